@@ -0,0 +1,88 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"musiclib/internal/app/httphandlers"
+	"musiclib/internal/app/services/songExporter"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const exportUsage = "usage: musiclib export [-format json|csv|ndjson] [-gzip] [-filter query] [-o file]"
+
+// runExportCommand runs `musiclib export` with its args. Songs are written to stdout or to the -o file, whose extension
+// gives the format unless -format is given; a ".gz" file is gzipped. The filter has the syntax of song list query params.
+func runExportCommand(ctx context.Context, db database, logger *zap.SugaredLogger, args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "", "json, csv or ndjson, by default it is taken from the output file extension or json")
+	gzipped := flags.Bool("gzip", false, "gzip the output")
+	filterQuery := flags.String("filter", "", "song list filter, like group=Muse&has_lyrics=true")
+	path := flags.String("o", "", "output file, stdout by default")
+	err = flags.Parse(args)
+	if err != nil || flags.NArg() != 0 {
+		return fmt.Errorf(exportUsage)
+	}
+
+	filter, err := httphandlers.ParseSongFilter(*filterQuery)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	name := *path
+	if strings.HasSuffix(name, ".gz") {
+		*gzipped = true
+		name = strings.TrimSuffix(name, ".gz")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(name), ".")
+	}
+	exportFormat := songExporter.FormatJSON
+	if *format != "" {
+		exportFormat, err = songExporter.ParseFormat(*format)
+		if err != nil {
+			return fmt.Errorf("%w, %v", err, exportUsage)
+		}
+	}
+
+	//open output, a partial file is removed
+	var out io.Writer = os.Stdout
+	if *path != "" {
+		file, createErr := os.Create(*path)
+		if createErr != nil {
+			return fmt.Errorf("failed to create output: %w", createErr)
+		}
+		defer func() {
+			closeErr := file.Close()
+			if err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(*path)
+			}
+		}()
+		out = file
+	}
+	if *gzipped {
+		gz := gzip.NewWriter(out)
+		defer func() {
+			closeErr := gz.Close()
+			if err == nil {
+				err = closeErr
+			}
+		}()
+		out = gz
+	}
+
+	exported, err := songExporter.NewExporter(db).Export(ctx, out, filter, exportFormat)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Exported %d songs", exported)
+	return nil
+}
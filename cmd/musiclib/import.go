@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/services/songImporter"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+const importUsage = "usage: musiclib import [-format csv|jsonl] [-enrich] [-batch size] file|-"
+
+// importActor is recorded as the author of imported songs.
+const importActor = "import"
+
+// runImportCommand runs `musiclib import` with its args. The format is taken from the file extension unless -format is given,
+// "-" reads stdin. Rows which were not created are printed with the reason.
+func runImportCommand(ctx context.Context, db database, logger *zap.SugaredLogger, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "", "csv or jsonl, by default it is taken from the file extension")
+	enrich := flags.Bool("enrich", false, "enrich new songs in background")
+	batch := flags.Int("batch", 0, "songs saved in one transaction")
+	err := flags.Parse(args)
+	if err != nil || flags.NArg() != 1 {
+		return fmt.Errorf(importUsage)
+	}
+	path := flags.Arg(0)
+
+	opts := songImporter.Options{Enrich: *enrich}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	opts.Format, err = songImporter.ParseFormat(*format)
+	if err != nil {
+		return fmt.Errorf("%w, %v", err, importUsage)
+	}
+
+	input := os.Stdin
+	if path != "-" {
+		input, err = os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		defer input.Close()
+	}
+
+	ctx = entities.WithAuditInfo(ctx, entities.AuditInfo{Actor: importActor})
+	importer := songImporter.NewImporter(db, logger, songImporter.Config{BatchSize: *batch})
+	report, err := importer.Import(ctx, input, opts)
+	printImportReport(os.Stdout, report)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Imported songs: %d created, %d skipped, %d failed", report.Created, report.Skipped, report.Failed)
+	return nil
+}
+
+// printImportReport prints rows which were skipped or failed.
+func printImportReport(out io.Writer, report entities.ImportReport) {
+	if report.Skipped+report.Failed == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tSTATUS\tGROUP\tSONG\tREASON")
+	for _, row := range report.Rows {
+		if row.Status == entities.ImportCreated {
+			continue
+		}
+		reason := row.Message
+		if row.Field != "" {
+			reason = row.Field + ": " + reason
+		}
+		fmt.Fprintf(w, "%d\t%v\t%v\t%v\t%v\n", row.Row, row.Status, row.Group, row.Song, reason)
+	}
+	w.Flush()
+}
@@ -1,19 +1,58 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
 	"log"
 	"musiclib/config"
 	_ "musiclib/docs"
 	"musiclib/internal/app/httphandlers"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/services/catalogueProvider"
+	"musiclib/internal/app/services/enrichmentWorkers"
 	"musiclib/internal/app/services/extraDataAPIProvider"
+	"musiclib/internal/app/services/extraDataCache"
+	"musiclib/internal/app/services/extraDataComposite"
+	"musiclib/internal/app/services/lyricsProvider"
+	"musiclib/internal/app/services/trashPurger"
+	"musiclib/pkg/databases/dbmigrations"
 	"musiclib/pkg/databases/gormpostgres"
+	"musiclib/pkg/databases/gormsqlite"
+	"musiclib/pkg/databases/memstorage"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
+// storage is everything the server needs from a storage backend.
+type storage interface {
+	requiredinterfaces.SongStorage
+	requiredinterfaces.EnrichmentJobStorage
+	requiredinterfaces.ExtraDataCacheStorage
+	requiredinterfaces.TrashStorage
+}
+
+// database is a storage backend in a database, it is migrated and used by commands.
+type database interface {
+	storage
+	MigrateUp(ctx context.Context) ([]dbmigrations.Status, error)
+	MigrateDown(ctx context.Context, steps int) ([]dbmigrations.Status, error)
+	MigrationStatus(ctx context.Context) ([]dbmigrations.Status, error)
+	UnparsedReleaseDates(ctx context.Context) (map[uint64]string, error)
+}
+
 func main() {
-	conf := config.Configure()
+	conf, err := config.Configure()
+	if err != nil {
+		log.Fatalf("Failed to read config, err: %v", err)
+	}
 
 	//set logger
 	logConf := zap.NewProductionConfig()
@@ -31,24 +70,174 @@ func main() {
 	sugar.Infof("Sugar logger was created with log level `%v`", logLevel)
 
 	//set storage
-	storage, err := gormpostgres.NewGormDB(conf.DBConnectionString)
-	if err != nil {
-		sugar.Fatalf("Failed to connect to database, err: %v", err)
+	var storage storage
+	var db database
+	switch conf.Storage {
+	case config.StorageMemory:
+		storage = memstorage.NewMemStorage()
+		sugar.Warnf("Songs are kept in memory, they will be lost on restart")
+	case config.StorageDatabase:
+		db, err = openDatabase(conf.DBConnectionString)
+		if err != nil {
+			sugar.Fatalf("Failed to connect to database, err: %v", err)
+		}
+		storage = db
 	}
-	err = storage.Migrate()
-	if err != nil {
-		sugar.Fatalf("Failed to migrate database, err: %v", err)
+
+	//`musiclib migrate|import|export ...` runs the command instead of the server, commands work with the database only
+	if len(os.Args) > 1 {
+		if db == nil {
+			sugar.Fatalf("Command `%v` needs STORAGE=%v", os.Args[1], config.StorageDatabase)
+		}
+		switch os.Args[1] {
+		case "migrate":
+			err = runMigrateCommand(context.Background(), db, sugar, os.Args[2:])
+			if err != nil {
+				sugar.Fatalf("Failed to migrate database, err: %v", err)
+			}
+		case "import":
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			err = runImportCommand(ctx, db, sugar, os.Args[2:])
+			stop()
+			if err != nil {
+				sugar.Fatalf("Failed to import songs, err: %v", err)
+			}
+		case "export":
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			err = runExportCommand(ctx, db, sugar, os.Args[2:])
+			stop()
+			if err != nil {
+				sugar.Fatalf("Failed to export songs, err: %v", err)
+			}
+		default:
+			sugar.Fatalf("Unknown command `%v`, %v, %v or %v", os.Args[1], migrateUsage, importUsage, exportUsage)
+		}
+		return
+	}
+	if db != nil && conf.MigrateOnStart {
+		err = migrateUp(context.Background(), db, sugar)
+		if err != nil {
+			sugar.Fatalf("Failed to migrate database, err: %v", err)
+		}
 	}
 
 	//set extra data provider
-	provider := extraDataAPIProvider.NewExtraDataAPIProvider(conf.ExtraDataAPIAddress)
+	provider, cache, err := buildExtraDataProvider(conf, storage, sugar)
+	if err != nil {
+		sugar.Fatalf("Failed to set extra data provider, err: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//run enrichment workers, they also serve re-triggered songs in the sync mode
+	pool := enrichmentWorkers.NewPool(storage, provider, sugar, enrichmentWorkers.Config{
+		Workers:      conf.EnrichmentWorkers,
+		MaxAttempts:  conf.EnrichmentMaxAttempts,
+		PollInterval: conf.EnrichmentPollInterval,
+	})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pool.Run(ctx)
+	}()
+	sugar.Infof("Started %v enrichment workers, enrichment mode is `%v`", conf.EnrichmentWorkers, conf.EnrichmentMode)
+
+	//purge the trash
+	purger := trashPurger.NewPurger(storage, sugar, trashPurger.Config{
+		Retention: conf.TrashRetention,
+		Interval:  conf.TrashPurgeInterval,
+	})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		purger.Run(ctx)
+	}()
+
+	//purge expired entries of the persistent extra data cache
+	if cache != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Run(ctx)
+		}()
+	}
 
 	//build and run server:
-	r := httphandlers.NewHTTPRouter(sugar, storage, provider)
+	r := httphandlers.NewHTTPRouter(sugar, storage, provider, conf.EnrichmentMode == config.EnrichmentAsync)
 	r.Handle("/swagger/*", httpSwagger.WrapHandler)
+	r.Handle("/debug/vars", expvar.Handler())
+	server := &http.Server{Addr: conf.ServerAddress, Handler: r}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
 	sugar.Infof("Starting an HTTP server on address `%v`...", conf.ServerAddress)
-	err = http.ListenAndServe(conf.ServerAddress, r)
-	if err != nil {
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		sugar.Fatalf("Failed to start HTTP server, err: %v", err)
 	}
+
+	wg.Wait()
+	sugar.Infof("Server stopped")
+}
+
+// openDatabase opens a SQLite database for a connection string with gormsqlite.Scheme and a Postgres one otherwise.
+func openDatabase(dsn string) (database, error) {
+	if strings.HasPrefix(dsn, gormsqlite.Scheme) {
+		return gormsqlite.NewGormDB(dsn)
+	}
+	return gormpostgres.NewGormDB(dsn)
+}
+
+// buildExtraDataProvider merges configured extra data sources. Answers of the API are cached,
+// the cache is returned too, it is nil if the API is not a source.
+func buildExtraDataProvider(conf config.Config, storage storage, logger *zap.SugaredLogger) (*extraDataComposite.Composite, *extraDataCache.Cache, error) {
+	var sources []extraDataComposite.Source
+	var cache *extraDataCache.Cache
+	for _, name := range conf.ExtraDataSources {
+		var provider requiredinterfaces.ExtraDataProvider
+		var fields []string
+		switch name {
+		case config.SourceAPI:
+			apiProvider := extraDataAPIProvider.NewExtraDataAPIProvider(conf.ExtraDataAPIAddress, extraDataAPIProvider.Config{
+				Client:           &http.Client{},
+				Timeout:          conf.ExtraDataAPITimeout,
+				MaxRetries:       conf.ExtraDataAPIRetries,
+				BreakerThreshold: conf.ExtraDataAPIBreakerThreshold,
+				BreakerCooldown:  conf.ExtraDataAPIBreakerCooldown,
+			})
+			var cacheStorage requiredinterfaces.ExtraDataCacheStorage
+			if conf.ExtraDataCachePersistent {
+				cacheStorage = storage
+			}
+			cache = extraDataCache.NewCache(apiProvider, cacheStorage, logger, extraDataCache.Config{
+				Size:          conf.ExtraDataCacheSize,
+				TTL:           conf.ExtraDataCacheTTL,
+				NegativeTTL:   conf.ExtraDataCacheNegativeTTL,
+				PurgeInterval: conf.ExtraDataCachePurgeInterval,
+			})
+			provider = cache
+		case config.SourceCatalogue:
+			catalogue, err := catalogueProvider.NewCatalogueProvider(conf.CatalogueFile)
+			if err != nil {
+				return nil, nil, err
+			}
+			provider = catalogue
+		case config.SourceLyrics:
+			provider = lyricsProvider.NewLyricsProvider(conf.LyricsAPIAddress, &http.Client{}, conf.ExtraDataAPITimeout)
+			fields = lyricsProvider.Fields
+		}
+		sources = append(sources, extraDataComposite.Source{Name: name, Provider: provider, Fields: fields})
+	}
+
+	composite, err := extraDataComposite.NewComposite(sources, extraDataComposite.Config{
+		Precedence: conf.ExtraDataPrecedence,
+		Parallel:   conf.ExtraDataParallel,
+	})
+	return composite, cache, err
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"maps"
+	"musiclib/pkg/databases/dbmigrations"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: musiclib migrate up|down [steps]|status"
+
+// releaseDatesMigration is the name of the migration which parses free-form release dates.
+const releaseDatesMigration = "release_dates"
+
+// runMigrateCommand runs `musiclib migrate` with its args: "up", "down" with an optional number of steps (1 by default) or "status".
+func runMigrateCommand(ctx context.Context, db database, logger *zap.SugaredLogger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		return migrateUp(ctx, db, logger)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive integer, %v", migrateUsage)
+			}
+		}
+		rolledBack, err := db.MigrateDown(ctx, steps)
+		for _, m := range rolledBack {
+			logger.Infof("Rolled back migration %04d `%v`", m.Version, m.Name)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			logger.Infof("No migrations to roll back")
+		}
+		return err
+	case "status":
+		statuses, err := db.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%v\t%v\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("unknown migrate command `%v`, %v", args[0], migrateUsage)
+}
+
+// migrateUp applies pending migrations. If release dates were parsed by them, dates which could not be
+// converted are reported by song IDs.
+func migrateUp(ctx context.Context, db database, logger *zap.SugaredLogger) error {
+	applied, err := db.MigrateUp(ctx)
+	for _, m := range applied {
+		logger.Infof("Applied migration %04d `%v`", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		logger.Infof("Database schema is up to date")
+	}
+
+	//dates are reported once, when they are parsed, and not on every restart
+	if !slices.ContainsFunc(applied, func(m dbmigrations.Status) bool { return m.Name == releaseDatesMigration }) {
+		return nil
+	}
+	unparsedDates, err := db.UnparsedReleaseDates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get unparsed release dates: %w", err)
+	}
+	for _, id := range slices.Sorted(maps.Keys(unparsedDates)) {
+		logger.Warnf("Release date `%v` of song %d could not be parsed, it is kept as is", unparsedDates[id], id)
+	}
+	return nil
+}
@@ -1,22 +1,270 @@
 package config
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of extra data sources, see Config.ExtraDataSources.
+const (
+	SourceAPI       = "api"
+	SourceCatalogue = "catalogue"
+	SourceLyrics    = "lyrics"
+)
+
+// Storage backends, see Config.Storage.
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
+// Enrichment modes, see Config.EnrichmentMode.
+const (
+	EnrichmentAsync = "async"
+	EnrichmentSync  = "sync"
+)
 
 type Config struct {
 	ServerAddress       string
 	DBConnectionString  string
 	ExtraDataAPIAddress string
 	LogLevel            string
+	// Storage is "database" (default) to keep songs in the database of DBConnectionString, a Postgres DSN
+	// or a SQLite file like sqlite://musiclib.db, or "memory" to keep them in memory for local runs, they are lost on restart.
+	Storage string
+	// MigrateOnStart applies pending migrations before serving, otherwise they are applied by `musiclib migrate up`.
+	MigrateOnStart bool
+
+	ExtraDataAPITimeout          time.Duration
+	ExtraDataAPIRetries          int
+	ExtraDataAPIBreakerThreshold int
+	ExtraDataAPIBreakerCooldown  time.Duration
+
+	ExtraDataCacheSize        int
+	ExtraDataCacheTTL         time.Duration
+	ExtraDataCacheNegativeTTL time.Duration
+	// ExtraDataCachePersistent also keeps the cache in the database, so it survives restarts.
+	// Expired entries are removed from the database every ExtraDataCachePurgeInterval.
+	ExtraDataCachePersistent    bool
+	ExtraDataCachePurgeInterval time.Duration
+
+	// ExtraDataSources are names of extra data sources in priority order: "api", "catalogue" and "lyrics".
+	ExtraDataSources []string
+	// ExtraDataPrecedence lists sources per field, like "text=lyrics,api;release_date=catalogue".
+	ExtraDataPrecedence map[string][]string
+	ExtraDataParallel   bool
+	CatalogueFile       string
+	LyricsAPIAddress    string
+
+	// EnrichmentMode is "async" (default) to enrich new songs by background workers or "sync" to do it in the request.
+	EnrichmentMode         string
+	EnrichmentWorkers      int
+	EnrichmentMaxAttempts  int
+	EnrichmentPollInterval time.Duration
+
+	// TrashRetention is how long removed songs can be restored before they are purged every TrashPurgeInterval.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
 }
 
 // Configure reads environmental variables and returns them in the "Config" struct.
-func Configure() Config {
+func Configure() (Config, error) {
 	conf := Config{}
+	var err error
 
 	conf.ServerAddress = os.Getenv("SERVER_ADDRESS")
 	conf.DBConnectionString = os.Getenv("DB_CONNECTION_STRING")
 	conf.ExtraDataAPIAddress = os.Getenv("EXTRA_DATA_API_ADDRESS")
 	conf.LogLevel = os.Getenv("LOG_LEVEL")
 
-	return conf
+	conf.Storage = os.Getenv("STORAGE")
+	if conf.Storage == "" {
+		conf.Storage = StorageDatabase
+	}
+	if conf.Storage != StorageDatabase && conf.Storage != StorageMemory {
+		return Config{}, fmt.Errorf("STORAGE must be `%v` or `%v`, got `%v`", StorageDatabase, StorageMemory, conf.Storage)
+	}
+	conf.MigrateOnStart, err = boolEnv("MIGRATE_ON_START", true)
+	if err != nil {
+		return Config{}, err
+	}
+
+	conf.ExtraDataAPITimeout, err = durationEnv("EXTRA_DATA_API_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataAPIRetries, err = nonNegativeIntEnv("EXTRA_DATA_API_RETRIES", 2)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataAPIBreakerThreshold, err = intEnv("EXTRA_DATA_API_BREAKER_THRESHOLD", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataAPIBreakerCooldown, err = durationEnv("EXTRA_DATA_API_BREAKER_COOLDOWN", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+
+	conf.ExtraDataCacheSize, err = intEnv("EXTRA_DATA_CACHE_SIZE", 1000)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataCacheTTL, err = durationEnv("EXTRA_DATA_CACHE_TTL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataCacheNegativeTTL, err = durationEnv("EXTRA_DATA_CACHE_NEGATIVE_TTL", time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataCachePersistent, err = boolEnv("EXTRA_DATA_CACHE_PERSISTENT", false)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataCachePurgeInterval, err = durationEnv("EXTRA_DATA_CACHE_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	conf.ExtraDataSources = listEnv("EXTRA_DATA_SOURCES", []string{SourceAPI})
+	for _, source := range conf.ExtraDataSources {
+		if source != SourceAPI && source != SourceCatalogue && source != SourceLyrics {
+			return Config{}, fmt.Errorf("EXTRA_DATA_SOURCES has unknown source `%v`", source)
+		}
+	}
+	conf.ExtraDataPrecedence, err = precedenceEnv("EXTRA_DATA_PRECEDENCE")
+	if err != nil {
+		return Config{}, err
+	}
+	conf.ExtraDataParallel, err = boolEnv("EXTRA_DATA_PARALLEL", false)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.CatalogueFile = os.Getenv("CATALOGUE_FILE")
+	conf.LyricsAPIAddress = os.Getenv("LYRICS_API_ADDRESS")
+
+	conf.EnrichmentMode = os.Getenv("ENRICHMENT_MODE")
+	if conf.EnrichmentMode == "" {
+		conf.EnrichmentMode = EnrichmentAsync
+	}
+	if conf.EnrichmentMode != EnrichmentAsync && conf.EnrichmentMode != EnrichmentSync {
+		return Config{}, fmt.Errorf("ENRICHMENT_MODE must be `%v` or `%v`, got `%v`", EnrichmentAsync, EnrichmentSync, conf.EnrichmentMode)
+	}
+	conf.EnrichmentWorkers, err = intEnv("ENRICHMENT_WORKERS", 4)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.EnrichmentMaxAttempts, err = intEnv("ENRICHMENT_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.EnrichmentPollInterval, err = durationEnv("ENRICHMENT_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+
+	conf.TrashRetention, err = durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	conf.TrashPurgeInterval, err = durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return conf, nil
+}
+
+// intEnv returns a positive integer from the environmental variable or def if it is not set.
+func intEnv(name string, def int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%v must be a positive integer, got `%v`", name, value)
+	}
+	return n, nil
+}
+
+// nonNegativeIntEnv returns a non-negative integer from the environmental variable or def if it is not set.
+func nonNegativeIntEnv(name string, def int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%v must be a non-negative integer, got `%v`", name, value)
+	}
+	return n, nil
+}
+
+// durationEnv returns a positive duration (like "1s" or "500ms") from the environmental variable or def if it is not set.
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%v must be a positive duration, got `%v`", name, value)
+	}
+	return d, nil
+}
+
+// boolEnv returns a boolean (like "true" or "0") from the environmental variable or def if it is not set.
+func boolEnv(name string, def bool) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%v must be a boolean, got `%v`", name, value)
+	}
+	return b, nil
+}
+
+// listEnv returns comma separated values of the environmental variable or def if it is not set.
+func listEnv(name string, def []string) []string {
+	list := splitList(os.Getenv(name))
+	if len(list) == 0 {
+		return def
+	}
+	return list
+}
+
+// precedenceEnv parses a precedence of sources per field: "field=source1,source2;field2=source3".
+func precedenceEnv(name string) (map[string][]string, error) {
+	value := os.Getenv(name)
+	precedence := map[string][]string{}
+	for _, rule := range strings.Split(value, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		field, sources, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("%v must look like `field=source1,source2;field2=source3`, got `%v`", name, value)
+		}
+		precedence[strings.TrimSpace(field)] = splitList(sources)
+	}
+	return precedence, nil
+}
+
+// splitList splits comma separated values and drops empty ones.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
@@ -15,213 +15,3363 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/lyrics": {
+        "/albums": {
             "get": {
-                "description": "Retrieves the requested couplet number from the specified song.",
+                "description": "filtration and pagination are supported",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
-                "summary": "Returns a specific couplet from a song's lyrics",
+                "summary": "Returns list of albums",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the album title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the group name",
+                        "name": "group",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "song_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Release date",
+                        "name": "release_date",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Couplet Number",
-                        "name": "couplet_num",
-                        "in": "query",
-                        "required": true
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Couplet text",
+                        "description": "Albums list",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Album"
+                            }
                         }
                     },
                     "204": {
-                        "description": "Requested couplet number is bigger than the number of couplets"
+                        "description": "No albums found"
                     },
                     "400": {
-                        "description": "Bad request"
-                    },
-                    "404": {
-                        "description": "Song not found"
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     },
                     "500": {
-                        "description": "Internal server error"
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     }
                 }
-            }
-        },
-        "/song": {
-            "put": {
-                "description": "Updates current song by id.",
+            },
+            "post": {
+                "description": "Creates new album. The group is found by its name or alias and created if it does not exist yet.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
-                "summary": "Updates the song",
+                "summary": "Creates new album",
                 "parameters": [
                     {
-                        "description": "JSON song data",
-                        "name": "song",
+                        "description": "JSON album data",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entities.Song"
+                            "$ref": "#/definitions/entities.Album"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Album ID",
+                        "schema": {
+                            "$ref": "#/definitions/entities.IDMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
                         }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/albums/{id}": {
+            "get": {
+                "description": "Returns album by id.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the album",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success"
+                        "description": "Album",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Album"
+                        }
                     },
                     "400": {
-                        "description": "Bad request"
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     },
                     "404": {
-                        "description": "Not found"
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     },
                     "500": {
-                        "description": "Internal server error"
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     }
                 }
             },
-            "post": {
-                "description": "Creates new song, asks different service for an additional data (release date, text and link).",
+            "put": {
+                "description": "Updates album by id, empty fields are left unchanged.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
-                "summary": "Creates new song",
+                "summary": "Updates the album",
                 "parameters": [
                     {
-                        "description": "JSON song data",
-                        "name": "song",
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON album data",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/httphandlers.SongMessage"
+                            "$ref": "#/definitions/entities.Album"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Song ID",
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
-                            "$ref": "#/definitions/entities.IDMessage"
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
                         }
                     },
-                    "400": {
-                        "description": "Bad request"
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     },
                     "500": {
-                        "description": "Internal server error"
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes current song by id.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Deletes album by id. Songs of the album are kept.",
                 "produces": [
                     "text/plain"
                 ],
-                "summary": "Deletes the song",
+                "summary": "Deletes the album",
                 "parameters": [
                     {
-                        "description": "JSON song ID",
-                        "name": "song",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entities.IDMessage"
-                        }
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "Success"
                     },
                     "400": {
-                        "description": "Bad request"
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     },
                     "500": {
-                        "description": "Internal server error"
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     }
                 }
             }
         },
-        "/songs": {
-            "post": {
-                "description": "filtration and pagination are supported",
-                "consumes": [
-                    "application/json"
-                ],
+        "/albums/{id}/tracks": {
+            "get": {
+                "description": "Returns songs of the album ordered by disc and track numbers.",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Returns list of songs",
+                "summary": "Returns the album tracklist",
                 "parameters": [
                     {
-                        "description": "Filter params",
-                        "name": "filter",
-                        "in": "body",
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tracklist",
                         "schema": {
-                            "$ref": "#/definitions/httphandlers.FilterRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
                         }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/albums": {
+            "get": {
+                "description": "filtration and pagination are supported",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns list of albums",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the album title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Release date",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Songs list",
+                    "200": {
+                        "description": "Albums list",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/httphandlers.FilterRequest"
+                                "$ref": "#/definitions/entities.Album"
                             }
                         }
                     },
                     "204": {
-                        "description": "No songs found"
+                        "description": "No albums found"
                     },
                     "400": {
-                        "description": "Bad request"
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     },
                     "500": {
-                        "description": "Internal server error"
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "entities.IDMessage": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                }
-            }
+            },
+            "post": {
+                "description": "Creates new album. The group is found by its name or alias and created if it does not exist yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates new album",
+                "parameters": [
+                    {
+                        "description": "JSON album data",
+                        "name": "album",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Album"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Album ID",
+                        "schema": {
+                            "$ref": "#/definitions/entities.IDMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/albums/{id}": {
+            "get": {
+                "description": "Returns album by id.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the album",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Album",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates album by id, empty fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Updates the album",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON album data",
+                        "name": "album",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Album"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes album by id. Songs of the album are kept.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Deletes the album",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/albums/{id}/tracks": {
+            "get": {
+                "description": "Returns songs of the album ordered by disc and track numbers.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the album tracklist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tracklist",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/duplicates": {
+            "get": {
+                "description": "Sets of songs of a group whose names are similar after ignoring case, spaces and diacritics,\nlike \"Starlight\" and \"Starlight (Live)\". Songs created before duplicates were refused may have the same name,\nthey have a similarity of 1. Every set suggests the song with the richest metadata to keep,\nthe others can be merged into it by POST /api/v1/songs/merge. Sets go in the order of their smallest song IDs.\nAn empty list is returned if there are no duplicates.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns likely duplicate songs",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Minimal trigram similarity of names above 0 and up to 1, 0.5 by default",
+                        "name": "similarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sets of likely duplicates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.DuplicateSongs"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups": {
+            "get": {
+                "description": "filtration and pagination are supported",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns list of groups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the group name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year the group was formed",
+                        "name": "formed_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Groups list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Group"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No groups found"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new group. Group names are unique regardless of case and extra spaces.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates new group",
+                "parameters": [
+                    {
+                        "description": "JSON group data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Group"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Group ID",
+                        "schema": {
+                            "$ref": "#/definitions/entities.IDMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group with the same name or alias already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{id}": {
+            "get": {
+                "description": "Returns group by id.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates group by id, empty fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Updates the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON group data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Group"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group with the same name or alias already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes group by id. Groups which still have songs or albums can not be deleted.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Deletes the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group still has songs or albums",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "description": "Full-text search, an empty list is returned if nothing was found.\nThe query supports \"quoted phrases\", ` + "`" + `or` + "`" + ` and ` + "`" + `-` + "`" + ` to exclude a word.\nEach result has a headline: the best matching couplet with matched words wrapped in ` + "`" + `\u003cmark\u003e\u003c/mark\u003e` + "`" + `.\nThe fuzzy mode finds songs by misspelled song or group names, the most similar go first.\nIf nothing matches the query exactly, similar names are suggested.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Searches songs by their names and lyrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "fulltext",
+                            "fuzzy"
+                        ],
+                        "type": "string",
+                        "description": "Search mode, fulltext by default",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal similarity of names from 0 to 1 for the fuzzy mode, 0.3 by default",
+                        "name": "similarity",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "simple",
+                            "english",
+                            "russian"
+                        ],
+                        "type": "string",
+                        "description": "Text search language, english by default",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Search the whole query as a phrase",
+                        "name": "phrase",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Found songs",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SearchMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs": {
+            "get": {
+                "description": "filtration, sorting and cursor pagination are supported, an empty list is returned if nothing was found.\nPass ` + "`" + `next_cursor` + "`" + ` or ` + "`" + `prev_cursor` + "`" + ` of a page as ` + "`" + `cursor` + "`" + ` to get the next or the previous page.\nText filters ignore case, ` + "`" + `contains` + "`" + ` is the default match mode.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns list of songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated song IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "How song name is matched",
+                        "name": "song_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "How group name is matched",
+                        "name": "group_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated group IDs, any of them matches",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Exact group names, any of them matches",
+                        "name": "groups",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Songs released on this date, month (2006-01) or year (2006)",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Songs released on this date or later, 2006-01-02, 02.01.2006, 2006-01 or 2006",
+                        "name": "release_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Songs released on this date or earlier, 2006-01-02, 02.01.2006, 2006-01 or 2006",
+                        "name": "release_date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) lyrics",
+                        "name": "has_lyrics",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) a link",
+                        "name": "has_link",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "song",
+                            "group",
+                            "release_date",
+                            "disc_number",
+                            "track_number",
+                            "-id",
+                            "-song",
+                            "-group",
+                            "-release_date",
+                            "-disc_number",
+                            "-track_number"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, ` + "`" + `-` + "`" + ` prefix for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all songs matching the filter",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset, can not be used with a cursor",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of songs",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongListMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new song. An additional data (release date, text and link) is asked from a different service\nin background, the song stays ` + "`" + `pending` + "`" + ` until it is received.\nIf the server is configured for synchronous enrichment, the data is asked before answering.\nThe group is found by its name or alias and created if it does not exist yet.\nNames which differ only in case, spaces and diacritics are the same song, a duplicate is refused with 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates new song",
+                "parameters": [
+                    {
+                        "description": "JSON song data",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Song ID and enrichment status",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongCreatedMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/export": {
+            "get": {
+                "description": "Streams all songs matching the filter in the order of IDs, filters are the same as of the song list.\nThe output is gzipped if the client accepts gzip. If the export fails in the middle, the output is cut.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "summary": "Exports songs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Output format, json by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated song IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "How song name is matched",
+                        "name": "song_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "contains",
+                            "prefix",
+                            "exact"
+                        ],
+                        "type": "string",
+                        "description": "How group name is matched",
+                        "name": "group_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated group IDs, any of them matches",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Exact group names, any of them matches",
+                        "name": "groups",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Songs released on this date, month (2006-01) or year (2006)",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Songs released on this date or later, 2006-01-02, 02.01.2006, 2006-01 or 2006",
+                        "name": "release_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Songs released on this date or earlier, 2006-01-02, 02.01.2006, 2006-01 or 2006",
+                        "name": "release_date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) lyrics",
+                        "name": "has_lyrics",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs with (true) or without (false) a link",
+                        "name": "has_link",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Songs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/import": {
+            "post": {
+                "description": "Streams songs from CSV with a header of field names or from JSON Lines and saves them in batches.\nEvery row is reported as created, skipped (the song of the same group and name is in the library already or earlier in the input) or failed.\nThe format is taken from the format parameter or else from Content-Type. With enrich=true extra data of new songs is asked in background.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Imports songs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "Input format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Enrich new songs",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Songs with the fields group, song, album_id, disc_number, track_number, release_date, text and link",
+                        "name": "songs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Outcome of every row",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "Unknown input format",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/merge": {
+            "post": {
+                "description": "Merges metadata of the duplicates into the song and moves the duplicates to the trash in one step.\nThe song keeps its name and takes the most precise release date and the longest lyrics of all of them,\na link and an album position are taken from the duplicates only if the song has none.\nDuplicates must be songs of the same group, GET /api/v1/duplicates suggests them.\nThe merge is recorded in the history of the song, the duplicates can be restored from the trash.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Merges duplicate songs",
+                "parameters": [
+                    {
+                        "description": "The song to keep and its duplicates",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongsMergeMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Merged song",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or duplicate not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "A duplicate is of another group",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{id}": {
+            "get": {
+                "description": "Returns song by id. The ETag header is the version of the song, it is given back in If-Match of updates.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the song the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Song"
+                        }
+                    },
+                    "304": {
+                        "description": "Song was not changed since the ETag in If-None-Match"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces all fields of the song a client can change, absent fields are cleared.\nThe song is moved to the group of group_id or, without it, of the group name.\nIf-Match must be the ETag of the song (or ` + "`" + `*` + "`" + ` to replace any version).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Replaces the song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the song",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON song data",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongReplaceMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed since the ETag in If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match is absent",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Moves the song to the trash, it can be restored by ` + "`" + `POST /api/v1/songs/{id}/restore` + "`" + ` until it is purged.\nIf-Match must be the ETag of the song (or ` + "`" + `*` + "`" + ` to remove any version).",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Deletes the song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the song",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed since the ETag in If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match is absent",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates song by id, absent and empty fields are left unchanged. id, version, enrichment_status and sources can not be changed.\nWith Content-Type application/merge-patch+json the body is an RFC 7396 merge patch: absent fields are left unchanged and null clears a field.\nIf-Match must be the ETag of the song (or ` + "`" + `*` + "`" + ` to update any version), so changes of others are not overwritten.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Updates the song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the song",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON song data",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongReplaceMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed since the ETag in If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match is absent",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{id}/enrichment": {
+            "post": {
+                "description": "Makes the song pending and schedules a new request of its additional data (release date, text and link).\nFields which are already filled are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Re-triggers enrichment of the song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Song ID and enrichment status",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongCreatedMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{id}/history": {
+            "get": {
+                "description": "Every change of the song is a revision with changed fields, the actor (` + "`" + `X-Actor` + "`" + ` header of the request) and the request ID.\nThe latest revisions go first, songs in the trash have a history too.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the change history of the song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revisions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.SongRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{id}/lyrics": {
+            "get": {
+                "description": "Retrieves the whole lyrics of the song or only the requested couplet.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Returns the song's lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Couplet Number, starting from 0",
+                        "name": "couplet",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lyrics or couplet text",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or couplet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{id}/restore": {
+            "post": {
+                "description": "Takes a removed song out of the trash, a pending song is enriched again.\nA song whose group has got a song of the same name meanwhile can not be restored.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Restores the song from the trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song is not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{id}/revert": {
+            "post": {
+                "description": "Brings the song back to its state after the revision, the revert is recorded as a new revision.\nA group which was removed since is found by its name again, a removed album is unset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Reverts the song to a revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.RevertMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reverted song",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash": {
+            "get": {
+                "description": "Songs in the trash can be restored until they are purged, the most recently removed go first.\nAn empty list is returned if the trash is empty.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns removed songs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Removed songs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.TrashedSong"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
+                "description": "filtration and pagination are supported",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns list of groups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the group name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year the group was formed",
+                        "name": "formed_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Groups list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Group"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No groups found"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new group. Group names are unique regardless of case and extra spaces.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates new group",
+                "parameters": [
+                    {
+                        "description": "JSON group data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Group"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Group ID",
+                        "schema": {
+                            "$ref": "#/definitions/entities.IDMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group with the same name or alias already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "description": "Returns group by id.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates group by id, empty fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Updates the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON group data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Group"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group with the same name or alias already exists",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes group by id. Groups which still have songs or albums can not be deleted.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Deletes the group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group still has songs or albums",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/lyrics": {
+            "get": {
+                "description": "Retrieves the requested couplet number from the specified song.",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Returns a specific couplet from a song's lyrics",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Couplet Number",
+                        "name": "couplet_num",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Couplet text",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "204": {
+                        "description": "Requested couplet number is bigger than the number of couplets"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/song": {
+            "put": {
+                "description": "Updates current song by id.\nOnly fields a client can change are taken from the body, empty ones are left unchanged.\nWith If-Match the song is updated only if its version is the ETag, the version in the body is ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Updates the song",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the song",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON song data",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongUpdateMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed since the ETag in If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new song. An additional data (release date, text and link) is asked from a different service\nin background, the song stays ` + "`" + `pending` + "`" + ` until it is received.\nIf the server is configured for synchronous enrichment, the data is asked before answering.\nThe group is found by its name or alias and created if it does not exist yet.\nNames which differ only in case, spaces and diacritics are the same song, a duplicate is refused with 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Creates new song",
+                "parameters": [
+                    {
+                        "description": "JSON song data",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Song ID and enrichment status",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.SongCreatedMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already has a song of the same name, Location points to it",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Request refers to missing content",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Moves current song by id to the trash, it can be restored until it is purged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Deletes the song",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the song",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON song ID",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.IDMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed since the ETag in If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "post": {
+                "description": "filtration, sorting and pagination are supported, limit is 50 by default and 500 at most",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Returns list of songs",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Filter params",
+                        "name": "filter",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.FilterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Songs list",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/httphandlers.FilterRequest"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No songs found"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage is unavailable, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage timed out, retry after Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/httphandlers.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entities.Album": {
+            "type": "object",
+            "properties": {
+                "cover_link": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.DuplicateSongs": {
+            "type": "object",
+            "properties": {
+                "keep_id": {
+                    "description": "KeepID is the song with the richest metadata, the others can be merged into it.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "similarity": {
+                    "description": "Similarity is the lowest similarity of names of the pairs which link the songs,\nnames which differ only in case, spaces and diacritics have a similarity of 1.",
+                    "type": "number",
+                    "example": 1
+                },
+                "songs": {
+                    "description": "Songs go in the order of IDs.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Song"
+                    }
+                }
+            }
+        },
+        "entities.EnrichmentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "enriched",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "EnrichmentPending",
+                "EnrichmentEnriched",
+                "EnrichmentFailed"
+            ]
+        },
+        "entities.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.Group": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "country": {
+                    "type": "string"
+                },
+                "formed_year": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.IDMessage": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ImportRowResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the invalid field of a failed row.",
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Message tells why the row was skipped or failed.",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "Row is the number of the record in the input starting from 1, a CSV header and blank lines are not counted.",
+                    "type": "integer"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entities.ImportStatus"
+                }
+            }
+        },
+        "entities.ImportStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "skipped",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ImportCreated",
+                "ImportSkipped",
+                "ImportFailed"
+            ]
+        },
+        "entities.MatchMode": {
+            "type": "string",
+            "enum": [
+                "contains",
+                "prefix",
+                "exact"
+            ],
+            "x-enum-varnames": [
+                "MatchContains",
+                "MatchPrefix",
+                "MatchExact"
+            ]
+        },
+        "entities.RevisionAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore",
+                "revert",
+                "merge"
+            ],
+            "x-enum-varnames": [
+                "RevisionCreate",
+                "RevisionUpdate",
+                "RevisionDelete",
+                "RevisionRestore",
+                "RevisionRevert",
+                "RevisionMerge"
+            ]
+        },
+        "entities.SearchResult": {
+            "type": "object",
+            "properties": {
+                "headline": {
+                    "description": "Headline is the best matching couplet with matched words wrapped in \u003cmark\u003e\u003c/mark\u003e, fuzzy search gives no headline.\nHTML of the lyrics is escaped, so the marks are the only tags.",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "Rank tells how well the song matches, a greater rank is better.",
+                    "type": "number"
+                },
+                "song": {
+                    "$ref": "#/definitions/entities.Song"
+                }
+            }
         },
         "entities.Song": {
             "type": "object",
             "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "enrichment_status": {
+                    "$ref": "#/definitions/entities.EnrichmentStatus"
+                },
                 "group": {
                     "type": "string"
                 },
+                "group_id": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -229,13 +3379,120 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "release_date": {
+                    "description": "ReleaseDate is given in ISO 8601 format with the known parts only, like 2006-01-02, 2006-01 or 2006.",
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "sources": {
+                    "description": "Sources maps an extra data field (release_date, text or link) to the source it was taken from.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version grows with every change of the song, it is given to clients as an ETag.",
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.SongRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/entities.RevisionAction"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "song": {
+                    "description": "Song is the state of the song after the change, a song is reverted to it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.Song"
+                        }
+                    ]
+                },
+                "song_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.TrashedSong": {
+            "type": "object",
+            "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "enrichment_status": {
+                    "$ref": "#/definitions/entities.EnrichmentStatus"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
                     "type": "string"
                 },
+                "release_date": {
+                    "description": "ReleaseDate is given in ISO 8601 format with the known parts only, like 2006-01-02, 2006-01 or 2006.",
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
                 "song": {
                     "type": "string"
                 },
+                "sources": {
+                    "description": "Sources maps an extra data field (release_date, text or link) to the source it was taken from.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "text": {
                     "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version grows with every change of the song, it is given to clients as an ETag.",
+                    "type": "integer"
                 }
             }
         },
@@ -245,22 +3502,284 @@ const docTemplate = `{
                 "filter": {
                     "$ref": "#/definitions/entities.Song"
                 },
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "group_match": {
+                    "enum": [
+                        "contains",
+                        "prefix",
+                        "exact"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.MatchMode"
+                        }
+                    ]
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "has_link": {
+                    "type": "boolean"
+                },
+                "has_lyrics": {
+                    "type": "boolean"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "limit": {
                     "type": "integer"
                 },
                 "offset": {
                     "type": "integer"
+                },
+                "release_date_from": {
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
+                "release_date_to": {
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
+                "song_match": {
+                    "enum": [
+                        "contains",
+                        "prefix",
+                        "exact"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.MatchMode"
+                        }
+                    ]
+                },
+                "sort": {
+                    "description": "Sort is a field name, \"-\" prefix gives a descending order.",
+                    "type": "string",
+                    "example": "-release_date"
+                }
+            }
+        },
+        "httphandlers.ProblemDetails": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "required_field"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "song name is empty"
+                },
+                "existing_id": {
+                    "description": "ExistingID is the song a new or changed song would duplicate, the Location header points to it.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "field": {
+                    "type": "string",
+                    "example": "song"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v1/songs"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "host/abcdef-000001"
+                },
+                "retryable": {
+                    "description": "Retryable tells that the same request may succeed later, the Retry-After header tells when.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "httphandlers.RevertMessage": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "httphandlers.SearchMessage": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.SearchResult"
+                    }
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "httphandlers.SongCreatedMessage": {
+            "type": "object",
+            "properties": {
+                "enrichment_status": {
+                    "$ref": "#/definitions/entities.EnrichmentStatus"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httphandlers.SongListMessage": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "songs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.Song"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
         "httphandlers.SongMessage": {
             "type": "object",
             "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httphandlers.SongReplaceMessage": {
+            "type": "object",
+            "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httphandlers.SongUpdateMessage": {
+            "type": "object",
+            "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
                 "group": {
                     "type": "string"
                 },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
                 "song": {
                     "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httphandlers.SongsMergeMessage": {
+            "type": "object",
+            "properties": {
+                "duplicate_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5,
+                        8
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         }
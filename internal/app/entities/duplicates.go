@@ -0,0 +1,211 @@
+package entities
+
+import (
+	"cmp"
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// DuplicateSimilarity is the trigram similarity names of songs of a group need to be reported as likely duplicates.
+const DuplicateSimilarity = 0.5
+
+// NormalizeSongName returns a key used to compare song names: "Hysteria", "HYSTERIA " and "Hystéria" are the same song.
+// Combining diacritical marks (U+0300 to U+036F) of decomposed letters are dropped, so "й" is compared as "и".
+func NormalizeSongName(name string) string {
+	decomposed := norm.NFD.String(NormalizeGroupName(name))
+	stripped := strings.Map(func(r rune) rune {
+		if r >= 0x300 && r <= 0x36f {
+			return -1
+		}
+		return r
+	}, decomposed)
+	return norm.NFC.String(stripped)
+}
+
+// IsSameSong tells whether a song of the group named name is the song under NormalizeSongName.
+// Storages check duplicates only on changes which make a song another one, so flagged legacy duplicates stay editable.
+func (s Song) IsSameSong(groupID uint64, name string) bool {
+	return s.GroupID == groupID && NormalizeSongName(s.Song) == NormalizeSongName(name)
+}
+
+// DuplicateSongError tells that the group has a song of the same normalized name already, ID is that song.
+type DuplicateSongError struct {
+	ID uint64
+}
+
+func (e DuplicateSongError) Error() string {
+	return fmt.Sprintf("song %d of the group has the same name", e.ID)
+}
+
+// DuplicatePair is two songs of a group with similar names, FirstID is the smaller one.
+type DuplicatePair struct {
+	FirstID    uint64
+	SecondID   uint64
+	Similarity float64
+}
+
+// DuplicateSet is a set of songs linked by pairs of similar names, their IDs go in order.
+type DuplicateSet struct {
+	IDs []uint64
+	// Similarity is the lowest similarity of the pairs which link the songs.
+	Similarity float64
+}
+
+// DuplicateSongs are likely duplicates: songs of a group whose names are similar directly or through other songs of the set.
+type DuplicateSongs struct {
+	// Similarity is the lowest similarity of names of the pairs which link the songs,
+	// names which differ only in case, spaces and diacritics have a similarity of 1.
+	Similarity float64 `json:"similarity" example:"1"`
+	// KeepID is the song with the richest metadata, the others can be merged into it.
+	KeepID uint64 `json:"keep_id" example:"3"`
+	// Songs go in the order of IDs.
+	Songs []Song `json:"songs"`
+}
+
+// ClusterDuplicates joins pairs which share songs into sets. Sets go in the order of their first IDs.
+func ClusterDuplicates(pairs []DuplicatePair) []DuplicateSet {
+	// every set is a tree of songs, its root is the smallest ID
+	parent := map[uint64]uint64{}
+	var find func(id uint64) uint64
+	find = func(id uint64) uint64 {
+		if p, ok := parent[id]; ok && p != id {
+			parent[id] = find(p)
+			return parent[id]
+		}
+		return id
+	}
+	for _, pair := range pairs {
+		a, b := find(pair.FirstID), find(pair.SecondID)
+		if a != b {
+			parent[max(a, b)] = min(a, b)
+		}
+	}
+
+	sets := map[uint64]*DuplicateSet{}
+	for _, pair := range pairs {
+		root := find(pair.FirstID)
+		set, ok := sets[root]
+		if !ok {
+			set = &DuplicateSet{Similarity: pair.Similarity}
+			sets[root] = set
+		}
+		set.Similarity = min(set.Similarity, pair.Similarity)
+		for _, id := range []uint64{pair.FirstID, pair.SecondID} {
+			if !slices.Contains(set.IDs, id) {
+				set.IDs = append(set.IDs, id)
+			}
+		}
+	}
+
+	result := make([]DuplicateSet, 0, len(sets))
+	for _, set := range sets {
+		slices.Sort(set.IDs)
+		result = append(result, *set)
+	}
+	slices.SortFunc(result, func(a, b DuplicateSet) int {
+		return cmp.Compare(a.IDs[0], b.IDs[0])
+	})
+	return result
+}
+
+// NewDuplicateSongs returns likely duplicates of the songs in the order of IDs and suggests the richest one to keep.
+func NewDuplicateSongs(songs []Song, similarity float64) DuplicateSongs {
+	songs = slices.Clone(songs)
+	slices.SortFunc(songs, func(a, b Song) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	duplicates := DuplicateSongs{Similarity: similarity, Songs: songs}
+	best := -1
+	for _, song := range songs {
+		if richness := song.richness(); richness > best {
+			duplicates.KeepID, best = song.ID, richness
+		}
+	}
+	return duplicates
+}
+
+// richness counts known metadata of the song, a more precise release date counts more.
+func (s Song) richness() int {
+	richness := releaseDateRank(s)
+	for _, known := range []bool{s.Text != "", s.Link != "", s.AlbumID != nil} {
+		if known {
+			richness++
+		}
+	}
+	return richness
+}
+
+// releaseDateRank orders release dates by how much is known: no date, a date which could not be parsed,
+// a year, a month and a day.
+func releaseDateRank(s Song) int {
+	if s.ReleaseDate == nil {
+		if s.ReleaseDateRaw == "" {
+			return 0
+		}
+		return 1
+	}
+	switch s.ReleaseDate.Precision {
+	case PrecisionYear:
+		return 2
+	case PrecisionMonth:
+		return 3
+	}
+	return 4
+}
+
+// MergeDuplicates returns the song with the richest metadata of it and its duplicates: the most precise release date,
+// the longest text and the link and the album position of the song or, if it has none, of the first duplicate which has.
+// Sources of values taken from a duplicate come with them. The name, the group and the version of the song are kept.
+func (s Song) MergeDuplicates(duplicates []Song) Song {
+	merged := copySong(s)
+	for _, d := range duplicates {
+		if releaseDateRank(d) > releaseDateRank(merged) {
+			merged.ReleaseDate, merged.ReleaseDateRaw = d.ReleaseDate, d.ReleaseDateRaw
+			merged.takeSource(d, FieldReleaseDate)
+		}
+		if utf8.RuneCountInString(d.Text) > utf8.RuneCountInString(merged.Text) {
+			merged.Text = d.Text
+			merged.takeSource(d, FieldText)
+		}
+		if merged.Link == "" && d.Link != "" {
+			merged.Link = d.Link
+			merged.takeSource(d, FieldLink)
+		}
+		if merged.AlbumID == nil && d.AlbumID != nil {
+			albumID := *d.AlbumID
+			merged.AlbumID, merged.DiscNumber, merged.TrackNumber = &albumID, d.DiscNumber, d.TrackNumber
+		}
+	}
+	return copySong(merged)
+}
+
+// takeSource sets the source of the field to the one of the duplicate the value was taken from.
+func (s *Song) takeSource(duplicate Song, field string) {
+	source, ok := duplicate.Sources[field]
+	if !ok {
+		delete(s.Sources, field)
+		return
+	}
+	if s.Sources == nil {
+		s.Sources = map[string]string{}
+	}
+	s.Sources[field] = source
+}
+
+// copySong returns a song which shares no pointers and maps with the given one.
+func copySong(song Song) Song {
+	if song.AlbumID != nil {
+		albumID := *song.AlbumID
+		song.AlbumID = &albumID
+	}
+	if song.ReleaseDate != nil {
+		date := *song.ReleaseDate
+		song.ReleaseDate = &date
+	}
+	song.Sources = maps.Clone(song.Sources)
+	return song
+}
@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeSongName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Hysteria", expected: "hysteria"},
+		{name: "  HYSTERIA\t ", expected: "hysteria"},
+		{name: "Hystéria", expected: "hysteria"},
+		{name: "Knights  of\nCydonia", expected: "knights of cydonia"},
+		{name: "Ёлка Мой", expected: "елка мои"},
+		{name: "Straße", expected: "straße"},
+		{name: " ", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeSongName(tt.name))
+		})
+	}
+
+	assert.Equal(t, SongKey("Muse", "Hysteria"), SongKey(" MUSE", "Hystéria"))
+}
+
+func TestClusterDuplicates(t *testing.T) {
+	sets := ClusterDuplicates([]DuplicatePair{
+		{FirstID: 7, SecondID: 9, Similarity: 0.6},
+		{FirstID: 2, SecondID: 5, Similarity: 1},
+		{FirstID: 3, SecondID: 7, Similarity: 0.8},
+		{FirstID: 5, SecondID: 8, Similarity: 0.7},
+	})
+
+	assert.Equal(t, []DuplicateSet{
+		{IDs: []uint64{2, 5, 8}, Similarity: 0.7},
+		{IDs: []uint64{3, 7, 9}, Similarity: 0.6},
+	}, sets)
+	assert.Empty(t, ClusterDuplicates(nil))
+}
+
+func TestNewDuplicateSongs(t *testing.T) {
+	albumID := uint64(7)
+	duplicates := NewDuplicateSongs([]Song{
+		{ID: 5, Song: "Hysteria", Text: "It's bugging me", Link: "https://example.com/hysteria"},
+		{ID: 2, Song: "HYSTERIA", ReleaseDate: MustParseReleaseDate("2003")},
+		{ID: 9, Song: "Hystéria", AlbumID: &albumID, ReleaseDate: MustParseReleaseDate("2003-12-01")},
+	}, 1)
+
+	assert.Equal(t, 1.0, duplicates.Similarity)
+	assert.Equal(t, []string{"HYSTERIA", "Hysteria", "Hystéria"}, songNames(duplicates.Songs))
+	//a day and an album outweigh a text and a link, ties go to the smaller ID
+	assert.Equal(t, uint64(9), duplicates.KeepID)
+
+	duplicates = NewDuplicateSongs([]Song{{ID: 5, Song: "Hysteria"}, {ID: 2, Song: "HYSTERIA"}}, 1)
+	assert.Equal(t, uint64(2), duplicates.KeepID)
+}
+
+func TestSong_MergeDuplicates(t *testing.T) {
+	albumID, otherAlbumID := uint64(7), uint64(8)
+	song := Song{
+		ID:          1,
+		Song:        "Hysteria",
+		GroupID:     2,
+		ReleaseDate: MustParseReleaseDate("2003"),
+		Text:        "It's bugging me",
+		Sources:     map[string]string{FieldReleaseDate: "api", FieldText: "api"},
+		Version:     3,
+	}
+
+	merged := song.MergeDuplicates([]Song{
+		{ID: 4, Song: "HYSTERIA", ReleaseDate: MustParseReleaseDate("2003-12-01"), Text: "It's", Link: "https://example.com/hysteria",
+			Sources: map[string]string{FieldReleaseDate: "catalogue", FieldLink: "api"}},
+		{ID: 5, Song: "Hystéria", AlbumID: &albumID, TrackNumber: 8, Text: "It's bugging me\n\nGrating me"},
+		{ID: 6, Song: "Hysteria", AlbumID: &otherAlbumID, TrackNumber: 1, Link: "https://example.com/other"},
+	})
+
+	assert.Equal(t, Song{
+		ID:          1,
+		Song:        "Hysteria",
+		GroupID:     2,
+		AlbumID:     &albumID,
+		TrackNumber: 8,
+		ReleaseDate: MustParseReleaseDate("2003-12-01"),
+		Text:        "It's bugging me\n\nGrating me",
+		Link:        "https://example.com/hysteria",
+		Sources:     map[string]string{FieldReleaseDate: "catalogue", FieldLink: "api"},
+		Version:     3,
+	}, merged)
+	//the song is not changed
+	assert.Equal(t, "2003", song.ReleaseDate.String())
+	assert.Equal(t, map[string]string{FieldReleaseDate: "api", FieldText: "api"}, song.Sources)
+
+	assert.Equal(t, song, song.MergeDuplicates(nil))
+}
+
+// songNames returns names of the songs in their order.
+func songNames(songs []Song) []string {
+	names := make([]string, 0, len(songs))
+	for _, song := range songs {
+		names = append(names, song.Song)
+	}
+	return names
+}
+
+func TestSong_IsSameSong(t *testing.T) {
+	song := Song{ID: 1, Song: "Hysteria", GroupID: 2}
+
+	assert.True(t, song.IsSameSong(2, "  hystéria "))
+	assert.False(t, song.IsSameSong(3, "Hysteria"))
+	assert.False(t, song.IsSameSong(2, "Uprising"))
+}
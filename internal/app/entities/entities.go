@@ -1,14 +1,185 @@
 package entities
 
+import (
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// EnrichmentStatus tells whether extra song data (release date, text and link) was received.
+type EnrichmentStatus string
+
+const (
+	EnrichmentPending  EnrichmentStatus = "pending"
+	EnrichmentEnriched EnrichmentStatus = "enriched"
+	EnrichmentFailed   EnrichmentStatus = "failed"
+)
+
 type Song struct {
+	ID          uint64  `gorm:"primary_key" json:"id,omitempty"`
+	Song        string  `json:"song"`
+	GroupID     uint64  `gorm:"index" json:"group_id,omitempty"`
+	Group       string  `gorm:"->;-:migration" json:"group"`
+	AlbumID     *uint64 `gorm:"index" json:"album_id,omitempty"`
+	DiscNumber  int     `json:"disc_number,omitempty"`
+	TrackNumber int     `json:"track_number,omitempty"`
+	// ReleaseDate is given in ISO 8601 format with the known parts only, like 2006-01-02, 2006-01 or 2006.
+	ReleaseDate *ReleaseDate `gorm:"embedded;embeddedPrefix:release_" json:"release_date,omitempty" swaggertype:"string" example:"2006-01-02"`
+	// ReleaseDateRaw keeps a release date which could not be parsed.
+	ReleaseDateRaw string `json:"-"`
+	Text           string `json:"text,omitempty"`
+	Link           string `json:"link,omitempty"`
+
+	EnrichmentStatus EnrichmentStatus `gorm:"index" json:"enrichment_status,omitempty"`
+	// Sources maps an extra data field (release_date, text or link) to the source it was taken from.
+	Sources map[string]string `gorm:"serializer:json;type:jsonb" json:"sources,omitempty"`
+
+	// Version grows with every change of the song, it is given to clients as an ETag.
+	Version int64 `gorm:"not null;default:1" json:"version,omitempty"`
+	// DeletedAt is set when the song is moved to the trash, trashed songs are hidden from all queries but the trash.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
+}
+
+// TrashedSong is a song in the trash, it can be restored until it is purged.
+type TrashedSong struct {
+	Song
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// ApplyExtraData fills empty extra data fields of the song and records their sources.
+// Fields which already have a value are kept.
+func (s *Song) ApplyExtraData(data ExtraSongData) {
+	current := ExtraSongData{ReleaseDate: s.ReleaseDateString(), Text: s.Text, Link: s.Link}
+	for _, field := range ExtraDataFields {
+		value := data.Field(field)
+		if value == "" || current.Field(field) != "" {
+			continue
+		}
+		current.SetField(field, value)
+		if source, ok := data.Sources[field]; ok {
+			if s.Sources == nil {
+				s.Sources = map[string]string{}
+			}
+			s.Sources[field] = source
+		}
+	}
+	if current.ReleaseDate != s.ReleaseDateString() {
+		s.SetReleaseDate(current.ReleaseDate)
+	}
+	s.Text, s.Link = current.Text, current.Link
+}
+
+// SetReleaseDate parses the release date, a value which can not be parsed is kept in ReleaseDateRaw.
+func (s *Song) SetReleaseDate(value string) {
+	date, err := ParseReleaseDate(value)
+	if err != nil {
+		s.ReleaseDate, s.ReleaseDateRaw = nil, value
+		return
+	}
+	s.ReleaseDate, s.ReleaseDateRaw = &date, ""
+}
+
+// ReleaseDateString returns the release date in ISO 8601 format or the raw value if it could not be parsed.
+func (s Song) ReleaseDateString() string {
+	if s.ReleaseDate == nil {
+		return s.ReleaseDateRaw
+	}
+	return s.ReleaseDate.String()
+}
+
+// Names of extra data fields, used as keys of Sources.
+const (
+	FieldReleaseDate = "release_date"
+	FieldText        = "text"
+	FieldLink        = "link"
+)
+
+// ExtraDataFields are all fields an extra data provider can fill.
+var ExtraDataFields = []string{FieldReleaseDate, FieldText, FieldLink}
+
+// ExtraSongData is an answer of an extra data provider.
+type ExtraSongData struct {
+	ReleaseDate string            `json:"release_date,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	Link        string            `json:"link,omitempty"`
+	Sources     map[string]string `json:"sources,omitempty"`
+}
+
+// Field returns a value of the field by its name.
+func (d ExtraSongData) Field(name string) string {
+	switch name {
+	case FieldReleaseDate:
+		return d.ReleaseDate
+	case FieldText:
+		return d.Text
+	case FieldLink:
+		return d.Link
+	}
+	return ""
+}
+
+// SetField sets a value of the field by its name.
+func (d *ExtraSongData) SetField(name, value string) {
+	switch name {
+	case FieldReleaseDate:
+		d.ReleaseDate = value
+	case FieldText:
+		d.Text = value
+	case FieldLink:
+		d.Link = value
+	}
+}
+
+// Group is a band or an artist. Songs reference it by GroupID.
+type Group struct {
+	ID             uint64   `gorm:"primary_key" json:"id,omitempty"`
+	Name           string   `gorm:"not null" json:"name"`
+	NormalizedName string   `gorm:"not null;uniqueIndex" json:"-"`
+	Aliases        []string `gorm:"serializer:json;type:jsonb" json:"aliases,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	FormedYear     int      `json:"formed_year,omitempty"`
+}
+
+// Album is a record of a group. Songs of the album are ordered by DiscNumber and TrackNumber.
+type Album struct {
 	ID          uint64 `gorm:"primary_key" json:"id,omitempty"`
-	Song        string `json:"song"`
-	Group       string `json:"group"`
+	Title       string `gorm:"not null" json:"title"`
+	GroupID     uint64 `gorm:"index" json:"group_id,omitempty"`
+	Group       string `gorm:"->;-:migration" json:"group"`
 	ReleaseDate string `json:"release_date,omitempty"`
-	Text        string `json:"text,omitempty"`
-	Link        string `json:"link,omitempty"`
+	CoverLink   string `json:"cover_link,omitempty"`
+}
+
+// EnrichmentJob is a pending request of extra data for a song, it is removed once the song is enriched or failed.
+type EnrichmentJob struct {
+	SongID        uint64    `gorm:"primary_key;autoIncrement:false"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	LastError     string
+	CreatedAt     time.Time
+}
+
+// CachedExtraData is a cached answer of an extra data provider. NotFound entries remember that the provider does not know the song.
+type CachedExtraData struct {
+	Key         string `gorm:"primary_key"`
+	ReleaseDate string
+	Text        string
+	Link        string
+	Sources     map[string]string `gorm:"serializer:json;type:jsonb"`
+	NotFound    bool
+	ExpiresAt   time.Time `gorm:"not null;index"`
 }
 
 type IDMessage struct {
 	ID uint64 `json:"id"`
 }
+
+// SongKey identifies a song by its group and name regardless of case and spaces, diacritics of the name are ignored too.
+func SongKey(group, song string) string {
+	return NormalizeGroupName(group) + "\n" + NormalizeSongName(song)
+}
+
+// NormalizeGroupName returns a key used to compare group names: "Muse", "muse" and "MUSE " are the same group.
+func NormalizeGroupName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
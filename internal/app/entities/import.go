@@ -0,0 +1,56 @@
+package entities
+
+// ImportStatus is an outcome of importing a row.
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	// ImportSkipped is a song which already exists in the library or earlier in the input.
+	ImportSkipped ImportStatus = "skipped"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportedSong is an outcome of saving an imported song.
+type ImportedSong struct {
+	// ID of the new song or, if it existed, of the song already in the library.
+	ID uint64
+	// Existed tells that a song of the same group and name was in the library, so the song was not saved.
+	Existed bool
+}
+
+// ImportRowResult is an outcome of importing a row of the input.
+type ImportRowResult struct {
+	// Row is the number of the record in the input starting from 1, a CSV header and blank lines are not counted.
+	Row    int          `json:"row"`
+	Status ImportStatus `json:"status"`
+	ID     uint64       `json:"id,omitempty"`
+	Group  string       `json:"group,omitempty"`
+	Song   string       `json:"song,omitempty"`
+	// Field is the invalid field of a failed row.
+	Field string `json:"field,omitempty"`
+	// Message tells why the row was skipped or failed.
+	Message string `json:"message,omitempty"`
+}
+
+// ImportReport is a result of an import, Rows go in the order of the input.
+type ImportReport struct {
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// Count counts rows by their status.
+func (r *ImportReport) Count() {
+	r.Created, r.Skipped, r.Failed = 0, 0, 0
+	for _, row := range r.Rows {
+		switch row.Status {
+		case ImportCreated:
+			r.Created++
+		case ImportSkipped:
+			r.Skipped++
+		case ImportFailed:
+			r.Failed++
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// DatePrecision tells which parts of a release date are known.
+// It is an alias, so gorm leaves Song.ReleaseDate nil for a NULL date instead of allocating an empty one.
+type DatePrecision = string
+
+const (
+	PrecisionDay   DatePrecision = "day"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionYear  DatePrecision = "year"
+)
+
+// releaseDateLayouts are formats of release dates given by users and upstream providers.
+var releaseDateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{"02.01.2006", PrecisionDay},
+	{time.DateOnly, PrecisionDay},
+	{time.RFC3339, PrecisionDay},
+	{"2006-01-02T15:04:05", PrecisionDay},
+	{"01.2006", PrecisionMonth},
+	{"2006-01", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// releaseDateMessage is the message of a release date parsing error.
+const releaseDateMessage = "release date must look like 2006-01-02, 02.01.2006, 2006-01 or 2006"
+
+// ReleaseDate is a date which may be known only up to a month or a year.
+// Date is the first day of the known period, it is stored as a date column and Precision next to it.
+type ReleaseDate struct {
+	Date      time.Time     `gorm:"column:date;type:date"`
+	Precision DatePrecision `gorm:"column:date_precision"`
+}
+
+// ParseReleaseDate parses a date in one of the known formats: 02.01.2006, ISO 8601 date or time,
+// a month like 2006-01 or 01.2006 and a year. Errors are FieldError.
+func ParseReleaseDate(value string) (ReleaseDate, error) {
+	value = strings.TrimSpace(value)
+	for _, format := range releaseDateLayouts {
+		date, err := time.Parse(format.layout, value)
+		if err == nil {
+			year, month, day := date.Date()
+			return ReleaseDate{Date: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Precision: format.precision}, nil
+		}
+	}
+	return ReleaseDate{}, FieldError{Field: FieldReleaseDate, Message: releaseDateMessage}
+}
+
+// MustParseReleaseDate is like ParseReleaseDate but panics on an invalid value.
+func MustParseReleaseDate(value string) *ReleaseDate {
+	date, err := ParseReleaseDate(value)
+	if err != nil {
+		panic(err)
+	}
+	return &date
+}
+
+// Last returns the last day of the known period.
+func (d ReleaseDate) Last() time.Time {
+	switch d.Precision {
+	case PrecisionMonth:
+		return d.Date.AddDate(0, 1, -1)
+	case PrecisionYear:
+		return d.Date.AddDate(1, 0, -1)
+	}
+	return d.Date
+}
+
+// String returns the date in ISO 8601 format with the known parts only, like 2006-01-02, 2006-01 or 2006.
+func (d ReleaseDate) String() string {
+	switch d.Precision {
+	case PrecisionMonth:
+		return d.Date.Format("2006-01")
+	case PrecisionYear:
+		return d.Date.Format("2006")
+	}
+	return d.Date.Format(time.DateOnly)
+}
+
+func (d ReleaseDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *ReleaseDate) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return FieldError{Field: FieldReleaseDate, Message: releaseDateMessage}
+	}
+	*d, err = ParseReleaseDate(value)
+	return err
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ReleaseDate
+		iso      string
+		last     time.Time
+		wantErr  bool
+	}{
+		{
+			value:    "16.07.2009",
+			expected: ReleaseDate{Date: time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay},
+			iso:      "2009-07-16",
+			last:     time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			value:    "2009-07-16",
+			expected: ReleaseDate{Date: time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay},
+			iso:      "2009-07-16",
+			last:     time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			value:    "2009-07-16T23:30:00+03:00",
+			expected: ReleaseDate{Date: time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay},
+			iso:      "2009-07-16",
+			last:     time.Date(2009, 7, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			value:    "2012-02",
+			expected: ReleaseDate{Date: time.Date(2012, 2, 1, 0, 0, 0, 0, time.UTC), Precision: PrecisionMonth},
+			iso:      "2012-02",
+			last:     time.Date(2012, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			value:    " 1999 ",
+			expected: ReleaseDate{Date: time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), Precision: PrecisionYear},
+			iso:      "1999",
+			last:     time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{value: "July 16, 2009", wantErr: true},
+		{value: "31.02.2009", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			date, err := ParseReleaseDate(tt.value)
+			if tt.wantErr {
+				assert.ErrorAs(t, err, &FieldError{})
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, date)
+			assert.Equal(t, tt.iso, date.String())
+			assert.Equal(t, tt.last, date.Last())
+		})
+	}
+}
+
+func TestReleaseDate_JSON(t *testing.T) {
+	song := Song{Song: "Hysteria", ReleaseDate: MustParseReleaseDate("01.12.2003")}
+	data, err := json.Marshal(song)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"song":"Hysteria","group":"","release_date":"2003-12-01"}`, string(data))
+
+	var decoded Song
+	err = json.Unmarshal([]byte(`{"song":"Hysteria","release_date":"2003-12"}`), &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseReleaseDate("2003-12"), decoded.ReleaseDate)
+
+	err = json.Unmarshal([]byte(`{"release_date":"soon"}`), &decoded)
+	var fieldErr FieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, FieldReleaseDate, fieldErr.Field)
+}
+
+func TestSong_ApplyExtraData(t *testing.T) {
+	song := Song{Link: "https://example.com/own"}
+	song.ApplyExtraData(ExtraSongData{
+		ReleaseDate: "16.07.2009",
+		Link:        "https://example.com/api",
+		Sources:     map[string]string{FieldReleaseDate: "api", FieldLink: "api"},
+	})
+	assert.Equal(t, MustParseReleaseDate("2009-07-16"), song.ReleaseDate)
+	assert.Equal(t, "https://example.com/own", song.Link)
+	assert.Equal(t, map[string]string{FieldReleaseDate: "api"}, song.Sources)
+
+	unparsed := Song{}
+	unparsed.ApplyExtraData(ExtraSongData{ReleaseDate: "summer of 69"})
+	assert.Nil(t, unparsed.ReleaseDate)
+	assert.Equal(t, "summer of 69", unparsed.ReleaseDateRaw)
+	assert.Equal(t, "summer of 69", unparsed.ReleaseDateString())
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// RevisionAction is a kind of a song change.
+type RevisionAction string
+
+const (
+	RevisionCreate  RevisionAction = "create"
+	RevisionUpdate  RevisionAction = "update"
+	RevisionDelete  RevisionAction = "delete"
+	RevisionRestore RevisionAction = "restore"
+	RevisionRevert  RevisionAction = "revert"
+	// RevisionMerge is a song which took metadata of its duplicates, they are moved to the trash.
+	RevisionMerge RevisionAction = "merge"
+)
+
+// SongRevision is a recorded change of a song. Revisions of a song are numbered from 1.
+type SongRevision struct {
+	ID        uint64         `gorm:"primary_key" json:"-"`
+	SongID    uint64         `gorm:"not null" json:"song_id"`
+	Revision  int            `gorm:"not null" json:"revision"`
+	Action    RevisionAction `gorm:"not null" json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Changes   []FieldChange  `gorm:"serializer:json;type:jsonb" json:"changes,omitempty"`
+	// Song is the state of the song after the change, a song is reverted to it.
+	Song      *Song     `gorm:"serializer:json;type:jsonb" json:"song,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FieldChange is a value of a song field before and after a change, nil for an empty value.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// DiffSongs returns fields which differ in JSON representations of the songs, sorted by name.
+// A nil song has no fields, so all fields of the other one are changed. IDs and versions are not compared.
+func DiffSongs(before, after *Song) []FieldChange {
+	beforeFields, afterFields := songFields(before), songFields(after)
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if name == "id" || name == "version" || reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: beforeFields[name], After: afterFields[name]})
+	}
+	return changes
+}
+
+// songFields returns fields of the song as they are given to clients.
+func songFields(song *Song) map[string]any {
+	fields := map[string]any{}
+	if song == nil {
+		return fields
+	}
+	data, err := json.Marshal(song)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+// AuditInfo tells who made a change, it is recorded in song revisions.
+type AuditInfo struct {
+	Actor     string
+	RequestID string
+}
+
+type auditInfoKey struct{}
+
+// WithAuditInfo returns a context which carries the audit info to a storage.
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditInfoKey{}, info)
+}
+
+// AuditInfoFromContext returns the audit info of the context, it is empty if there is none.
+func AuditInfoFromContext(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditInfoKey{}).(AuditInfo)
+	return info
+}
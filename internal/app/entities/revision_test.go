@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffSongs(t *testing.T) {
+	albumID := uint64(7)
+	tests := []struct {
+		name     string
+		before   *Song
+		after    *Song
+		expected []FieldChange
+	}{
+		{
+			name:   "Created",
+			before: nil,
+			after:  &Song{ID: 1, Song: "some song", Group: "some group", GroupID: 2},
+			expected: []FieldChange{
+				{Field: "group", Before: nil, After: "some group"},
+				{Field: "group_id", Before: nil, After: float64(2)},
+				{Field: "song", Before: nil, After: "some song"},
+			},
+		},
+		{
+			name:   "Updated",
+			before: &Song{ID: 1, Song: "some song", Group: "some group", Text: "some text", ReleaseDate: MustParseReleaseDate("2010")},
+			after:  &Song{ID: 1, Song: "some song", Group: "some group", AlbumID: &albumID, ReleaseDate: MustParseReleaseDate("2010-10-10")},
+			expected: []FieldChange{
+				{Field: "album_id", Before: nil, After: float64(7)},
+				{Field: "release_date", Before: "2010", After: "2010-10-10"},
+				{Field: "text", Before: "some text", After: nil},
+			},
+		},
+		{
+			name:     "Unchanged",
+			before:   &Song{ID: 1, Song: "some song", Sources: map[string]string{FieldText: "api"}},
+			after:    &Song{ID: 1, Song: "some song", Sources: map[string]string{FieldText: "api"}},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DiffSongs(tt.before, tt.after))
+		})
+	}
+}
+
+func TestAuditInfoFromContext(t *testing.T) {
+	assert.Equal(t, AuditInfo{}, AuditInfoFromContext(context.Background()))
+
+	info := AuditInfo{Actor: "admin", RequestID: "host/abcdef-000001"}
+	assert.Equal(t, info, AuditInfoFromContext(WithAuditInfo(context.Background(), info)))
+}
@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultSearchLanguage is the text search configuration used when a query does not name one.
+const DefaultSearchLanguage = "english"
+
+// MaxSearchQueryLength limits the length of a search query in bytes.
+const MaxSearchQueryLength = 500
+
+// DefaultSimilarity is the trigram similarity a name needs to be found by a fuzzy search.
+const DefaultSimilarity = 0.3
+
+// MaxSuggestions limits the "did you mean" list.
+const MaxSuggestions = 5
+
+// SearchMode tells how a search query is matched.
+type SearchMode string
+
+const (
+	// SearchFullText matches words of song names and lyrics, it is the default mode.
+	SearchFullText SearchMode = "fulltext"
+	// SearchFuzzy matches song and group names by trigram similarity, so misspelled names are found.
+	SearchFuzzy SearchMode = "fuzzy"
+)
+
+// SearchLanguages are the text search configurations songs are indexed with.
+// "simple" does not stem words, so it suits any language.
+var SearchLanguages = []string{"simple", "english", "russian"}
+
+// SearchQuery is a full-text search request over song names and lyrics.
+type SearchQuery struct {
+	// Text is a web search query: words, "quoted phrases", `or` and `-` to exclude a word.
+	Text string
+	Mode SearchMode
+	// Language and Phrase are used by the full-text mode.
+	Language string
+	// Phrase makes the whole Text a phrase, its words must follow each other.
+	Phrase bool
+	// Similarity from 0 to 1 is used by the fuzzy mode, zero gives DefaultSimilarity.
+	Similarity float64
+	Offset     int
+	Limit      int
+}
+
+// SearchResult is a found song with its rank and a highlighted snippet.
+type SearchResult struct {
+	Song Song `json:"song"`
+	// Rank tells how well the song matches, a greater rank is better.
+	Rank float64 `json:"rank"`
+	// Headline is the best matching couplet with matched words wrapped in <mark></mark>, fuzzy search gives no headline.
+	// HTML of the lyrics is escaped, so the marks are the only tags.
+	Headline string `json:"headline,omitempty"`
+}
+
+// headlineEscaper escapes HTML, the Postgres storage does the same in SQL.
+var headlineEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// EscapeHeadline escapes HTML of lyrics before their matched words are marked in a headline.
+func EscapeHeadline(text string) string {
+	return headlineEscaper.Replace(text)
+}
+
+// Validate checks the query. Errors are FieldError.
+func (q SearchQuery) Validate() error {
+	if strings.TrimSpace(q.Text) == "" {
+		return FieldError{Field: "q", Message: "search query is empty"}
+	}
+	if len(q.Text) > MaxSearchQueryLength {
+		return FieldError{Field: "q", Message: "search query is too long"}
+	}
+	if q.Mode != "" && q.Mode != SearchFullText && q.Mode != SearchFuzzy {
+		return FieldError{Field: "mode", Message: "search mode must be `fulltext` or `fuzzy`"}
+	}
+	if q.Similarity < 0 || q.Similarity > 1 {
+		return FieldError{Field: "similarity", Message: "similarity must be from 0 to 1"}
+	}
+	if q.Language != "" && !slices.Contains(SearchLanguages, q.Language) {
+		return FieldError{Field: "lang", Message: "language must be one of " + strings.Join(SearchLanguages, ", ")}
+	}
+	return nil
+}
+
+// SearchLanguage returns the language of the query or DefaultSearchLanguage.
+func (q SearchQuery) SearchLanguage() string {
+	if q.Language == "" {
+		return DefaultSearchLanguage
+	}
+	return q.Language
+}
+
+// MinSimilarity returns the similarity of the query or DefaultSimilarity.
+func (q SearchQuery) MinSimilarity() float64 {
+	if q.Similarity == 0 {
+		return DefaultSimilarity
+	}
+	return q.Similarity
+}
+
+// MatchesExactly tells whether the song or its group is named exactly as the text, ignoring case.
+func (r SearchResult) MatchesExactly(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.EqualFold(r.Song.Song, text) || strings.EqualFold(r.Song.Group, text)
+}
+
+// PageLimit returns the limit in range [1, MaxPageLimit], zero and negative limits give DefaultPageLimit.
+func (q SearchQuery) PageLimit() int {
+	return SongListQuery{Limit: q.Limit}.PageLimit()
+}
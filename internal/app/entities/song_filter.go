@@ -0,0 +1,106 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxFilterListSize limits lists of IDs and names in a filter.
+const MaxFilterListSize = 100
+
+// MatchMode tells how a text filter is compared with a value, case is ignored in all modes.
+type MatchMode string
+
+const (
+	MatchContains MatchMode = "contains"
+	MatchPrefix   MatchMode = "prefix"
+	MatchExact    MatchMode = "exact"
+)
+
+// SongSortFields are fields songs can be sorted by.
+var SongSortFields = []string{"id", "song", "group", "release_date", "disc_number", "track_number"}
+
+// FieldError is a validation error of a single field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// SongFilter selects songs, empty fields do not filter.
+type SongFilter struct {
+	IDs []uint64
+	// Song is compared with song names according to SongMatch, MatchContains by default.
+	Song      string
+	SongMatch MatchMode
+	// Group is compared with group names according to GroupMatch, MatchContains by default.
+	Group      string
+	GroupMatch MatchMode
+	GroupIDs   []uint64
+	// Groups are exact group names, any of them matches.
+	Groups []string
+	// ReleaseDate selects songs released within its period, like a whole year for a year-only date.
+	ReleaseDate *ReleaseDate
+	// ReleaseDateFrom is the start of a period and ReleaseDateTo is its end, both are included.
+	ReleaseDateFrom *ReleaseDate
+	ReleaseDateTo   *ReleaseDate
+	HasLyrics       *bool
+	HasLink         *bool
+}
+
+// Validate checks the filter. Errors are FieldError.
+func (f SongFilter) Validate() error {
+	for field, mode := range map[string]MatchMode{"song_match": f.SongMatch, "group_match": f.GroupMatch} {
+		if mode != "" && mode != MatchContains && mode != MatchPrefix && mode != MatchExact {
+			return FieldError{Field: field, Message: fmt.Sprintf("match mode must be `%v`, `%v` or `%v`", MatchContains, MatchPrefix, MatchExact)}
+		}
+	}
+	if len(f.IDs) > MaxFilterListSize {
+		return FieldError{Field: "ids", Message: fmt.Sprintf("at most %d ids are allowed", MaxFilterListSize)}
+	}
+	if len(f.GroupIDs) > MaxFilterListSize {
+		return FieldError{Field: "group_id", Message: fmt.Sprintf("at most %d group ids are allowed", MaxFilterListSize)}
+	}
+	if len(f.Groups) > MaxFilterListSize {
+		return FieldError{Field: "groups", Message: fmt.Sprintf("at most %d groups are allowed", MaxFilterListSize)}
+	}
+	if f.ReleaseDateFrom != nil && f.ReleaseDateTo != nil && f.ReleaseDateFrom.Date.After(f.ReleaseDateTo.Last()) {
+		return FieldError{Field: "release_date_from", Message: "release_date_from is after release_date_to"}
+	}
+	return nil
+}
+
+// SongSort is an order of songs. Songs with the same value of Field are ordered by ID in the same direction.
+type SongSort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSongSort parses a sort like "release_date" or "-release_date" (descending). An empty sort orders by ID.
+func ParseSongSort(sort string) (SongSort, error) {
+	sort = strings.TrimSpace(sort)
+	if sort == "" {
+		return SongSort{Field: "id"}, nil
+	}
+	result := SongSort{Field: strings.TrimPrefix(sort, "-"), Desc: strings.HasPrefix(sort, "-")}
+	if !slices.Contains(SongSortFields, result.Field) {
+		return SongSort{}, FieldError{Field: "sort", Message: fmt.Sprintf("songs can be sorted by %v", strings.Join(SongSortFields, ", "))}
+	}
+	return result, nil
+}
+
+// String returns the sort in the ParseSongSort format.
+func (s SongSort) String() string {
+	field := s.Field
+	if field == "" {
+		field = "id"
+	}
+	if s.Desc {
+		return "-" + field
+	}
+	return field
+}
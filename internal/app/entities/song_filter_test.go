@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSongFilter_Validate(t *testing.T) {
+	early := MustParseReleaseDate("2000")
+	late := MustParseReleaseDate("2010-05-01")
+
+	tests := []struct {
+		name          string
+		filter        SongFilter
+		expectedField string
+	}{
+		{name: "Empty", filter: SongFilter{}},
+		{name: "Valid", filter: SongFilter{SongMatch: MatchExact, GroupMatch: MatchPrefix, ReleaseDateFrom: early, ReleaseDateTo: late}},
+		{name: "Same year", filter: SongFilter{ReleaseDateFrom: late, ReleaseDateTo: MustParseReleaseDate("2010")}},
+		{name: "Unknown match mode", filter: SongFilter{GroupMatch: "regex"}, expectedField: "group_match"},
+		{name: "Reversed dates", filter: SongFilter{ReleaseDateFrom: late, ReleaseDateTo: early}, expectedField: "release_date_from"},
+		{name: "Too many IDs", filter: SongFilter{IDs: make([]uint64, MaxFilterListSize+1)}, expectedField: "ids"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.expectedField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var fieldErr FieldError
+			assert.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, tt.expectedField, fieldErr.Field)
+		})
+	}
+}
+
+func TestParseSongSort(t *testing.T) {
+	tests := []struct {
+		sort     string
+		expected SongSort
+		wantErr  bool
+	}{
+		{sort: "", expected: SongSort{Field: "id"}},
+		{sort: "song", expected: SongSort{Field: "song"}},
+		{sort: "-release_date", expected: SongSort{Field: "release_date", Desc: true}},
+		{sort: "text", wantErr: true},
+		{sort: "--id", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			sort, err := ParseSongSort(tt.sort)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.expected, sort)
+			if !tt.wantErr {
+				assert.Equal(t, tt.expected.String(), SongSort{Field: sort.Field, Desc: sort.Desc}.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// ErrInvalidCursor is returned for a cursor token which was not made by Cursor.Encode.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SongListQuery is a request of a page of songs.
+type SongListQuery struct {
+	Filter SongFilter
+	Sort   SongSort
+	// Cursor is a position the page starts from, nil for the first page.
+	Cursor *Cursor
+	// Offset is used by legacy clients without a cursor.
+	Offset int
+	Limit  int
+	// WithTotal also counts all songs matching the filter.
+	WithTotal bool
+}
+
+// PageLimit returns the limit in range [1, MaxPageLimit], zero and negative limits give DefaultPageLimit.
+func (q SongListQuery) PageLimit() int {
+	if q.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	if q.Limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return q.Limit
+}
+
+// SongPage is a page of songs. Cursors are nil when there are no songs in their direction.
+type SongPage struct {
+	Songs      []Song
+	NextCursor *Cursor
+	PrevCursor *Cursor
+	// Total is set only if it was requested.
+	Total *int64
+}
+
+// Cursor points between two songs of a list.
+type Cursor struct {
+	// Sort is the sort of the list the cursor was made for.
+	Sort string `json:"s,omitempty"`
+	// Key is a sort key of the song the cursor points after (or before, if Backward).
+	Key string `json:"k,omitempty"`
+	// ID breaks ties of songs with the same Key.
+	ID       uint64 `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// Encode returns an opaque token of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token made by Cursor.Encode.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	cursor := Cursor{}
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "Forward", cursor: Cursor{ID: 10}},
+		{name: "Backward with key", cursor: Cursor{Key: "2010-10-10", ID: 10, Backward: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.cursor.Encode())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.cursor, cursor)
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	for _, token := range []string{"", "abc", "e30", "not base64!"} {
+		t.Run(token, func(t *testing.T) {
+			_, err := DecodeCursor(token)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestSongListQuery_PageLimit(t *testing.T) {
+	tests := []struct {
+		limit    int
+		expected int
+	}{
+		{limit: 0, expected: DefaultPageLimit},
+		{limit: -1, expected: DefaultPageLimit},
+		{limit: 10, expected: 10},
+		{limit: MaxPageLimit + 1, expected: MaxPageLimit},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, SongListQuery{Limit: tt.limit}.PageLimit())
+	}
+}
@@ -0,0 +1,183 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// editableSongFields are JSON names of song fields a client can change, in the order a patch applies them.
+var editableSongFields = []string{"song", "group_id", "group", "album_id", "disc_number", "track_number", FieldReleaseDate, FieldText, FieldLink}
+
+// readOnlySongFields are JSON names of song fields only the service changes.
+var readOnlySongFields = []string{"id", "version", "enrichment_status", "sources"}
+
+// ApplyMergePatch changes the song by an RFC 7396 merge patch: absent fields are kept and null clears a field.
+// A new group name without group_id moves the song to the group of that name. Errors are FieldError.
+func (s *Song) ApplyMergePatch(patch []byte) error {
+	return s.applyPatch(patch, false)
+}
+
+// ApplyUpdate changes the song by a JSON object of fields to set, absent and empty fields are kept.
+// It checks fields like ApplyMergePatch does. Errors are FieldError.
+func (s *Song) ApplyUpdate(update []byte) error {
+	return s.applyPatch(update, true)
+}
+
+// applyPatch sets the editable fields of the JSON object, empty ones are skipped if skipEmpty is set.
+func (s *Song) applyPatch(patch []byte, skipEmpty bool) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(patch, &fields)
+	if err != nil || fields == nil {
+		if skipEmpty {
+			return FieldError{Message: "song update must be a JSON object"}
+		}
+		return FieldError{Message: "merge patch must be a JSON object"}
+	}
+	for name := range fields {
+		if slices.Contains(readOnlySongFields, name) {
+			return FieldError{Field: name, Message: name + " can not be changed"}
+		}
+		if !slices.Contains(editableSongFields, name) {
+			return FieldError{Field: name, Message: "song has no field " + name}
+		}
+		if skipEmpty && isEmptyJSON(fields[name]) {
+			delete(fields, name)
+		}
+	}
+
+	for _, name := range editableSongFields {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		err = s.patchField(name, value)
+		if err != nil {
+			return err
+		}
+		if name == "group" {
+			if _, ok := fields["group_id"]; !ok {
+				s.GroupID = 0
+			}
+		}
+	}
+	return nil
+}
+
+// isEmptyJSON tells whether the value is null or the zero value of a field.
+func isEmptyJSON(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "null", `""`, "0":
+		return true
+	}
+	return false
+}
+
+// patchField sets a field by its JSON name, null clears it.
+func (s *Song) patchField(name string, value json.RawMessage) error {
+	if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+		switch name {
+		case "song":
+			s.Song = ""
+		case "group_id":
+			s.GroupID = 0
+		case "group":
+			s.Group = ""
+		case "album_id":
+			s.AlbumID = nil
+		case "disc_number":
+			s.DiscNumber = 0
+		case "track_number":
+			s.TrackNumber = 0
+		case FieldReleaseDate:
+			s.ReleaseDate, s.ReleaseDateRaw = nil, ""
+		case FieldText:
+			s.Text = ""
+		case FieldLink:
+			s.Link = ""
+		}
+		return nil
+	}
+
+	var target any
+	switch name {
+	case "song":
+		target = &s.Song
+	case "group_id":
+		target = &s.GroupID
+	case "group":
+		target = &s.Group
+	case "album_id":
+		target = &s.AlbumID
+	case "disc_number":
+		target = &s.DiscNumber
+	case "track_number":
+		target = &s.TrackNumber
+	case FieldReleaseDate:
+		var date ReleaseDate
+		err := json.Unmarshal(value, &date)
+		if err != nil {
+			return err
+		}
+		s.ReleaseDate, s.ReleaseDateRaw = &date, ""
+		return nil
+	case FieldText:
+		target = &s.Text
+	case FieldLink:
+		target = &s.Link
+	}
+	err := json.Unmarshal(value, target)
+	if err != nil {
+		return FieldError{Field: name, Message: name + " has a wrong type"}
+	}
+	return nil
+}
+
+// Validate checks fields a client can change. Errors are FieldError.
+func (s Song) Validate() error {
+	if strings.TrimSpace(s.Song) == "" {
+		return FieldError{Field: "song", Message: "song name is empty"}
+	}
+	if strings.TrimSpace(s.Group) == "" && s.GroupID == 0 {
+		return FieldError{Field: "group", Message: "song group is empty"}
+	}
+	err := ValidateTrackPosition(s.AlbumID, s.DiscNumber, s.TrackNumber)
+	if err != nil {
+		return err
+	}
+	if s.Link != "" {
+		link, err := url.Parse(s.Link)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return FieldError{Field: FieldLink, Message: "link must be an absolute http or https URL"}
+		}
+	}
+	return nil
+}
+
+// ValidateTrackPosition checks the position of a song on an album. Errors are FieldError.
+func ValidateTrackPosition(albumID *uint64, discNumber, trackNumber int) error {
+	if discNumber < 0 || trackNumber < 0 {
+		return FieldError{Field: "track_number", Message: "disc and track numbers can not be negative"}
+	}
+	if albumID == nil && (discNumber != 0 || trackNumber != 0) {
+		return FieldError{Field: "album_id", Message: "disc and track numbers are set, but album is not"}
+	}
+	return nil
+}
+
+// ForgetChangedSources drops sources of extra data fields which differ from the song before,
+// a value set by a client was not taken from any source.
+func (s *Song) ForgetChangedSources(before Song) {
+	current := ExtraSongData{ReleaseDate: s.ReleaseDateString(), Text: s.Text, Link: s.Link}
+	previous := ExtraSongData{ReleaseDate: before.ReleaseDateString(), Text: before.Text, Link: before.Link}
+	for _, field := range ExtraDataFields {
+		if current.Field(field) != previous.Field(field) {
+			delete(s.Sources, field)
+		}
+	}
+	if len(s.Sources) == 0 {
+		s.Sources = nil
+	}
+}
@@ -0,0 +1,172 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSong_ApplyMergePatch(t *testing.T) {
+	albumID := uint64(7)
+	song := Song{
+		ID:          1,
+		Song:        "some song",
+		GroupID:     2,
+		Group:       "some group",
+		AlbumID:     &albumID,
+		TrackNumber: 3,
+		ReleaseDate: MustParseReleaseDate("2010-10-10"),
+		Text:        "some text",
+		Link:        "https://example.com/somesong",
+		Version:     4,
+	}
+	tests := []struct {
+		name     string
+		patch    string
+		expected Song
+		wantErr  error
+	}{
+		{
+			name:  "Null clears fields",
+			patch: `{"link":null,"text":null,"release_date":null}`,
+			expected: Song{
+				ID: 1, Song: "some song", GroupID: 2, Group: "some group", AlbumID: &albumID, TrackNumber: 3, Version: 4,
+			},
+		},
+		{
+			name:  "Absent fields are kept",
+			patch: `{"song":"new song","release_date":"2011"}`,
+			expected: Song{
+				ID: 1, Song: "new song", GroupID: 2, Group: "some group", AlbumID: &albumID, TrackNumber: 3,
+				ReleaseDate: MustParseReleaseDate("2011"), Text: "some text", Link: "https://example.com/somesong", Version: 4,
+			},
+		},
+		{
+			name:  "Group name moves the song",
+			patch: `{"group":"new group","album_id":null,"track_number":null}`,
+			expected: Song{
+				ID: 1, Song: "some song", Group: "new group",
+				ReleaseDate: MustParseReleaseDate("2010-10-10"), Text: "some text", Link: "https://example.com/somesong", Version: 4,
+			},
+		},
+		{
+			name:    "Read-only field",
+			patch:   `{"version":5}`,
+			wantErr: FieldError{Field: "version", Message: "version can not be changed"},
+		},
+		{
+			name:    "Unknown field",
+			patch:   `{"lyrics":"some text"}`,
+			wantErr: FieldError{Field: "lyrics", Message: "song has no field lyrics"},
+		},
+		{
+			name:    "Wrong type",
+			patch:   `{"track_number":"3"}`,
+			wantErr: FieldError{Field: "track_number", Message: "track_number has a wrong type"},
+		},
+		{
+			name:    "Bad release date",
+			patch:   `{"release_date":"next week"}`,
+			wantErr: FieldError{Field: FieldReleaseDate, Message: releaseDateMessage},
+		},
+		{
+			name:    "Not an object",
+			patch:   `["song"]`,
+			wantErr: FieldError{Message: "merge patch must be a JSON object"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched := song
+			err := patched.ApplyMergePatch([]byte(tt.patch))
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, patched)
+		})
+	}
+}
+
+func TestSong_ApplyUpdate(t *testing.T) {
+	song := Song{ID: 1, Song: "some song", GroupID: 2, Group: "some group", Text: "some text", Version: 4}
+	tests := []struct {
+		name     string
+		update   string
+		expected Song
+		wantErr  error
+	}{
+		{
+			name:     "Empty fields are kept",
+			update:   `{"song":"new song","text":"","link":null,"track_number":0}`,
+			expected: Song{ID: 1, Song: "new song", GroupID: 2, Group: "some group", Text: "some text", Version: 4},
+		},
+		{
+			name:     "Group name moves the song",
+			update:   `{"group":"new group","group_id":0}`,
+			expected: Song{ID: 1, Song: "some song", Group: "new group", Text: "some text", Version: 4},
+		},
+		{
+			name:    "Read-only field",
+			update:  `{"enrichment_status":"done"}`,
+			wantErr: FieldError{Field: "enrichment_status", Message: "enrichment_status can not be changed"},
+		},
+		{
+			name:    "Not an object",
+			update:  `"song"`,
+			wantErr: FieldError{Message: "song update must be a JSON object"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := song
+			err := updated.ApplyUpdate([]byte(tt.update))
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, updated)
+		})
+	}
+}
+
+func TestSong_Validate(t *testing.T) {
+	albumID := uint64(7)
+	tests := []struct {
+		name    string
+		song    Song
+		wantErr error
+	}{
+		{name: "Ok", song: Song{Song: "some song", Group: "some group", AlbumID: &albumID, TrackNumber: 1, Link: "https://example.com/somesong"}},
+		{name: "Group by ID", song: Song{Song: "some song", GroupID: 2}},
+		{name: "Empty song", song: Song{Song: " ", Group: "some group"}, wantErr: FieldError{Field: "song", Message: "song name is empty"}},
+		{name: "Empty group", song: Song{Song: "some song"}, wantErr: FieldError{Field: "group", Message: "song group is empty"}},
+		{
+			name:    "Track without album",
+			song:    Song{Song: "some song", Group: "some group", TrackNumber: 1},
+			wantErr: FieldError{Field: "album_id", Message: "disc and track numbers are set, but album is not"},
+		},
+		{
+			name:    "Relative link",
+			song:    Song{Song: "some song", Group: "some group", Link: "/somesong"},
+			wantErr: FieldError{Field: FieldLink, Message: "link must be an absolute http or https URL"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.song.Validate())
+		})
+	}
+}
+
+func TestSong_ForgetChangedSources(t *testing.T) {
+	before := Song{Text: "some text", Link: "https://example.com/somesong", Sources: map[string]string{FieldText: "lyrics", FieldLink: "api"}}
+	song := Song{Text: "new text", Link: "https://example.com/somesong", Sources: map[string]string{FieldText: "lyrics", FieldLink: "api"}}
+	song.ForgetChangedSources(before)
+	assert.Equal(t, map[string]string{FieldLink: "api"}, song.Sources)
+
+	song.Link = ""
+	song.ForgetChangedSources(before)
+	assert.Nil(t, song.Sources)
+}
@@ -0,0 +1,45 @@
+package httphandlers
+
+import (
+	"errors"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// DeleteAlbum godoc
+// @Summary Deletes the album
+// @Description Deletes album by id. Songs of the album are kept.
+// @Produce plain
+// @Param id path uint64 true "Album ID"
+// @Success 200 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /albums/{id} [delete]
+// @Router /api/v1/albums/{id} [delete]
+func (h *handler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid album id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "album id must be a positive integer")
+		return
+	}
+
+	//delete
+	err = h.storage.RemoveAlbum(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no albums found with ID `%v`, err: %v", id, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "album not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to remove album in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusOK)
+	return
+}
@@ -0,0 +1,117 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GetAlbum(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbum(gomock.Any(), uint64(7)).Return(entities.Album{
+						ID:      7,
+						Title:   "Absolution",
+						GroupID: 3,
+						Group:   "Muse",
+					}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":7,"title":"Absolution","group_id":3,"group":"Muse"}`,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/abc", nil), map[string]string{"id": "abc"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "id", "album id must be a positive integer", "/albums/abc"),
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbum(gomock.Any(), uint64(7)).Return(entities.Album{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "album not found", "/albums/7"),
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbum(gomock.Any(), uint64(7)).Return(entities.Album{}, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/albums/7"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.GetAlbum(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
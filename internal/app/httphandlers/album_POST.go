@@ -0,0 +1,71 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"io"
+	"musiclib/internal/app/entities"
+	"net/http"
+	"strings"
+)
+
+// PostAlbum godoc
+// @Summary Creates new album
+// @Description Creates new album. The group is found by its name or alias and created if it does not exist yet.
+// @Accept  json
+// @Produce json
+// @Param album body entities.Album true "JSON album data"
+// @Success 201 {object} entities.IDMessage "Album ID"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 422 {object} ProblemDetails "Request refers to missing content"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /albums [post]
+// @Router /api/v1/albums [post]
+func (h *handler) PostAlbum(w http.ResponseWriter, r *http.Request) {
+	//get album from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	album := entities.Album{}
+	err = json.Unmarshal(bodyBytes, &album)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
+		return
+	}
+	if strings.TrimSpace(album.Title) == "" {
+		h.logger.Debugf("album title is empty")
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "title", "album title is empty")
+		return
+	}
+	if album.GroupID == 0 && strings.TrimSpace(album.Group) == "" {
+		h.logger.Debugf("album group is empty")
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "group", "album group or group_id is required")
+		return
+	}
+	album.ID = 0
+
+	//save
+	id, err := h.storage.SaveAlbum(r.Context(), album)
+	if err != nil {
+		h.logger.Debugf("failed to create album in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(entities.IDMessage{
+		ID: id,
+	})
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonAnswer)
+	return
+}
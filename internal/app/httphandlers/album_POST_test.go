@@ -0,0 +1,134 @@
+package httphandlers
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_PostAlbum(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Ok",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveAlbum(gomock.Any(), entities.Album{
+						Title:       "Absolution",
+						Group:       "Muse",
+						ReleaseDate: "15.09.2003",
+						CoverLink:   "https://example.com/absolution.jpg",
+					}).Return(uint64(7), nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"title":"Absolution","group":"Muse","release_date":"15.09.2003","cover_link":"https://example.com/absolution.jpg"}`)),
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":7}`,
+		},
+		{
+			name: "Group by ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveAlbum(gomock.Any(), entities.Album{
+						Title:   "Absolution",
+						GroupID: 3,
+					}).Return(uint64(7), nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"id":1,"title":"Absolution","group_id":3}`)),
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":7}`,
+		},
+		{
+			name: "Empty title",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"group":"Muse"}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeRequiredField, "title", "album title is empty", "/albums"),
+		},
+		{
+			name: "No group",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"title":"Absolution"}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeRequiredField, "group", "album group or group_id is required", "/albums"),
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveAlbum(gomock.Any(), gomock.Any()).Return(uint64(0), fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/albums", bytes.NewBufferString(`{"title":"Absolution","group":"Muse"}`)),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/albums"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.PostAlbum(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
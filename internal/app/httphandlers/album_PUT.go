@@ -0,0 +1,69 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// PutAlbum godoc
+// @Summary Updates the album
+// @Description Updates album by id, empty fields are left unchanged.
+// @Accept  json
+// @Produce plain
+// @Param id path uint64 true "Album ID"
+// @Param album body entities.Album true "JSON album data"
+// @Success 200 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 422 {object} ProblemDetails "Request refers to missing content"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /albums/{id} [put]
+// @Router /api/v1/albums/{id} [put]
+func (h *handler) PutAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid album id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "album id must be a positive integer")
+		return
+	}
+
+	//get album from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	album := entities.Album{}
+	err = json.Unmarshal(bodyBytes, &album)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
+		return
+	}
+	album.ID = id
+
+	//update
+	err = h.storage.UpdateAlbum(r.Context(), album)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("album was`nt found in db, err: %v", err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "album not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to update album in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusOK)
+	return
+}
@@ -0,0 +1,123 @@
+package httphandlers
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_PutAlbum(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateAlbum(gomock.Any(), entities.Album{
+						ID:        7,
+						CoverLink: "https://example.com/cover.jpg",
+					}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/albums/7", bytes.NewBufferString(`{"cover_link":"https://example.com/cover.jpg"}`)), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/albums/x", bytes.NewBufferString(`{"title":"Showbiz"}`)), map[string]string{"id": "x"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Bad JSON",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/albums/7", bytes.NewBufferString(`{"title":`)), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateAlbum(gomock.Any(), gomock.Any()).Return(dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/albums/7", bytes.NewBufferString(`{"title":"Showbiz"}`)), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateAlbum(gomock.Any(), gomock.Any()).Return(fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/albums/7", bytes.NewBufferString(`{"title":"Showbiz"}`)), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.PutAlbum(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// GetAlbumTracks godoc
+// @Summary Returns the album tracklist
+// @Description Returns songs of the album ordered by disc and track numbers.
+// @Produce json
+// @Param id path uint64 true "Album ID"
+// @Success 200 {array} entities.Song "Tracklist"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Album not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /albums/{id}/tracks [get]
+// @Router /api/v1/albums/{id}/tracks [get]
+func (h *handler) GetAlbumTracks(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid album id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "album id must be a positive integer")
+		return
+	}
+
+	//get tracks
+	songs, err := h.storage.GetAlbumTracks(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no album found with id %d", id)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "album not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to get album tracks from db: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(songs)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
@@ -0,0 +1,136 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GetAlbumTracks(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	albumID := uint64(7)
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumTracks(gomock.Any(), albumID).Return([]entities.Song{
+						{ID: 2, Song: "Apocalypse Please", GroupID: 3, Group: "Muse", AlbumID: &albumID, DiscNumber: 1, TrackNumber: 2},
+						{ID: 1, Song: "Time Is Running Out", GroupID: 3, Group: "Muse", AlbumID: &albumID, DiscNumber: 1, TrackNumber: 3},
+					}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7/tracks", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[
+				{"id":2,"song":"Apocalypse Please","group_id":3,"group":"Muse","album_id":7,"disc_number":1,"track_number":2},
+				{"id":1,"song":"Time Is Running Out","group_id":3,"group":"Muse","album_id":7,"disc_number":1,"track_number":3}
+			]`,
+		},
+		{
+			name: "Empty album",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumTracks(gomock.Any(), albumID).Return([]entities.Song{}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7/tracks", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/abc/tracks", nil), map[string]string{"id": "abc"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "id", "album id must be a positive integer", "/albums/abc/tracks"),
+		},
+		{
+			name: "Album not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumTracks(gomock.Any(), albumID).Return(nil, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7/tracks", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "album not found", "/albums/7/tracks"),
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumTracks(gomock.Any(), albumID).Return(nil, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/albums/7/tracks", nil), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/albums/7/tracks"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.GetAlbumTracks(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"strconv"
+)
+
+// AlbumsListGet godoc
+// @Summary Returns list of albums
+// @Description filtration and pagination are supported
+// @Produce json
+// @Param title query string false "Part of the album title"
+// @Param group query string false "Part of the group name"
+// @Param group_id query uint64 false "Group ID"
+// @Param release_date query string false "Release date"
+// @Param offset query int false "Offset"
+// @Param limit query int false "Limit"
+// @Success 200 {array} entities.Album "Albums list"
+// @Failure 204 {object} nil "No albums found"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /albums [get]
+// @Router /api/v1/albums [get]
+func (h *handler) AlbumsListGet(w http.ResponseWriter, r *http.Request) {
+	//get filter from request
+	query := r.URL.Query()
+	filter := entities.Album{
+		Title:       query.Get("title"),
+		Group:       query.Get("group"),
+		ReleaseDate: query.Get("release_date"),
+	}
+	var err error
+	if groupID := query.Get("group_id"); groupID != "" {
+		filter.GroupID, err = strconv.ParseUint(groupID, 10, 64)
+		if err != nil {
+			h.logger.Debugf("invalid group_id: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "group_id", "group_id must be a positive integer")
+			return
+		}
+	}
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return
+	}
+	limit, err := intQueryParam(r, "limit", -1)
+	if err != nil {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer")
+		return
+	}
+
+	//get albums list
+	albums, err := h.storage.GetAlbumList(r.Context(), filter, offset, limit)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no albums with requested params: %v", err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed get albums: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(albums)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
@@ -0,0 +1,168 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_AlbumsListGet(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	albums := []entities.Album{
+		{
+			ID:          1,
+			Title:       "Absolution",
+			GroupID:     3,
+			Group:       "Muse",
+			ReleaseDate: "15.09.2003",
+		},
+		{
+			ID:        2,
+			Title:     "Showbiz",
+			GroupID:   3,
+			Group:     "Muse",
+			CoverLink: "https://example.com/showbiz.jpg",
+		},
+	}
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumList(gomock.Any(), entities.Album{Title: "abs", Group: "muse", GroupID: 3}, 10, 5).Return(albums, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/albums?title=abs&group=muse&group_id=3&offset=10&limit=5", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[
+				{"id":1,"title":"Absolution","group_id":3,"group":"Muse","release_date":"15.09.2003"},
+				{"id":2,"title":"Showbiz","group_id":3,"group":"Muse","cover_link":"https://example.com/showbiz.jpg"}
+			]`,
+		},
+		{
+			name: "No params",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumList(gomock.Any(), entities.Album{}, 0, -1).Return(albums, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/albums", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[
+				{"id":1,"title":"Absolution","group_id":3,"group":"Muse","release_date":"15.09.2003"},
+				{"id":2,"title":"Showbiz","group_id":3,"group":"Muse","cover_link":"https://example.com/showbiz.jpg"}
+			]`,
+		},
+		{
+			name: "Bad group ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/albums?group_id=muse", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "group_id", "group_id must be a positive integer", "/albums"),
+		},
+		{
+			name: "Bad offset",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/albums?offset=-1", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer", "/albums"),
+		},
+		{
+			name: "No albums found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/albums?title=load", nil),
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
+		{
+			name: "Storage error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetAlbumList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/albums", nil),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/albums"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			h := handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+
+			h.AlbumsListGet(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package httphandlers
+
+import (
+	"github.com/go-chi/chi/middleware"
+	"musiclib/internal/app/entities"
+	"net/http"
+	"strings"
+)
+
+// actorHeader names who makes the request, it is recorded in song revisions.
+const actorHeader = "X-Actor"
+
+// maxActorLength limits the actor recorded in song revisions.
+const maxActorLength = 100
+
+// auditInfo passes the actor and the request ID to the storage, so they are recorded with song changes.
+// It goes after middleware.RequestID.
+func auditInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(actorHeader))
+		if runes := []rune(actor); len(runes) > maxActorLength {
+			actor = string(runes[:maxActorLength])
+		}
+		ctx := entities.WithAuditInfo(r.Context(), entities.AuditInfo{
+			Actor:     actor,
+			RequestID: middleware.GetReqID(r.Context()),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
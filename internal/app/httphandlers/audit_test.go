@@ -0,0 +1,37 @@
+package httphandlers
+
+import (
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_auditInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    string
+		expected string
+	}{
+		{name: "Actor", actor: " admin ", expected: "admin"},
+		{name: "Anonymous", actor: "", expected: ""},
+		{name: "Too long", actor: strings.Repeat("я", maxActorLength+1), expected: strings.Repeat("я", maxActorLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info entities.AuditInfo
+			handler := middleware.RequestID(auditInfo(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				info = entities.AuditInfoFromContext(r.Context())
+			})))
+			r := httptest.NewRequest("GET", "/api/v1/songs/1", nil)
+			r.Header.Set(actorHeader, tt.actor)
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.Equal(t, tt.expected, info.Actor)
+			assert.NotEmpty(t, info.RequestID)
+		})
+	}
+}
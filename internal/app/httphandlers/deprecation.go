@@ -0,0 +1,14 @@
+package httphandlers
+
+import "net/http"
+
+// deprecated marks responses of a legacy route as deprecated and points clients to the route which replaces it.
+func deprecated(successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"strconv"
+)
+
+// GetDuplicates godoc
+// @Summary Returns likely duplicate songs
+// @Description Sets of songs of a group whose names are similar after ignoring case, spaces and diacritics,
+// @Description like "Starlight" and "Starlight (Live)". Songs created before duplicates were refused may have the same name,
+// @Description they have a similarity of 1. Every set suggests the song with the richest metadata to keep,
+// @Description the others can be merged into it by POST /api/v1/songs/merge. Sets go in the order of their smallest song IDs.
+// @Description An empty list is returned if there are no duplicates.
+// @Produce json
+// @Param similarity query number false "Minimal trigram similarity of names above 0 and up to 1, 0.5 by default"
+// @Param offset query int false "Offset"
+// @Param limit query int false "Page size, 50 by default, 500 at most"
+// @Success 200 {array} entities.DuplicateSongs "Sets of likely duplicates"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/duplicates [get]
+func (h *handler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	similarity := entities.DuplicateSimilarity
+	if param := r.URL.Query().Get("similarity"); param != "" {
+		var err error
+		similarity, err = strconv.ParseFloat(param, 64)
+		if err != nil || similarity <= 0 || similarity > 1 {
+			h.logger.Debugf("invalid similarity `%v`: %v", param, err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "similarity", "similarity must be above 0 and up to 1")
+			return
+		}
+	}
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return
+	}
+	limit, err := intQueryParam(r, "limit", 0)
+	if err != nil {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer")
+		return
+	}
+	limit = entities.SongListQuery{Limit: limit}.PageLimit()
+
+	//get duplicates
+	duplicates, err := h.storage.GetDuplicateSongs(r.Context(), similarity, offset, limit)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		duplicates = []entities.DuplicateSongs{}
+	} else if err != nil {
+		h.logger.Debugf("failed to get duplicate songs: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(duplicates)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
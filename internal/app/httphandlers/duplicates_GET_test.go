@@ -0,0 +1,137 @@
+package httphandlers
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GetDuplicates(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetDuplicateSongs(gomock.Any(), entities.DuplicateSimilarity, 0, entities.DefaultPageLimit).Return([]entities.DuplicateSongs{
+						{
+							Similarity: 1,
+							KeepID:     3,
+							Songs: []entities.Song{
+								{ID: 1, Song: "Hysteria", GroupID: 2, Group: "Muse"},
+								{ID: 3, Song: "HYSTERIA", GroupID: 2, Group: "Muse", Text: "It's bugging me"},
+							},
+						},
+					}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/duplicates", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"similarity":1,"keep_id":3,"songs":[{"id":1,"song":"Hysteria","group_id":2,"group":"Muse"},
+				{"id":3,"song":"HYSTERIA","group_id":2,"group":"Muse","text":"It's bugging me"}]}]`,
+		},
+		{
+			name: "No duplicates",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetDuplicateSongs(gomock.Any(), 0.8, 10, 5).Return(nil, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/duplicates?similarity=0.8&offset=10&limit=5", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name: "Bad similarity",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/duplicates?similarity=0", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "similarity", "similarity must be above 0 and up to 1", "/api/v1/duplicates"),
+		},
+		{
+			name: "Bad offset",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/duplicates?offset=-1", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer", "/api/v1/duplicates"),
+		},
+		{
+			name: "Storage Error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetDuplicateSongs(gomock.Any(), entities.DuplicateSimilarity, 0, entities.DefaultPageLimit).Return(nil, errors.New("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/duplicates", nil),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/duplicates"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.GetDuplicates(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package httphandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errInvalidETag is returned for an If-Match value which is not an ETag made by songETag.
+var errInvalidETag = errors.New("invalid ETag")
+
+// songETag returns a strong ETag of the song version.
+func songETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseSongETag returns the version of a song ETag, "*" gives 0 which matches any version.
+func parseSongETag(etag string) (int64, error) {
+	etag = strings.TrimSpace(etag)
+	if etag == "*" {
+		return 0, nil
+	}
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, errInvalidETag
+	}
+	version, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidETag
+	}
+	return version, nil
+}
+
+// writeETag gives the song version to the client. Songs of storages without versions have none.
+func writeETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", songETag(version))
+	}
+}
+
+// notModified tells whether If-None-Match of the request has the current version of the song.
+func notModified(r *http.Request, version int64) bool {
+	if version <= 0 {
+		return false
+	}
+	for _, etag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+		if etag == "*" || etag == songETag(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion returns the song version of the If-Match header, 0 if the header is absent or "*".
+// An invalid header answers 412, as no version can match it.
+func (h *handler) ifMatchVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	value := r.Header.Get("If-Match")
+	if value == "" {
+		return 0, true
+	}
+	version, err := parseSongETag(value)
+	if err != nil {
+		h.logger.Debugf("invalid If-Match `%v`: %v", value, err)
+		h.writeProblem(w, r, http.StatusPreconditionFailed, codePreconditionFailed, "", "If-Match must be an ETag of the song or *")
+		return 0, false
+	}
+	return version, true
+}
+
+// requireIfMatch is ifMatchVersion which answers 428 when the header is absent.
+func (h *handler) requireIfMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	if r.Header.Get("If-Match") == "" {
+		h.logger.Debugf("If-Match is absent")
+		h.writeProblem(w, r, http.StatusPreconditionRequired, codePreconditionRequired, "", "If-Match with an ETag of the song is required")
+		return 0, false
+	}
+	return h.ifMatchVersion(w, r)
+}
+
+// writeVersionMismatch answers 412 when the song has changed since the version the client has.
+func (h *handler) writeVersionMismatch(w http.ResponseWriter, r *http.Request) {
+	h.writeProblem(w, r, http.StatusPreconditionFailed, codePreconditionFailed, "", "song was changed since the version in If-Match")
+}
@@ -0,0 +1,65 @@
+package httphandlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withIfMatch returns the request with the If-Match header.
+func withIfMatch(r *http.Request, etag string) *http.Request {
+	r.Header.Set("If-Match", etag)
+	return r
+}
+
+func Test_parseSongETag(t *testing.T) {
+	tests := []struct {
+		etag     string
+		expected int64
+		wantErr  bool
+	}{
+		{etag: songETag(3), expected: 3},
+		{etag: ` "12" `, expected: 12},
+		{etag: `*`, expected: 0},
+		{etag: `W/"3"`, wantErr: true},
+		{etag: `3`, wantErr: true},
+		{etag: `"0"`, wantErr: true},
+		{etag: `"3", "4"`, wantErr: true},
+		{etag: `"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.etag, func(t *testing.T) {
+			version, err := parseSongETag(tt.etag)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidETag)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
+
+func Test_notModified(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		version     int64
+		expected    bool
+	}{
+		{name: "Same version", ifNoneMatch: `"3"`, version: 3, expected: true},
+		{name: "Weak comparison", ifNoneMatch: `"2", W/"3"`, version: 3, expected: true},
+		{name: "Any", ifNoneMatch: `*`, version: 3, expected: true},
+		{name: "Other version", ifNoneMatch: `"2"`, version: 3, expected: false},
+		{name: "Absent", ifNoneMatch: ``, version: 3, expected: false},
+		{name: "Without version", ifNoneMatch: `*`, version: 0, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/songs/1", nil)
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			assert.Equal(t, tt.expected, notModified(r, tt.version))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package httphandlers
+
+import (
+	"errors"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// DeleteGroup godoc
+// @Summary Deletes the group
+// @Description Deletes group by id. Groups which still have songs or albums can not be deleted.
+// @Produce plain
+// @Param id path uint64 true "Group ID"
+// @Success 200 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 409 {object} ProblemDetails "Group still has songs or albums"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /groups/{id} [delete]
+// @Router /api/v1/groups/{id} [delete]
+func (h *handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid group id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "group id must be a positive integer")
+		return
+	}
+
+	//delete
+	err = h.storage.RemoveGroup(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no groups found with ID `%v`, err: %v", id, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "group not found")
+		return
+	} else if errors.Is(err, dberrors.NewConflictErr()) {
+		h.logger.Debugf("group `%v` still has songs or albums", id)
+		h.writeProblem(w, r, http.StatusConflict, codeConflict, "", "group still has songs or albums")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to remove group in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusOK)
+	return
+}
@@ -0,0 +1,49 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// GetGroup godoc
+// @Summary Returns the group
+// @Description Returns group by id.
+// @Produce json
+// @Param id path uint64 true "Group ID"
+// @Success 200 {object} entities.Group "Group"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /groups/{id} [get]
+// @Router /api/v1/groups/{id} [get]
+func (h *handler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid group id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "group id must be a positive integer")
+		return
+	}
+
+	//get group
+	group, err := h.storage.GetGroup(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no group found with id %d", id)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "group not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to get group from db: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(group)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
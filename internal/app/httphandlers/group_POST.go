@@ -0,0 +1,93 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PostGroup godoc
+// @Summary Creates new group
+// @Description Creates new group. Group names are unique regardless of case and extra spaces.
+// @Accept  json
+// @Produce json
+// @Param group body entities.Group true "JSON group data"
+// @Success 201 {object} entities.IDMessage "Group ID"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 409 {object} ProblemDetails "Group with the same name or alias already exists"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /groups [post]
+// @Router /api/v1/groups [post]
+func (h *handler) PostGroup(w http.ResponseWriter, r *http.Request) {
+	//get group from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	group := entities.Group{}
+	err = json.Unmarshal(bodyBytes, &group)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
+		return
+	}
+	if strings.TrimSpace(group.Name) == "" {
+		h.logger.Debugf("group name is empty")
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "name", "group name is empty")
+		return
+	}
+	err = validateGroup(group)
+	if err != nil {
+		h.logger.Debugf("invalid group: %v", err)
+		h.writeValidationProblem(w, r, err)
+		return
+	}
+	group.ID = 0
+
+	//save
+	id, err := h.storage.SaveGroup(r.Context(), group)
+	if errors.Is(err, dberrors.NewConflictErr()) {
+		h.logger.Debugf("group `%v` already exists", group.Name)
+		h.writeProblem(w, r, http.StatusConflict, codeConflict, "name", "group with the same name or alias already exists")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to create group in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(entities.IDMessage{
+		ID: id,
+	})
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonAnswer)
+	return
+}
+
+// validateGroup checks optional group fields.
+func validateGroup(group entities.Group) error {
+	if group.FormedYear < 0 || group.FormedYear > time.Now().Year() {
+		return fieldError{field: "formed_year", message: fmt.Sprintf("formed year `%v` is out of range", group.FormedYear)}
+	}
+	for _, alias := range group.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			return fieldError{field: "aliases", message: "alias is empty"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// PutGroup godoc
+// @Summary Updates the group
+// @Description Updates group by id, empty fields are left unchanged.
+// @Accept  json
+// @Produce plain
+// @Param id path uint64 true "Group ID"
+// @Param group body entities.Group true "JSON group data"
+// @Success 200 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 409 {object} ProblemDetails "Group with the same name or alias already exists"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /groups/{id} [put]
+// @Router /api/v1/groups/{id} [put]
+func (h *handler) PutGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid group id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "group id must be a positive integer")
+		return
+	}
+
+	//get group from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	group := entities.Group{}
+	err = json.Unmarshal(bodyBytes, &group)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
+		return
+	}
+	err = validateGroup(group)
+	if err != nil {
+		h.logger.Debugf("invalid group: %v", err)
+		h.writeValidationProblem(w, r, err)
+		return
+	}
+	group.ID = id
+
+	//update
+	err = h.storage.UpdateGroup(r.Context(), group)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("group was`nt found in db, err: %v", err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "group not found")
+		return
+	} else if errors.Is(err, dberrors.NewConflictErr()) {
+		h.logger.Debugf("group `%v` already exists", group.Name)
+		h.writeProblem(w, r, http.StatusConflict, codeConflict, "name", "group with the same name or alias already exists")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to update group in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusOK)
+	return
+}
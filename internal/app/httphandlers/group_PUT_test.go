@@ -0,0 +1,138 @@
+package httphandlers
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_PutGroup(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateGroup(gomock.Any(), entities.Group{
+						ID:      3,
+						Country: "UK",
+					}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/groups/3", bytes.NewBufferString(`{"id":100,"country":"UK"}`)), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/groups/x", bytes.NewBufferString(`{"country":"UK"}`)), map[string]string{"id": "x"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Bad JSON",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/groups/3", bytes.NewBufferString(`{"country":`)), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateGroup(gomock.Any(), gomock.Any()).Return(dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/groups/3", bytes.NewBufferString(`{"country":"UK"}`)), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Name is taken",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateGroup(gomock.Any(), gomock.Any()).Return(dberrors.NewConflictErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/groups/3", bytes.NewBufferString(`{"name":"Muse"}`)), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateGroup(gomock.Any(), gomock.Any()).Return(fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PUT", "/groups/3", bytes.NewBufferString(`{"country":"UK"}`)), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.PutGroup(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+		})
+	}
+}
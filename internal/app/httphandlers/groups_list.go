@@ -0,0 +1,73 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// GroupsListGet godoc
+// @Summary Returns list of groups
+// @Description filtration and pagination are supported
+// @Produce json
+// @Param name query string false "Part of the group name"
+// @Param country query string false "Country"
+// @Param formed_year query int false "Year the group was formed"
+// @Param offset query int false "Offset"
+// @Param limit query int false "Limit"
+// @Success 200 {array} entities.Group "Groups list"
+// @Failure 204 {object} nil "No groups found"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /groups [get]
+// @Router /api/v1/groups [get]
+func (h *handler) GroupsListGet(w http.ResponseWriter, r *http.Request) {
+	//get filter from request
+	query := r.URL.Query()
+	filter := entities.Group{
+		Name:    query.Get("name"),
+		Country: query.Get("country"),
+	}
+	var err error
+	filter.FormedYear, err = intQueryParam(r, "formed_year", 0)
+	if err != nil {
+		h.logger.Debugf("invalid formed_year: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "formed_year", "formed_year must be an integer")
+		return
+	}
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return
+	}
+	limit, err := intQueryParam(r, "limit", -1)
+	if err != nil {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer")
+		return
+	}
+
+	//get groups list
+	groups, err := h.storage.GetGroupList(r.Context(), filter, offset, limit)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no groups with requested params: %v", err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed get groups: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(groups)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
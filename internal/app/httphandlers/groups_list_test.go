@@ -0,0 +1,151 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GroupsListGet(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	groups := []entities.Group{
+		{
+			ID:         1,
+			Name:       "Muse",
+			Country:    "UK",
+			FormedYear: 1994,
+		},
+		{
+			ID:      2,
+			Name:    "Museum",
+			Aliases: []string{"The Museum"},
+		},
+	}
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetGroupList(gomock.Any(), entities.Group{Name: "muse", FormedYear: 1994}, 10, 5).Return(groups, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/groups?name=muse&formed_year=1994&offset=10&limit=5", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[
+				{"id":1,"name":"Muse","country":"UK","formed_year":1994},
+				{"id":2,"name":"Museum","aliases":["The Museum"]}
+			]`,
+		},
+		{
+			name: "No params",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetGroupList(gomock.Any(), entities.Group{}, 0, -1).Return(groups, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/groups", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[
+				{"id":1,"name":"Muse","country":"UK","formed_year":1994},
+				{"id":2,"name":"Museum","aliases":["The Museum"]}
+			]`,
+		},
+		{
+			name: "Bad offset",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/groups?offset=-1", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer", "/groups"),
+		},
+		{
+			name: "No groups found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetGroupList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/groups?name=metallica", nil),
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
+		{
+			name: "Storage error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetGroupList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/groups", nil),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/groups"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			h := handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+
+			h.GroupsListGet(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/middleware"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem codes tell clients what exactly went wrong, several codes may share the same HTTP status.
+const (
+	codeInvalidBody          = "invalid_body"
+	codeInvalidParameter     = "invalid_parameter"
+	codeRequiredField        = "required_field"
+	codeInvalidField         = "invalid_field"
+	codeNotFound             = "not_found"
+	codeConflict             = "conflict"
+	codeDuplicateSong        = "duplicate_song"
+	codePreconditionFailed   = "precondition_failed"
+	codePreconditionRequired = "precondition_required"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codeUnprocessable        = "unprocessable"
+	codeUnavailable          = "unavailable"
+	codeTimeout              = "timeout"
+	codeInternal             = "internal_error"
+)
+
+// retryAfter is how long clients are asked to wait before retrying a request the storage could not serve.
+const retryAfter = time.Second
+
+// ProblemDetails is an RFC 7807 error response body extended with a problem code, a field and a request ID.
+type ProblemDetails struct {
+	Type      string `json:"type" example:"about:blank"`
+	Title     string `json:"title" example:"Bad Request"`
+	Status    int    `json:"status" example:"400"`
+	Detail    string `json:"detail,omitempty" example:"song name is empty"`
+	Instance  string `json:"instance,omitempty" example:"/api/v1/songs"`
+	Code      string `json:"code" example:"required_field"`
+	Field     string `json:"field,omitempty" example:"song"`
+	RequestID string `json:"request_id,omitempty" example:"host/abcdef-000001"`
+	// Retryable tells that the same request may succeed later, the Retry-After header tells when.
+	Retryable bool `json:"retryable,omitempty" example:"false"`
+	// ExistingID is the song a new or changed song would duplicate, the Location header points to it.
+	ExistingID uint64 `json:"existing_id,omitempty" example:"0"`
+}
+
+// fieldError is a validation error of a single request field.
+type fieldError struct {
+	field   string
+	message string
+}
+
+func (e fieldError) Error() string {
+	return e.field + ": " + e.message
+}
+
+// writeProblem answers with an application/problem+json body.
+func (h *handler) writeProblem(w http.ResponseWriter, r *http.Request, status int, code, field, detail string) {
+	h.writeProblemDetails(w, h.problem(r, status, code, field, detail))
+}
+
+// writeRetryableProblem answers with a problem which may go away, so the client is asked to retry after retryAfter.
+func (h *handler) writeRetryableProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	problem := h.problem(r, status, code, "", detail)
+	problem.Retryable = true
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	h.writeProblemDetails(w, problem)
+}
+
+// problem returns the problem details of the request.
+func (h *handler) problem(r *http.Request, status int, code, field, detail string) ProblemDetails {
+	return ProblemDetails{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		Field:     field,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+// writeProblemDetails answers with the problem as an application/problem+json body and its status.
+func (h *handler) writeProblemDetails(w http.ResponseWriter, problem ProblemDetails) {
+	jsonAnswer, err := json.Marshal(problem)
+	if err != nil {
+		h.logger.Errorf("failed to marshal problem details: %v", err)
+		w.WriteHeader(problem.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	w.Write(jsonAnswer)
+}
+
+// writeValidationProblem answers 400 for a validation error, fieldError and entities.FieldError give the name of the invalid field.
+func (h *handler) writeValidationProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var fErr fieldError
+	if errors.As(err, &fErr) {
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidField, fErr.field, fErr.message)
+		return
+	}
+	var entityErr entities.FieldError
+	if errors.As(err, &entityErr) {
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidField, entityErr.Field, entityErr.Message)
+		return
+	}
+	h.writeProblem(w, r, http.StatusBadRequest, codeInvalidField, "", err.Error())
+}
+
+// writeBodyProblem answers 400 for a body which could not be decoded.
+// entities.FieldError of a field which could not be parsed, like a release date, gives its name.
+func (h *handler) writeBodyProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var entityErr entities.FieldError
+	if errors.As(err, &entityErr) {
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidField, entityErr.Field, entityErr.Message)
+		return
+	}
+	h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
+}
+
+// writeParameterProblem answers 400 for an invalid query parameter, entities.FieldError gives its name.
+func (h *handler) writeParameterProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var entityErr entities.FieldError
+	if errors.As(err, &entityErr) {
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, entityErr.Field, entityErr.Message)
+		return
+	}
+	h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "", err.Error())
+}
+
+// writeInternalError answers 500 without revealing the reason to the client, it is logged instead.
+func (h *handler) writeInternalError(w http.ResponseWriter, r *http.Request) {
+	h.writeProblem(w, r, http.StatusInternalServerError, codeInternal, "", "internal server error")
+}
+
+// writeStorageError answers for an error of the storage by its dberrors.Kind, errors of an unknown kind answer 500.
+// Handlers answer errors they expect, like a missing song, with their own details before falling back to it.
+func (h *handler) writeStorageError(w http.ResponseWriter, r *http.Request, err error) {
+	switch dberrors.KindOf(err) {
+	case dberrors.KindNotFound:
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "content not found")
+	case dberrors.KindConflict:
+		var duplicateErr entities.DuplicateSongError
+		if errors.As(err, &duplicateErr) {
+			h.writeDuplicateProblem(w, r, duplicateErr.ID)
+			return
+		}
+		h.writeProblem(w, r, http.StatusConflict, codeConflict, "", "request conflicts with existing content")
+	case dberrors.KindVersionMismatch:
+		h.writeProblem(w, r, http.StatusPreconditionFailed, codePreconditionFailed, "", "content was changed since the expected version")
+	case dberrors.KindInvalid:
+		h.writeProblem(w, r, http.StatusUnprocessableEntity, codeUnprocessable, "", "request refers to missing content or breaks a constraint")
+	case dberrors.KindUnavailable:
+		h.logger.Warnf("storage is unavailable: %v", err)
+		h.writeRetryableProblem(w, r, http.StatusServiceUnavailable, codeUnavailable, "storage is unavailable, retry later")
+	case dberrors.KindTimeout:
+		h.logger.Warnf("storage timed out: %v", err)
+		h.writeRetryableProblem(w, r, http.StatusGatewayTimeout, codeTimeout, "storage did not answer in time, retry later")
+	default:
+		h.writeInternalError(w, r)
+	}
+}
+
+// writeDuplicateProblem answers 409 for a song which would duplicate the existing one.
+func (h *handler) writeDuplicateProblem(w http.ResponseWriter, r *http.Request, existingID uint64) {
+	problem := h.problem(r, http.StatusConflict, codeDuplicateSong, "song", fmt.Sprintf("group already has song %d of the same name", existingID))
+	problem.ExistingID = existingID
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/songs/%d", existingID))
+	h.writeProblemDetails(w, problem)
+}
+
+// requestIDHeader returns the request ID assigned by middleware.RequestID to the client.
+func requestIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := middleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(middleware.RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
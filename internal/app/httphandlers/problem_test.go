@@ -0,0 +1,136 @@
+package httphandlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// problemBody returns the JSON a handler answers with when it calls writeProblem without a request ID.
+func problemBody(status int, code, field, detail, instance string) string {
+	body, _ := json.Marshal(ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+		Field:    field,
+	})
+	return string(body)
+}
+
+// assertBody compares JSON bodies regardless of formatting and other bodies as is.
+func assertBody(t *testing.T, expected string, w *httptest.ResponseRecorder) {
+	t.Helper()
+	if expected != "" && json.Valid([]byte(expected)) {
+		assert.JSONEq(t, expected, w.Body.String())
+		return
+	}
+	assert.Equal(t, expected, w.Body.String())
+}
+
+func Test_handler_writeProblem(t *testing.T) {
+	h := &handler{
+		logger: zaptest.NewLogger(t).Sugar(),
+	}
+
+	t.Run("With request ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/api/v1/songs", nil)
+		r = r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, "host/abc-000001"))
+
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "song", "song name is empty")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type":"about:blank",
+			"title":"Bad Request",
+			"status":400,
+			"detail":"song name is empty",
+			"instance":"/api/v1/songs",
+			"code":"required_field",
+			"field":"song",
+			"request_id":"host/abc-000001"
+		}`, w.Body.String())
+	})
+
+	t.Run("Validation error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/groups", nil)
+
+		h.writeValidationProblem(w, r, fieldError{field: "formed_year", message: "formed year `3000` is out of range"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, problemBody(http.StatusBadRequest, codeInvalidField, "formed_year", "formed year `3000` is out of range", "/groups"), w.Body.String())
+	})
+
+	t.Run("Unknown route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/v1/unknown", nil)
+
+		NewHTTPRouter(h.logger, nil, nil, true).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+		assert.NotEmpty(t, w.Header().Get(middleware.RequestIDHeader))
+		problem := ProblemDetails{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, codeNotFound, problem.Code)
+		assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), problem.RequestID)
+	})
+}
+
+func Test_handler_writeStorageError(t *testing.T) {
+	h := &handler{
+		logger: zaptest.NewLogger(t).Sugar(),
+	}
+
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatus     int
+		expectedCode       string
+		expectedRetryAfter string
+		expectedExistingID uint64
+	}{
+		{name: "Not found", err: dberrors.NewNotFoundErr(), expectedStatus: http.StatusNotFound, expectedCode: codeNotFound},
+		{name: "Conflict", err: fmt.Errorf("failed to save: %w", dberrors.NewConflictErr()), expectedStatus: http.StatusConflict, expectedCode: codeConflict},
+		{name: "Duplicate song", err: dberrors.Wrap(dberrors.KindConflict, entities.DuplicateSongError{ID: 7}), expectedStatus: http.StatusConflict, expectedCode: codeDuplicateSong, expectedExistingID: 7},
+		{name: "Version mismatch", err: dberrors.NewVersionMismatchErr(), expectedStatus: http.StatusPreconditionFailed, expectedCode: codePreconditionFailed},
+		{name: "Invalid", err: dberrors.NewInvalidErr(fmt.Errorf("album 3 does not exist")), expectedStatus: http.StatusUnprocessableEntity, expectedCode: codeUnprocessable},
+		{name: "Unavailable", err: dberrors.NewUnavailableErr(fmt.Errorf("connection refused")), expectedStatus: http.StatusServiceUnavailable, expectedCode: codeUnavailable, expectedRetryAfter: "1"},
+		{name: "Timeout", err: dberrors.NewTimeoutErr(fmt.Errorf("statement timeout")), expectedStatus: http.StatusGatewayTimeout, expectedCode: codeTimeout, expectedRetryAfter: "1"},
+		{name: "Deadline exceeded", err: context.DeadlineExceeded, expectedStatus: http.StatusGatewayTimeout, expectedCode: codeTimeout, expectedRetryAfter: "1"},
+		{name: "Unknown", err: fmt.Errorf("test error"), expectedStatus: http.StatusInternalServerError, expectedCode: codeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/api/v1/songs/3", nil)
+
+			h.writeStorageError(w, r, tt.err)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedRetryAfter, w.Header().Get("Retry-After"))
+			problem := ProblemDetails{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.expectedCode, problem.Code)
+			assert.Equal(t, tt.expectedRetryAfter != "", problem.Retryable)
+			assert.Equal(t, tt.expectedExistingID, problem.ExistingID)
+			if tt.expectedExistingID != 0 {
+				assert.Equal(t, fmt.Sprintf("/api/v1/songs/%d", tt.expectedExistingID), w.Header().Get("Location"))
+			}
+			assert.NotContains(t, w.Body.String(), "connection refused")
+		})
+	}
+}
@@ -2,31 +2,91 @@ package httphandlers
 
 import (
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 	"go.uber.org/zap"
 	"musiclib/internal/app/requiredinterfaces"
+	"net/http"
 )
 
 type handler struct {
 	storage           requiredinterfaces.SongStorage
 	logger            *zap.SugaredLogger
 	extraDataProvider requiredinterfaces.ExtraDataProvider
+	// asyncEnrichment makes new songs pending instead of asking extraDataProvider in the request.
+	asyncEnrichment bool
 }
 
-func NewHTTPRouter(logger *zap.SugaredLogger, storage requiredinterfaces.SongStorage, extraDataProvider requiredinterfaces.ExtraDataProvider) chi.Router {
+// NewHTTPRouter builds the router. With asyncEnrichment new songs are enriched by background workers.
+func NewHTTPRouter(logger *zap.SugaredLogger, storage requiredinterfaces.SongStorage, extraDataProvider requiredinterfaces.ExtraDataProvider, asyncEnrichment bool) chi.Router {
 
 	h := &handler{
 		storage:           storage,
 		logger:            logger,
 		extraDataProvider: extraDataProvider,
+		asyncEnrichment:   asyncEnrichment,
 	}
 
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID, requestIDHeader, auditInfo)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "route not found")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		h.writeProblem(w, r, http.StatusMethodNotAllowed, codeInvalidParameter, "", "method is not allowed for the route")
+	})
 
-	r.Post("/song", h.PostSong)
-	r.Put("/song", h.PutSong)
-	r.Get("/lyrics", h.GetSongLyrics)
-	r.Delete("/song", h.DeleteSong)
-	r.Post("/songs", h.SongsListGet)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Post("/songs", h.PostSong)
+		r.Get("/songs", h.GetSongs)
+		r.Post("/songs/import", h.PostSongsImport)
+		r.Get("/songs/export", h.GetSongsExport)
+		r.Post("/songs/merge", h.PostSongsMerge)
+		r.Get("/songs/{id}", h.GetSong)
+		r.Put("/songs/{id}", h.PutSongByID)
+		r.Patch("/songs/{id}", h.PatchSong)
+		r.Delete("/songs/{id}", h.DeleteSongByID)
+		r.Get("/songs/{id}/lyrics", h.GetSongLyricsByID)
+		r.Post("/songs/{id}/enrichment", h.PostSongEnrichment)
+		r.Post("/songs/{id}/restore", h.PostSongRestore)
+		r.Get("/songs/{id}/history", h.GetSongHistory)
+		r.Post("/songs/{id}/revert", h.PostSongRevert)
+		r.Get("/trash", h.GetTrash)
+		r.Get("/duplicates", h.GetDuplicates)
+		r.Get("/search", h.SearchSongs)
+
+		groupRoutes(r, h)
+		albumRoutes(r, h)
+	})
+
+	//legacy routes, kept for existing clients
+	r.With(deprecated("/api/v1/songs")).Post("/song", h.PostSong)
+	r.With(deprecated("/api/v1/songs/{id}")).Put("/song", h.PutSong)
+	r.With(deprecated("/api/v1/songs/{id}/lyrics")).Get("/lyrics", h.GetSongLyrics)
+	r.With(deprecated("/api/v1/songs/{id}")).Delete("/song", h.DeleteSong)
+	r.With(deprecated("/api/v1/songs")).Post("/songs", h.SongsListGet)
+	r.With(deprecated("/api/v1/groups")).Group(func(r chi.Router) {
+		groupRoutes(r, h)
+	})
+	r.With(deprecated("/api/v1/albums")).Group(func(r chi.Router) {
+		albumRoutes(r, h)
+	})
 
 	return r
 }
+
+func groupRoutes(r chi.Router, h *handler) {
+	r.Post("/groups", h.PostGroup)
+	r.Get("/groups", h.GroupsListGet)
+	r.Get("/groups/{id}", h.GetGroup)
+	r.Put("/groups/{id}", h.PutGroup)
+	r.Delete("/groups/{id}", h.DeleteGroup)
+}
+
+func albumRoutes(r chi.Router, h *handler) {
+	r.Post("/albums", h.PostAlbum)
+	r.Get("/albums", h.AlbumsListGet)
+	r.Get("/albums/{id}", h.GetAlbum)
+	r.Put("/albums/{id}", h.PutAlbum)
+	r.Delete("/albums/{id}", h.DeleteAlbum)
+	r.Get("/albums/{id}/tracks", h.GetAlbumTracks)
+}
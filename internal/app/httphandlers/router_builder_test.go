@@ -0,0 +1,212 @@
+package httphandlers
+
+import (
+	"bytes"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHTTPRouter(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	tests := []struct {
+		name               string
+		storage            func(c *gomock.Controller) requiredinterfaces.SongStorage
+		r                  *http.Request
+		expectedStatus     int
+		expectedDeprecated bool
+		expectedLink       string
+	}{
+		{
+			name: "Song by ID",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetSong(gomock.Any(), uint64(5)).Return(entities.Song{ID: 5}, nil)
+				return storage
+			},
+			r:              httptest.NewRequest("GET", "/api/v1/songs/5", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Delete song by ID",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().RemoveSong(gomock.Any(), uint64(5), int64(1)).Return(nil)
+				return storage
+			},
+			r:              withIfMatch(httptest.NewRequest("DELETE", "/api/v1/songs/5", nil), `"1"`),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Replace song by ID",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{ID: 5, Song: "Hysteria", GroupID: 2, Version: 1}).Return(nil)
+				return storage
+			},
+			r:              withIfMatch(httptest.NewRequest("PUT", "/api/v1/songs/5", bytes.NewBufferString(`{"song":"Hysteria","group_id":2}`)), `"1"`),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Import songs",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{{Song: "Hysteria", Group: "Muse"}}).Return([]entities.ImportedSong{{ID: 5}}, nil)
+				return storage
+			},
+			r:              httptest.NewRequest("POST", "/api/v1/songs/import?format=csv", bytes.NewBufferString("group,song\nMuse,Hysteria\n")),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Export songs",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ExportSongs(gomock.Any(), entities.SongFilter{}, gomock.Any()).Return(nil)
+				return storage
+			},
+			r:              httptest.NewRequest("GET", "/api/v1/songs/export", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Merge songs",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().MergeSongs(gomock.Any(), uint64(5), []uint64{6}).Return(entities.Song{ID: 5, Version: 2}, nil)
+				return storage
+			},
+			r:              httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":5,"duplicate_ids":[6]}`)),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Duplicates",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetDuplicateSongs(gomock.Any(), entities.DuplicateSimilarity, 0, entities.DefaultPageLimit).Return(nil, dberrors.NewNotFoundErr())
+				return storage
+			},
+			r:              httptest.NewRequest("GET", "/api/v1/duplicates", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Re-trigger enrichment",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().EnqueueEnrichment(gomock.Any(), uint64(5)).Return(nil)
+				return storage
+			},
+			r:              httptest.NewRequest("POST", "/api/v1/songs/5/enrichment", nil),
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "Restore song",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().RestoreSong(gomock.Any(), uint64(5)).Return(nil)
+				return storage
+			},
+			r:              httptest.NewRequest("POST", "/api/v1/songs/5/restore", nil),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Song history",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetSongHistory(gomock.Any(), uint64(5), 0, entities.DefaultPageLimit).Return([]entities.SongRevision{}, nil)
+				return storage
+			},
+			r:              httptest.NewRequest("GET", "/api/v1/songs/5/history", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Trash",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetTrash(gomock.Any(), 0, entities.DefaultPageLimit).Return(nil, dberrors.NewNotFoundErr())
+				return storage
+			},
+			r:              httptest.NewRequest("GET", "/api/v1/trash", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Groups of v1",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetGroup(gomock.Any(), uint64(2)).Return(entities.Group{ID: 2}, nil)
+				return storage
+			},
+			r:              httptest.NewRequest("GET", "/api/v1/groups/2", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Legacy delete",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().RemoveSong(gomock.Any(), uint64(5), int64(0)).Return(nil)
+				return storage
+			},
+			r:                  httptest.NewRequest("DELETE", "/song", bytes.NewBufferString(`{"id":5}`)),
+			expectedStatus:     http.StatusOK,
+			expectedDeprecated: true,
+			expectedLink:       `</api/v1/songs/{id}>; rel="successor-version"`,
+		},
+		{
+			name: "Legacy list",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{Sort: entities.SongSort{Field: "id"}}).Return(entities.SongPage{Songs: []entities.Song{{ID: 5}}}, nil)
+				return storage
+			},
+			r:                  httptest.NewRequest("POST", "/songs", nil),
+			expectedStatus:     http.StatusOK,
+			expectedDeprecated: true,
+			expectedLink:       `</api/v1/songs>; rel="successor-version"`,
+		},
+		{
+			name: "Legacy groups",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().GetGroup(gomock.Any(), uint64(2)).Return(entities.Group{ID: 2}, nil)
+				return storage
+			},
+			r:                  httptest.NewRequest("GET", "/groups/2", nil),
+			expectedStatus:     http.StatusOK,
+			expectedDeprecated: true,
+			expectedLink:       `</api/v1/groups>; rel="successor-version"`,
+		},
+		{
+			name: "Verb overloaded route is not a part of v1",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              httptest.NewRequest("POST", "/api/v1/songs/5", nil),
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			r := NewHTTPRouter(sugar, tt.storage(c), mocks.NewMockExtraDataProvider(c), true)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, tt.r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedDeprecated {
+				assert.Equal(t, "true", w.Header().Get("Deprecation"))
+				assert.Equal(t, tt.expectedLink, w.Header().Get("Link"))
+			} else {
+				assert.Empty(t, w.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"strconv"
+)
+
+// SearchMessage is a page of found songs, the best matching songs go first.
+// Suggestions are similar song and group names, they are given when nothing matches the query exactly.
+type SearchMessage struct {
+	Results     []entities.SearchResult `json:"results"`
+	Suggestions []string                `json:"suggestions,omitempty"`
+}
+
+// SearchSongs godoc
+// @Summary Searches songs by their names and lyrics
+// @Description Full-text search, an empty list is returned if nothing was found.
+// @Description The query supports "quoted phrases", `or` and `-` to exclude a word.
+// @Description Each result has a headline: the best matching couplet with matched words wrapped in `<mark></mark>`.
+// @Description The fuzzy mode finds songs by misspelled song or group names, the most similar go first.
+// @Description If nothing matches the query exactly, similar names are suggested.
+// @Produce json
+// @Param q query string true "Search query"
+// @Param mode query string false "Search mode, fulltext by default" Enums(fulltext, fuzzy)
+// @Param similarity query number false "Minimal similarity of names from 0 to 1 for the fuzzy mode, 0.3 by default"
+// @Param lang query string false "Text search language, english by default" Enums(simple, english, russian)
+// @Param phrase query bool false "Search the whole query as a phrase"
+// @Param limit query int false "Page size, 50 by default, 500 at most"
+// @Param offset query int false "Offset"
+// @Success 200 {object} SearchMessage "Found songs"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/search [get]
+func (h *handler) SearchSongs(w http.ResponseWriter, r *http.Request) {
+	//get search query from request
+	params := r.URL.Query()
+	query := entities.SearchQuery{
+		Text:     params.Get("q"),
+		Mode:     entities.SearchMode(params.Get("mode")),
+		Language: params.Get("lang"),
+	}
+	var err error
+	if similarity := params.Get("similarity"); similarity != "" {
+		query.Similarity, err = strconv.ParseFloat(similarity, 64)
+		if err != nil {
+			h.logger.Debugf("invalid similarity: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "similarity", "similarity must be from 0 to 1")
+			return
+		}
+	}
+	err = query.Validate()
+	if err != nil {
+		h.logger.Debugf("invalid search query: %v", err)
+		h.writeParameterProblem(w, r, err)
+		return
+	}
+	if phrase := params.Get("phrase"); phrase != "" {
+		query.Phrase, err = strconv.ParseBool(phrase)
+		if err != nil {
+			h.logger.Debugf("invalid phrase: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "phrase", "phrase must be a boolean")
+			return
+		}
+	}
+	query.Offset, err = intQueryParam(r, "offset", 0)
+	if err != nil || query.Offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return
+	}
+	query.Limit, err = intQueryParam(r, "limit", entities.DefaultPageLimit)
+	if err != nil || query.Limit < 1 || query.Limit > entities.MaxPageLimit {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit",
+			fmt.Sprintf("limit must be an integer from 1 to %d", entities.MaxPageLimit))
+		return
+	}
+
+	//search songs
+	results, err := h.storage.SearchSongs(r.Context(), query)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no songs found by `%v`", query.Text)
+		results = []entities.SearchResult{}
+	} else if err != nil {
+		h.logger.Debugf("failed search songs: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//suggest similar names
+	answer := SearchMessage{Results: results}
+	if !matchesExactly(query, results) {
+		answer.Suggestions, err = h.storage.SuggestNames(r.Context(), query.Text, query.MinSimilarity(), entities.MaxSuggestions)
+		if err != nil {
+			h.logger.Debugf("failed suggest names: %v", err)
+		}
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(answer)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
+
+// matchesExactly tells whether the query was found as is. A full-text match is exact,
+// while a fuzzy one is exact only if a song or group is named as the query.
+func matchesExactly(query entities.SearchQuery, results []entities.SearchResult) bool {
+	if query.Mode != entities.SearchFuzzy {
+		return len(results) > 0
+	}
+	for _, result := range results {
+		if result.MatchesExactly(query.Text) {
+			return true
+		}
+	}
+	return false
+}
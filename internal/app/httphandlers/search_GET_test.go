@@ -0,0 +1,251 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_SearchSongs(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	results := []entities.SearchResult{
+		{
+			Song:     entities.Song{ID: 3, Song: "Supermassive Black Hole", GroupID: 1, Group: "Muse", Text: "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?"},
+			Rank:     0.5,
+			Headline: "Ooh baby, don't you know I <mark>suffer</mark>?\nOoh baby, can you hear me moan?",
+		},
+	}
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SearchSongs(gomock.Any(), entities.SearchQuery{Text: "suffer", Limit: entities.DefaultPageLimit}).Return(results, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=suffer", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"results":[{
+				"song":{"id":3,"song":"Supermassive Black Hole","group_id":1,"group":"Muse","text":"Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?"},
+				"rank":0.5,
+				"headline":"Ooh baby, don't you know I <mark>suffer</mark>?\nOoh baby, can you hear me moan?"
+			}]}`,
+		},
+		{
+			name: "Phrase in another language",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SearchSongs(gomock.Any(), entities.SearchQuery{Text: "группа крови", Language: "russian", Phrase: true, Offset: 10, Limit: 5}).
+						Return(nil, dberrors.NewNotFoundErr())
+					storage.EXPECT().SuggestNames(gomock.Any(), "группа крови", entities.DefaultSimilarity, entities.MaxSuggestions).Return([]string{"Группа крови"}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=%D0%B3%D1%80%D1%83%D0%BF%D0%BF%D0%B0+%D0%BA%D1%80%D0%BE%D0%B2%D0%B8&lang=russian&phrase=true&offset=10&limit=5", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"results":[],"suggestions":["Группа крови"]}`,
+		},
+		{
+			name: "Fuzzy",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SearchSongs(gomock.Any(), entities.SearchQuery{Text: "Metalica", Mode: entities.SearchFuzzy, Similarity: 0.4, Limit: entities.DefaultPageLimit}).
+						Return([]entities.SearchResult{{Song: entities.Song{ID: 5, Song: "One", GroupID: 2, Group: "Metallica"}, Rank: 0.6}}, nil)
+					storage.EXPECT().SuggestNames(gomock.Any(), "Metalica", 0.4, entities.MaxSuggestions).Return([]string{"Metallica"}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=Metalica&mode=fuzzy&similarity=0.4", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"results":[{"song":{"id":5,"song":"One","group_id":2,"group":"Metallica"},"rank":0.6}],"suggestions":["Metallica"]}`,
+		},
+		{
+			name: "Fuzzy exact match",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SearchSongs(gomock.Any(), entities.SearchQuery{Text: "metallica", Mode: entities.SearchFuzzy, Limit: entities.DefaultPageLimit}).
+						Return([]entities.SearchResult{{Song: entities.Song{ID: 5, Song: "One", GroupID: 2, Group: "Metallica"}, Rank: 1}}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=metallica&mode=fuzzy", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"results":[{"song":{"id":5,"song":"One","group_id":2,"group":"Metallica"},"rank":1}]}`,
+		},
+		{
+			name: "Suggestions error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SearchSongs(gomock.Any(), gomock.Any()).Return(nil, dberrors.NewNotFoundErr())
+					storage.EXPECT().SuggestNames(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=Metalica&mode=fuzzy", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"results":[]}`,
+		},
+		{
+			name: "Unknown mode",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=love&mode=regex", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "mode", "search mode must be `fulltext` or `fuzzy`", "/api/v1/search"),
+		},
+		{
+			name: "Bad similarity",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=love&mode=fuzzy&similarity=2", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "similarity", "similarity must be from 0 to 1", "/api/v1/search"),
+		},
+		{
+			name: "Empty query",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=+", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "q", "search query is empty", "/api/v1/search"),
+		},
+		{
+			name: "Unknown language",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=love&lang=klingon", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "lang", "language must be one of simple, english, russian", "/api/v1/search"),
+		},
+		{
+			name: "Bad phrase",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=love&phrase=maybe", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "phrase", "phrase must be a boolean", "/api/v1/search"),
+		},
+		{
+			name: "Bad limit",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=love&limit=0", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer from 1 to 500", "/api/v1/search"),
+		},
+		{
+			name: "Storage error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SearchSongs(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/search?q=love", nil),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/search"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			h := handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+
+			h.SearchSongs(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
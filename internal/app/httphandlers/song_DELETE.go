@@ -11,20 +11,30 @@ import (
 
 // DeleteSong godoc
 // @Summary Deletes the song
-// @Description Deletes current song by id.
+// @Description Moves current song by id to the trash, it can be restored until it is purged.
 // @Accept  json
 // @Produce plain
+// @Param If-Match header string false "ETag of the song"
 // @Param song body entities.IDMessage true "JSON song ID"
 // @Success 201 {object} nil "Success"
-// @Failure 400 {object} nil "Bad request"
-// @Failure 500 {object} nil "Internal server error"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 412 {object} ProblemDetails "Song was changed since the ETag in If-Match"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Deprecated
 // @Router /song [delete]
 func (h *handler) DeleteSong(w http.ResponseWriter, r *http.Request) {
+	version, ok := h.ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	//get idMessage from request
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		h.logger.Debugf("failed to read body: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeInternalError(w, r)
 		return
 	}
 	defer r.Body.Close()
@@ -33,24 +43,28 @@ func (h *handler) DeleteSong(w http.ResponseWriter, r *http.Request) {
 	err = json.Unmarshal(bodyBytes, &idMessage)
 	if err != nil {
 		h.logger.Debugf("failed to unmarshal body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
 		return
 	}
 	if idMessage.ID == 0 {
 		h.logger.Debugf("idMessage ID is empty")
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "id", "song id is empty")
 		return
 	}
 
 	//delete
-	err = h.storage.RemoveSong(r.Context(), idMessage.ID)
+	err = h.storage.RemoveSong(r.Context(), idMessage.ID, version)
 	if errors.Is(err, dberrors.NewNotFoundErr()) {
 		h.logger.Debugf("no songs found with ID `%v`, err: %v", idMessage.ID, err)
 		w.WriteHeader(http.StatusNoContent)
 		return
+	} else if errors.Is(err, dberrors.NewVersionMismatchErr()) {
+		h.logger.Debugf("song %d was changed since version %d", idMessage.ID, version)
+		h.writeVersionMismatch(w, r)
+		return
 	} else if err != nil {
 		h.logger.Debugf("failed to remove idMessage in database: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeStorageError(w, r, err)
 		return
 	}
 
@@ -58,3 +72,52 @@ func (h *handler) DeleteSong(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	return
 }
+
+// DeleteSongByID godoc
+// @Summary Deletes the song
+// @Description Moves the song to the trash, it can be restored by `POST /api/v1/songs/{id}/restore` until it is purged.
+// @Description If-Match must be the ETag of the song (or `*` to remove any version).
+// @Produce plain
+// @Param id path uint64 true "Song ID"
+// @Param If-Match header string true "ETag of the song"
+// @Success 204 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 412 {object} ProblemDetails "Song was changed since the ETag in If-Match"
+// @Failure 428 {object} ProblemDetails "If-Match is absent"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id} [delete]
+func (h *handler) DeleteSongByID(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+	version, ok := h.requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	//delete
+	err = h.storage.RemoveSong(r.Context(), id, version)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no songs found with ID `%v`, err: %v", id, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if errors.Is(err, dberrors.NewVersionMismatchErr()) {
+		h.logger.Debugf("song %d was changed since version %d", id, version)
+		h.writeVersionMismatch(w, r)
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to remove song in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusNoContent)
+	return
+}
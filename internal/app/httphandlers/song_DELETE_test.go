@@ -9,6 +9,7 @@ import (
 	"musiclib/internal/app/entities"
 	"musiclib/internal/app/requiredinterfaces"
 	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -39,7 +40,7 @@ func Test_handler_DeleteSong(t *testing.T) {
 					song := entities.Song{
 						ID: 1,
 					}
-					storage.EXPECT().RemoveSong(gomock.Any(), song.ID).Return(nil)
+					storage.EXPECT().RemoveSong(gomock.Any(), song.ID, int64(0)).Return(nil)
 					return storage
 				},
 			},
@@ -82,7 +83,7 @@ func Test_handler_DeleteSong(t *testing.T) {
 			fields: fields{
 				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
 					storage := mocks.NewMockSongStorage(c)
-					storage.EXPECT().RemoveSong(gomock.Any(), gomock.Any()).Return(errors.New("test error"))
+					storage.EXPECT().RemoveSong(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("test error"))
 					return storage
 				},
 			},
@@ -94,6 +95,21 @@ func Test_handler_DeleteSong(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "With If-Match",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RemoveSong(gomock.Any(), uint64(2), int64(4)).Return(dberrors.NewVersionMismatchErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(httptest.NewRequest("DELETE", "/song", bytes.NewBufferString(`{"id": 2}`)), `"4"`),
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
 		{
 			name: "Empty Request Body",
 			fields: fields{
@@ -125,3 +141,125 @@ func Test_handler_DeleteSong(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_DeleteSongByID(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RemoveSong(gomock.Any(), uint64(1), int64(2)).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("DELETE", "/api/v1/songs/1", nil), map[string]string{"id": "1"}), `"2"`),
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("DELETE", "/api/v1/songs/abc", nil), map[string]string{"id": "abc"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RemoveSong(gomock.Any(), uint64(1), int64(2)).Return(dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("DELETE", "/api/v1/songs/1", nil), map[string]string{"id": "1"}), `"2"`),
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Without If-Match",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("DELETE", "/api/v1/songs/1", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name: "Version mismatch",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RemoveSong(gomock.Any(), uint64(1), int64(2)).Return(dberrors.NewVersionMismatchErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("DELETE", "/api/v1/songs/1", nil), map[string]string{"id": "1"}), `"2"`),
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Storage Error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RemoveSong(gomock.Any(), uint64(1), int64(2)).Return(errors.New("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("DELETE", "/api/v1/songs/1", nil), map[string]string{"id": "1"}), `"2"`),
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := &handler{
+				storage: tt.fields.storage(ctrl),
+				logger:  sugar,
+			}
+
+			h.DeleteSongByID(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+		})
+	}
+}
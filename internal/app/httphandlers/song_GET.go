@@ -0,0 +1,55 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// GetSong godoc
+// @Summary Returns the song
+// @Description Returns song by id. The ETag header is the version of the song, it is given back in If-Match of updates.
+// @Produce json
+// @Param id path uint64 true "Song ID"
+// @Param If-None-Match header string false "ETag of the song the client has"
+// @Success 200 {object} entities.Song "Song"
+// @Success 304 {object} nil "Song was not changed since the ETag in If-None-Match"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id} [get]
+func (h *handler) GetSong(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+
+	//get song
+	song, err := h.storage.GetSong(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no song found with id %d", id)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to get song from db: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	writeETag(w, song.Version)
+	if notModified(r, song.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	jsonAnswer, err := json.Marshal(song)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
@@ -0,0 +1,185 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GetSong(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(3)).Return(entities.Song{
+						ID:          3,
+						Song:        "Hysteria",
+						GroupID:     1,
+						Group:       "Muse",
+						ReleaseDate: entities.MustParseReleaseDate("01.12.2003"),
+						Link:        "https://example.com/hysteria",
+					}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/3", nil), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":3,"song":"Hysteria","group_id":1,"group":"Muse","release_date":"2003-12-01","link":"https://example.com/hysteria"}`,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/abc", nil), map[string]string{"id": "abc"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer", "/api/v1/songs/abc"),
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(3)).Return(entities.Song{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/3", nil), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "song not found", "/api/v1/songs/3"),
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(3)).Return(entities.Song{}, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/3", nil), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs/3"),
+		},
+		{
+			name: "Db unavailable",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(3)).Return(entities.Song{}, dberrors.NewUnavailableErr(fmt.Errorf("test error")))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/3", nil), map[string]string{"id": "3"}),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"type":"about:blank","title":"Service Unavailable","status":503,"detail":"storage is unavailable, retry later","instance":"/api/v1/songs/3","code":"unavailable","retryable":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.GetSong(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
+
+func Test_handler_GetSong_ETag(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Normal",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":3,"song":"Hysteria","group":"Muse","version":4}`,
+		},
+		{
+			name:           "Not modified",
+			ifNoneMatch:    `"4"`,
+			expectedStatus: http.StatusNotModified,
+			expectedBody:   ``,
+		},
+		{
+			name:           "Modified",
+			ifNoneMatch:    `"3"`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":3,"song":"Hysteria","group":"Muse","version":4}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			storage := mocks.NewMockSongStorage(c)
+			storage.EXPECT().GetSong(gomock.Any(), uint64(3)).Return(entities.Song{ID: 3, Song: "Hysteria", Group: "Muse", Version: 4}, nil)
+			h := &handler{
+				storage: storage,
+				logger:  sugar,
+			}
+			w := httptest.NewRecorder()
+			r := withURLParams(httptest.NewRequest("GET", "/api/v1/songs/3", nil), map[string]string{"id": "3"})
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			h.GetSong(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+			assertBody(t, tt.expectedBody, w)
+		})
+	}
+}
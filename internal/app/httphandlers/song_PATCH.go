@@ -0,0 +1,123 @@
+package httphandlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// mergePatchContentType is the media type of an RFC 7396 merge patch.
+const mergePatchContentType = "application/merge-patch+json"
+
+// PatchSong godoc
+// @Summary Updates the song
+// @Description Updates song by id, absent and empty fields are left unchanged. id, version, enrichment_status and sources can not be changed.
+// @Description With Content-Type application/merge-patch+json the body is an RFC 7396 merge patch: absent fields are left unchanged and null clears a field.
+// @Description If-Match must be the ETag of the song (or `*` to update any version), so changes of others are not overwritten.
+// @Accept  json,application/merge-patch+json
+// @Produce plain
+// @Param id path uint64 true "Song ID"
+// @Param If-Match header string true "ETag of the song"
+// @Param song body SongReplaceMessage true "JSON song data"
+// @Success 204 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 412 {object} ProblemDetails "Song was changed since the ETag in If-Match"
+// @Failure 428 {object} ProblemDetails "If-Match is absent"
+// @Failure 409 {object} ProblemDetails "Group already has a song of the same name, Location points to it"
+// @Failure 422 {object} ProblemDetails "Request refers to missing content"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id} [patch]
+func (h *handler) PatchSong(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+	version, ok := h.requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	//get song from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	//both kinds of patches are checked and validated alike, only a merge patch can clear fields
+	apply := (*entities.Song).ApplyUpdate
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == mergePatchContentType {
+		apply = (*entities.Song).ApplyMergePatch
+	}
+	h.patchSong(w, r, id, version, bodyBytes, apply)
+}
+
+// patchSong applies the patch to the current song and replaces it.
+func (h *handler) patchSong(w http.ResponseWriter, r *http.Request, id uint64, version int64, patch []byte,
+	apply func(song *entities.Song, patch []byte) error) {
+	//get current song
+	song, err := h.storage.GetSong(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("song was`nt found in db, err: %v", err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to get song from database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+	if version != 0 && version != song.Version {
+		h.logger.Debugf("song %d was changed since version %d", id, version)
+		h.writeVersionMismatch(w, r)
+		return
+	}
+
+	//patch
+	err = apply(&song, patch)
+	if err != nil {
+		h.logger.Debugf("failed to apply patch: %v", err)
+		h.writeBodyProblem(w, r, err)
+		return
+	}
+	err = song.Validate()
+	if err != nil {
+		h.logger.Debugf("invalid song: %v", err)
+		h.writeValidationProblem(w, r, err)
+		return
+	}
+
+	//the version of the song read above, so changes made meanwhile are not overwritten
+	h.replaceSong(w, r, song)
+}
+
+// replaceSong replaces the song in the storage and answers 204.
+func (h *handler) replaceSong(w http.ResponseWriter, r *http.Request, song entities.Song) {
+	err := h.storage.ReplaceSong(r.Context(), song)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("song was`nt found in db, err: %v", err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if errors.Is(err, dberrors.NewVersionMismatchErr()) {
+		h.logger.Debugf("song %d was changed since version %d", song.ID, song.Version)
+		h.writeVersionMismatch(w, r)
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to replace song in database: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusNoContent)
+}
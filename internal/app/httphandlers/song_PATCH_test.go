@@ -0,0 +1,373 @@
+package httphandlers
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_PatchSong(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Text: "It's bugging me", Version: 3}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{
+						ID:      7,
+						Song:    "Hysteria",
+						GroupID: 2,
+						Text:    "It's bugging me",
+						Link:    "https://example.com/song",
+						Version: 3,
+					}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"text":"","link":"https://example.com/song"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/x", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "x"}), `"3"`),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Bad JSON",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Year-only release date",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{
+						ID:          7,
+						Song:        "Hysteria",
+						GroupID:     2,
+						ReleaseDate: entities.MustParseReleaseDate("2006"),
+						Version:     3,
+					}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"release_date":"2006"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Bad release date",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"release_date":"next week"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Negative track number",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"track_number":-1}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Any version",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Starlight", GroupID: 2, Version: 5}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 5}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "7"}), "*"),
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Without If-Match",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "7"}),
+			},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name: "Invalid If-Match",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "7"}), `W/"3"`),
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Version mismatch",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Starlight", GroupID: 2, Version: 3}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}).Return(dberrors.NewVersionMismatchErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Read-only field",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"enrichment_status":"done","group_id":9}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid link",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"link":"javascript:alert(1)"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Merge patch clears fields",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{
+						ID: 7, Song: "Hysteria", GroupID: 2, Group: "Muse", Text: "It's bugging me", Link: "https://example.com/song", Version: 3,
+					}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{
+						ID: 7, Song: "Hysteria", GroupID: 2, Group: "Muse", Text: "It's bugging me", Version: 3,
+					}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withMergePatch(withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"link":null}`)), map[string]string{"id": "7"}), `"3"`)),
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Merge patch moves song to another group",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Group: "Muse", Version: 3}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{ID: 7, Song: "Hysteria", Group: "Queen", Version: 3}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withMergePatch(withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"group":"Queen"}`)), map[string]string{"id": "7"}), "*")),
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Merge patch of a read-only field",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withMergePatch(withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"version":10}`)), map[string]string{"id": "7"}), `"3"`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Merge patch clears a required field",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 3}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withMergePatch(withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":null}`)), map[string]string{"id": "7"}), `"3"`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Merge patch of a changed song",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Hysteria", GroupID: 2, Version: 4}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withMergePatch(withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"text":null}`)), map[string]string{"id": "7"}), `"3"`)),
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Merge patch of a missing song",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withMergePatch(withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"text":null}`)), map[string]string{"id": "7"}), `"3"`)),
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(7)).Return(entities.Song{ID: 7, Song: "Starlight", GroupID: 2, Version: 3}, nil)
+					storage.EXPECT().ReplaceSong(gomock.Any(), gomock.Any()).Return(fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(withURLParams(httptest.NewRequest("PATCH", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria"}`)), map[string]string{"id": "7"}), `"3"`),
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.PatchSong(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+		})
+	}
+}
+
+// withMergePatch marks the request body as a JSON merge patch.
+func withMergePatch(r *http.Request) *http.Request {
+	r.Header.Set("Content-Type", mergePatchContentType+"; charset=utf-8")
+	return r
+}
@@ -2,32 +2,53 @@ package httphandlers
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
 	"net/http"
+	"strconv"
 )
 
 type SongMessage struct {
-	Song  string `json:"song"`
-	Group string `json:"group"`
+	Song        string  `json:"song"`
+	Group       string  `json:"group"`
+	AlbumID     *uint64 `json:"album_id,omitempty"`
+	DiscNumber  int     `json:"disc_number,omitempty"`
+	TrackNumber int     `json:"track_number,omitempty"`
+}
+
+// SongCreatedMessage is an answer to a song creation request.
+type SongCreatedMessage struct {
+	ID               uint64                    `json:"id"`
+	EnrichmentStatus entities.EnrichmentStatus `json:"enrichment_status"`
 }
 
 // PostSong godoc
 // @Summary Creates new song
-// @Description Creates new song, asks different service for an additional data (release date, text and link).
+// @Description Creates new song. An additional data (release date, text and link) is asked from a different service
+// @Description in background, the song stays `pending` until it is received.
+// @Description If the server is configured for synchronous enrichment, the data is asked before answering.
+// @Description The group is found by its name or alias and created if it does not exist yet.
+// @Description Names which differ only in case, spaces and diacritics are the same song, a duplicate is refused with 409.
 // @Accept  json
 // @Produce json
 // @Param song body SongMessage true "JSON song data"
-// @Success 201 {object} entities.IDMessage "Song ID"
-// @Failure 400 {object} nil "Bad request"
-// @Failure 500 {object} nil "Internal server error"
+// @Success 201 {object} SongCreatedMessage "Song ID and enrichment status"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 409 {object} ProblemDetails "Group already has a song of the same name, Location points to it"
+// @Failure 422 {object} ProblemDetails "Request refers to missing content"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
 // @Router /song [post]
+// @Router /api/v1/songs [post]
 func (h *handler) PostSong(w http.ResponseWriter, r *http.Request) {
 	//get song from request
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		h.logger.Debugf("failed to read body: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeInternalError(w, r)
 		return
 	}
 	defer r.Body.Close()
@@ -36,45 +57,73 @@ func (h *handler) PostSong(w http.ResponseWriter, r *http.Request) {
 	err = json.Unmarshal(bodyBytes, &songMessage)
 	if err != nil {
 		h.logger.Debugf("failed to unmarshal body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON")
 		return
 	}
 	if len(songMessage.Song) == 0 {
 		h.logger.Debugf("song name is empty")
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "song", "song name is empty")
 		return
 	}
 	if len(songMessage.Group) == 0 {
 		h.logger.Debugf("song group is empty")
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "group", "song group is empty")
+		return
+	}
+	err = entities.ValidateTrackPosition(songMessage.AlbumID, songMessage.DiscNumber, songMessage.TrackNumber)
+	if err != nil {
+		h.logger.Debugf("invalid track position: %v", err)
+		h.writeValidationProblem(w, r, err)
 		return
 	}
 
-	//ask api for an additional data
 	song := entities.Song{
-		Song:  songMessage.Song,
-		Group: songMessage.Group,
+		Song:        songMessage.Song,
+		Group:       songMessage.Group,
+		AlbumID:     songMessage.AlbumID,
+		DiscNumber:  songMessage.DiscNumber,
+		TrackNumber: songMessage.TrackNumber,
 	}
-	song.ReleaseDate, song.Text, song.Link, err = h.extraDataProvider.GetExtraSongData(song)
-	if err != nil {
-		h.logger.Debugf("failed to get song data: %v", err)
+
+	//ask api for an additional data or leave it to enrichment workers
+	if h.asyncEnrichment {
+		song.EnrichmentStatus = entities.EnrichmentPending
+	} else {
+		data, err := h.extraDataProvider.GetExtraSongData(r.Context(), song)
+		if errors.Is(err, requiredinterfaces.ErrExtraDataIncomplete) {
+			//there is no one to ask again later, keep what was found
+			h.logger.Debugf("song data is incomplete: %v", err)
+			err = nil
+		}
+		if r.Context().Err() != nil {
+			h.logger.Debugf("request was cancelled while asking for song data: %v", err)
+			return
+		} else if err != nil {
+			h.logger.Debugf("failed to get song data: %v", err)
+			song.EnrichmentStatus = entities.EnrichmentFailed
+		} else {
+			song.ApplyExtraData(data)
+			song.EnrichmentStatus = entities.EnrichmentEnriched
+		}
 	}
 
 	//save
 	song.ID, err = h.storage.SaveSong(r.Context(), song)
 	if err != nil {
 		h.logger.Debugf("failed to create song in database: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeStorageError(w, r, err)
 		return
 	}
 
 	//answer
-	jsonAnswer, err := json.Marshal(entities.IDMessage{
-		ID: song.ID,
+	jsonAnswer, err := json.Marshal(SongCreatedMessage{
+		ID:               song.ID,
+		EnrichmentStatus: song.EnrichmentStatus,
 	})
 
-	w.WriteHeader(http.StatusCreated)
 	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("Location", "/api/v1/songs/"+strconv.FormatUint(song.ID, 10))
+	w.WriteHeader(http.StatusCreated)
 	w.Write(jsonAnswer)
 	return
 }
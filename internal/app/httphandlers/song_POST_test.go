@@ -2,6 +2,7 @@ package httphandlers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,7 @@ import (
 	"musiclib/internal/app/entities"
 	"musiclib/internal/app/requiredinterfaces"
 	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -29,10 +31,35 @@ func Test_handler_PostSong(t *testing.T) {
 	tests := []struct {
 		name           string
 		fields         fields
+		async          bool
 		args           args
 		expectedStatus int
 		expectedBody   string
 	}{
+		{
+			name: "Async",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveSong(gomock.Any(), entities.Song{
+						Song:             "some song",
+						Group:            "some group",
+						EnrichmentStatus: entities.EnrichmentPending,
+					}).Return(uint64(10), nil)
+					return storage
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					return mocks.NewMockExtraDataProvider(c)
+				},
+			},
+			async: true,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song"}`)),
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":10,"enrichment_status":"pending"}`,
+		},
 		{
 			name: "Ok",
 			fields: fields{
@@ -41,18 +68,26 @@ func Test_handler_PostSong(t *testing.T) {
 					storage.EXPECT().SaveSong(gomock.Any(), entities.Song{
 						Song:        "some song",
 						Group:       "some group",
-						ReleaseDate: "10.10.2010",
+						ReleaseDate: entities.MustParseReleaseDate("10.10.2010"),
 						Text:        "some text\ntext2\n\ntext3.",
 						Link:        "https://example.com/somesong",
+						Sources:     map[string]string{entities.FieldReleaseDate: "api", entities.FieldText: "lyrics", entities.FieldLink: "api"},
+
+						EnrichmentStatus: entities.EnrichmentEnriched,
 					}).Return(uint64(10), nil)
 					return storage
 				},
 				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
 					provider := mocks.NewMockExtraDataProvider(c)
-					provider.EXPECT().GetExtraSongData(entities.Song{
+					provider.EXPECT().GetExtraSongData(gomock.Any(), entities.Song{
 						Song:  "some song",
 						Group: "some group",
-					}).Return("10.10.2010", "some text\ntext2\n\ntext3.", "https://example.com/somesong", nil)
+					}).Return(entities.ExtraSongData{
+						ReleaseDate: "10.10.2010",
+						Text:        "some text\ntext2\n\ntext3.",
+						Link:        "https://example.com/somesong",
+						Sources:     map[string]string{entities.FieldReleaseDate: "api", entities.FieldText: "lyrics", entities.FieldLink: "api"},
+					}, nil)
 					return provider
 				},
 			},
@@ -61,7 +96,28 @@ func Test_handler_PostSong(t *testing.T) {
 				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song"}`)),
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"id":10}`,
+			expectedBody:   `{"id":10,"enrichment_status":"enriched"}`,
+		},
+		{
+			name: "Cancelled by client",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
+						return entities.ExtraSongData{}, ctx.Err()
+					})
+					return provider
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: cancelledRequest(httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song"}`))),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ``,
 		},
 		{
 			name: "empty request",
@@ -80,7 +136,7 @@ func Test_handler_PostSong(t *testing.T) {
 				r: httptest.NewRequest("POST", "/song", nil),
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON", "/song"),
 		},
 		{
 			name: "Empty JSON",
@@ -99,7 +155,96 @@ func Test_handler_PostSong(t *testing.T) {
 				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{}`)),
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusBadRequest, codeRequiredField, "song", "song name is empty", "/song"),
+		},
+		{
+			name: "Track of an album",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					albumID := uint64(7)
+					storage.EXPECT().SaveSong(gomock.Any(), entities.Song{
+						Song:        "some song",
+						Group:       "some group",
+						AlbumID:     &albumID,
+						DiscNumber:  1,
+						TrackNumber: 3,
+
+						EnrichmentStatus: entities.EnrichmentFailed,
+					}).Return(uint64(10), nil)
+					return storage
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), gomock.Any()).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
+					return provider
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song","album_id":7,"disc_number":1,"track_number":3}`)),
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":10,"enrichment_status":"failed"}`,
+		},
+		{
+			name: "Missing album",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveSong(gomock.Any(), gomock.Any()).Return(uint64(0), dberrors.NewInvalidErr(fmt.Errorf("album 7 does not exist")))
+					return storage
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), gomock.Any()).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
+					return provider
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song","album_id":7,"track_number":3}`)),
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   problemBody(http.StatusUnprocessableEntity, codeUnprocessable, "", "request refers to missing content or breaks a constraint", "/song"),
+		},
+		{
+			name: "Duplicate song",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveSong(gomock.Any(), gomock.Any()).Return(uint64(0), dberrors.Wrap(dberrors.KindConflict, entities.DuplicateSongError{ID: 4}))
+					return storage
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), gomock.Any()).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
+					return provider
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"Muse","song":"HYSTÉRIA"}`)),
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"type":"about:blank","title":"Conflict","status":409,"detail":"group already has song 4 of the same name","instance":"/song","code":"duplicate_song","field":"song","existing_id":4}`,
+		},
+		{
+			name: "Track number without album",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					return mocks.NewMockExtraDataProvider(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song","track_number":3}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidField, "album_id", "disc and track numbers are set, but album is not", "/song"),
 		},
 		{
 			name: "Db error",
@@ -111,10 +256,15 @@ func Test_handler_PostSong(t *testing.T) {
 				},
 				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
 					provider := mocks.NewMockExtraDataProvider(c)
-					provider.EXPECT().GetExtraSongData(entities.Song{
+					provider.EXPECT().GetExtraSongData(gomock.Any(), entities.Song{
 						Song:  "some song",
 						Group: "some group",
-					}).Return("10.10.2010", "some text\ntext2\n\ntext3.", "https://example.com/somesong", nil)
+					}).Return(entities.ExtraSongData{
+						ReleaseDate: "10.10.2010",
+						Text:        "some text\ntext2\n\ntext3.",
+						Link:        "https://example.com/somesong",
+						Sources:     map[string]string{entities.FieldReleaseDate: "api", entities.FieldText: "lyrics", entities.FieldLink: "api"},
+					}, nil)
 					return provider
 				},
 			},
@@ -123,7 +273,7 @@ func Test_handler_PostSong(t *testing.T) {
 				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song"}`)),
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/song"),
 		},
 		{
 			name: "Provider error",
@@ -131,14 +281,15 @@ func Test_handler_PostSong(t *testing.T) {
 				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
 					storage := mocks.NewMockSongStorage(c)
 					storage.EXPECT().SaveSong(gomock.Any(), entities.Song{
-						Song:  "some song",
-						Group: "some group",
+						Song:             "some song",
+						Group:            "some group",
+						EnrichmentStatus: entities.EnrichmentFailed,
 					}).Return(uint64(10), nil)
 					return storage
 				},
 				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
 					provider := mocks.NewMockExtraDataProvider(c)
-					provider.EXPECT().GetExtraSongData(gomock.Any()).Return("", "", "", fmt.Errorf("test error"))
+					provider.EXPECT().GetExtraSongData(gomock.Any(), gomock.Any()).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
 					return provider
 				},
 			},
@@ -147,7 +298,37 @@ func Test_handler_PostSong(t *testing.T) {
 				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song"}`)),
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"id":10}`,
+			expectedBody:   `{"id":10,"enrichment_status":"failed"}`,
+		},
+		{
+			name: "Incomplete data",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().SaveSong(gomock.Any(), entities.Song{
+						Song:             "some song",
+						Group:            "some group",
+						Text:             "some text",
+						Sources:          map[string]string{entities.FieldText: "lyrics"},
+						EnrichmentStatus: entities.EnrichmentEnriched,
+					}).Return(uint64(10), nil)
+					return storage
+				},
+				extraDataProvider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), gomock.Any()).Return(entities.ExtraSongData{
+						Text:    "some text",
+						Sources: map[string]string{entities.FieldText: "lyrics"},
+					}, fmt.Errorf("%w: test error", requiredinterfaces.ErrExtraDataIncomplete))
+					return provider
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/song", bytes.NewBufferString(`{"group":"some group","song":"some song"}`)),
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":10,"enrichment_status":"enriched"}`,
 		},
 	}
 	for _, tt := range tests {
@@ -158,11 +339,19 @@ func Test_handler_PostSong(t *testing.T) {
 				storage:           tt.fields.storage(c),
 				logger:            sugar,
 				extraDataProvider: tt.fields.extraDataProvider(c),
+				asyncEnrichment:   tt.async,
 			}
 			h.PostSong(tt.args.w, tt.args.r)
 
 			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
-			assert.Equal(t, tt.expectedBody, tt.args.w.Body.String())
+			assertBody(t, tt.expectedBody, tt.args.w)
 		})
 	}
 }
+
+// cancelledRequest returns the request with a context cancelled as if the client went away.
+func cancelledRequest(r *http.Request) *http.Request {
+	ctx, cancel := context.WithCancel(r.Context())
+	cancel()
+	return r.WithContext(ctx)
+}
@@ -12,34 +12,55 @@ import (
 // PutSong godoc
 // @Summary Updates the song
 // @Description Updates current song by id.
+// @Description Only fields a client can change are taken from the body, empty ones are left unchanged.
+// @Description With If-Match the song is updated only if its version is the ETag, the version in the body is ignored.
 // @Accept  json
 // @Produce plain
-// @Param song body entities.Song true "JSON song data"
+// @Param If-Match header string false "ETag of the song"
+// @Param song body SongUpdateMessage true "JSON song data"
 // @Success 200 {object} nil "Success"
-// @Failure 400 {object} nil "Bad request"
-// @Failure 404 {object} nil "Not found"
-// @Failure 500 {object} nil "Internal server error"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 412 {object} ProblemDetails "Song was changed since the ETag in If-Match"
+// @Failure 409 {object} ProblemDetails "Group already has a song of the same name, Location points to it"
+// @Failure 422 {object} ProblemDetails "Request refers to missing content"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Deprecated
 // @Router /song [put]
 func (h *handler) PutSong(w http.ResponseWriter, r *http.Request) {
+	version, ok := h.ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	//get song from request
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		h.logger.Debugf("failed to read body: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeInternalError(w, r)
 		return
 	}
 	defer r.Body.Close()
 
-	song := entities.Song{}
-	err = json.Unmarshal(bodyBytes, &song)
+	message := SongUpdateMessage{}
+	err = json.Unmarshal(bodyBytes, &message)
 	if err != nil {
 		h.logger.Debugf("failed to unmarshal body: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeBodyProblem(w, r, err)
 		return
 	}
-	if song.ID == 0 {
+	if message.ID == 0 {
 		h.logger.Debugf("song ID is empty")
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "id", "song id is empty")
+		return
+	}
+	song := message.song(message.ID, version)
+	err = song.Validate()
+	if err != nil {
+		h.logger.Debugf("invalid song: %v", err)
+		h.writeValidationProblem(w, r, err)
 		return
 	}
 
@@ -47,11 +68,15 @@ func (h *handler) PutSong(w http.ResponseWriter, r *http.Request) {
 	err = h.storage.UpdateSong(r.Context(), song)
 	if errors.Is(err, dberrors.NewNotFoundErr()) {
 		h.logger.Debugf("song was`nt found in db, err: %v", err)
-		w.WriteHeader(http.StatusNotFound)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if errors.Is(err, dberrors.NewVersionMismatchErr()) {
+		h.logger.Debugf("song %d was changed since version %d", song.ID, song.Version)
+		h.writeVersionMismatch(w, r)
 		return
 	} else if err != nil {
 		h.logger.Debugf("failed to update song in database: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeStorageError(w, r, err)
 		return
 	}
 
@@ -59,3 +84,100 @@ func (h *handler) PutSong(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	return
 }
+
+// SongReplaceMessage is a full state of a song a client can change, absent fields are cleared.
+type SongReplaceMessage struct {
+	Song        string                `json:"song"`
+	Group       string                `json:"group,omitempty"`
+	GroupID     uint64                `json:"group_id,omitempty"`
+	AlbumID     *uint64               `json:"album_id,omitempty"`
+	DiscNumber  int                   `json:"disc_number,omitempty"`
+	TrackNumber int                   `json:"track_number,omitempty"`
+	ReleaseDate *entities.ReleaseDate `json:"release_date,omitempty" swaggertype:"string" example:"2006-01-02"`
+	Text        string                `json:"text,omitempty"`
+	Link        string                `json:"link,omitempty"`
+}
+
+// song returns the song of the message with the ID and the version.
+func (m SongReplaceMessage) song(id uint64, version int64) entities.Song {
+	return entities.Song{
+		ID:          id,
+		Song:        m.Song,
+		Group:       m.Group,
+		GroupID:     m.GroupID,
+		AlbumID:     m.AlbumID,
+		DiscNumber:  m.DiscNumber,
+		TrackNumber: m.TrackNumber,
+		ReleaseDate: m.ReleaseDate,
+		Text:        m.Text,
+		Link:        m.Link,
+		Version:     version,
+	}
+}
+
+// SongUpdateMessage is a song of the legacy update, fields only the service changes are ignored.
+type SongUpdateMessage struct {
+	ID uint64 `json:"id"`
+	SongReplaceMessage
+}
+
+// PutSongByID godoc
+// @Summary Replaces the song
+// @Description Replaces all fields of the song a client can change, absent fields are cleared.
+// @Description The song is moved to the group of group_id or, without it, of the group name.
+// @Description If-Match must be the ETag of the song (or `*` to replace any version).
+// @Accept  json
+// @Produce plain
+// @Param id path uint64 true "Song ID"
+// @Param If-Match header string true "ETag of the song"
+// @Param song body SongReplaceMessage true "JSON song data"
+// @Success 204 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 412 {object} ProblemDetails "Song was changed since the ETag in If-Match"
+// @Failure 428 {object} ProblemDetails "If-Match is absent"
+// @Failure 409 {object} ProblemDetails "Group already has a song of the same name, Location points to it"
+// @Failure 422 {object} ProblemDetails "Request refers to missing content"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id} [put]
+func (h *handler) PutSongByID(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+	version, ok := h.requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	//get song from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	message := SongReplaceMessage{}
+	err = json.Unmarshal(bodyBytes, &message)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeBodyProblem(w, r, err)
+		return
+	}
+	song := message.song(id, version)
+	err = song.Validate()
+	if err != nil {
+		h.logger.Debugf("invalid song: %v", err)
+		h.writeValidationProblem(w, r, err)
+		return
+	}
+
+	//replace
+	h.replaceSong(w, r, song)
+}
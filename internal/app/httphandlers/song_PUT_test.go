@@ -41,7 +41,7 @@ func Test_handler_PutSong(t *testing.T) {
 						ID:          1,
 						Song:        "updated song",
 						Group:       "updated group",
-						ReleaseDate: "10.10.2010",
+						ReleaseDate: entities.MustParseReleaseDate("10.10.2010"),
 						Text:        "Updated text",
 						Link:        "https://example.com/updatedsong",
 					}
@@ -62,6 +62,65 @@ func Test_handler_PutSong(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "Version in body is ignored",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateSong(gomock.Any(), entities.Song{ID: 1, Song: "updated song", GroupID: 3}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("PUT", "/song", bytes.NewBufferString(`{"id": 1, "song": "updated song", "group_id": 3, "version": 2}`)),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Version of If-Match",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateSong(gomock.Any(), entities.Song{ID: 1, Song: "updated song", GroupID: 3, Version: 5}).Return(dberrors.NewVersionMismatchErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withIfMatch(httptest.NewRequest("PUT", "/song", bytes.NewBufferString(`{"id": 1, "song": "updated song", "group_id": 3, "version": 2}`)), `"5"`),
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Read-only fields are ignored",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().UpdateSong(gomock.Any(), entities.Song{ID: 1, Song: "updated song", GroupID: 3}).Return(nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("PUT", "/song", bytes.NewBufferString(`{"id": 1, "song": "updated song", "group_id": 3,
+					"enrichment_status": "done", "sources": {"text": "lyrics"}}`)),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Invalid song",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("PUT", "/song", bytes.NewBufferString(`{"id": 1, "song": "updated song", "group_id": 3, "link": "/song"}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "Bag JSON",
 			fields: fields{
@@ -103,7 +162,7 @@ func Test_handler_PutSong(t *testing.T) {
 						ID:          999,
 						Song:        "nonexistent song",
 						Group:       "nonexistent group",
-						ReleaseDate: "12.12.2012",
+						ReleaseDate: entities.MustParseReleaseDate("12.12.2012"),
 						Text:        "No text",
 						Link:        "https://example.com/nonexistentsong",
 					}
@@ -133,7 +192,7 @@ func Test_handler_PutSong(t *testing.T) {
 						ID:          2,
 						Song:        "song with error",
 						Group:       "group with error",
-						ReleaseDate: "09.09.2009",
+						ReleaseDate: entities.MustParseReleaseDate("09.09.2009"),
 						Text:        "Error text",
 						Link:        "https://example.com/songwitherror",
 					}
@@ -198,3 +257,121 @@ func Test_handler_PutSong(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_PutSongByID(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	request := func(body string) *http.Request {
+		return withIfMatch(withURLParams(httptest.NewRequest("PUT", "/api/v1/songs/7", bytes.NewBufferString(body)), map[string]string{"id": "7"}), `"3"`)
+	}
+	tests := []struct {
+		name           string
+		storage        func(c *gomock.Controller) requiredinterfaces.SongStorage
+		r              *http.Request
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Absent fields are cleared",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ReplaceSong(gomock.Any(), entities.Song{
+					ID:          7,
+					Song:        "Hysteria",
+					Group:       "Muse",
+					ReleaseDate: entities.MustParseReleaseDate("2003"),
+					Version:     3,
+				}).Return(nil)
+				return storage
+			},
+			r:              request(`{"song":"Hysteria","group":"Muse","release_date":"2003"}`),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Empty song name",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request(`{"group":"Muse"}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidField, "song", "song name is empty", "/api/v1/songs/7"),
+		},
+		{
+			name: "Relative link",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request(`{"song":"Hysteria","group_id":2,"link":"/songs/hysteria"}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidField, "link", "link must be an absolute http or https URL", "/api/v1/songs/7"),
+		},
+		{
+			name: "Track number without album",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request(`{"song":"Hysteria","group_id":2,"track_number":3}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidField, "album_id", "disc and track numbers are set, but album is not", "/api/v1/songs/7"),
+		},
+		{
+			name: "Without If-Match",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              withURLParams(httptest.NewRequest("PUT", "/api/v1/songs/7", bytes.NewBufferString(`{"song":"Hysteria","group_id":2}`)), map[string]string{"id": "7"}),
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name: "Version mismatch",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ReplaceSong(gomock.Any(), gomock.Any()).Return(dberrors.NewVersionMismatchErr())
+				return storage
+			},
+			r:              request(`{"song":"Hysteria","group_id":2}`),
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Not found",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ReplaceSong(gomock.Any(), gomock.Any()).Return(dberrors.NewNotFoundErr())
+				return storage
+			},
+			r:              request(`{"song":"Hysteria","group_id":2}`),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Db error",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ReplaceSong(gomock.Any(), gomock.Any()).Return(errors.New("test error"))
+				return storage
+			},
+			r:              request(`{"song":"Hysteria","group_id":2}`),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := handler{
+				storage: tt.storage(ctrl),
+				logger:  sugar,
+			}
+			w := httptest.NewRecorder()
+
+			h.PutSongByID(w, tt.r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assertBody(t, tt.expectedBody, w)
+			}
+		})
+	}
+}
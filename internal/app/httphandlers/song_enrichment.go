@@ -0,0 +1,54 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// PostSongEnrichment godoc
+// @Summary Re-triggers enrichment of the song
+// @Description Makes the song pending and schedules a new request of its additional data (release date, text and link).
+// @Description Fields which are already filled are kept.
+// @Produce json
+// @Param id path uint64 true "Song ID"
+// @Success 202 {object} SongCreatedMessage "Song ID and enrichment status"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id}/enrichment [post]
+func (h *handler) PostSongEnrichment(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+
+	//schedule
+	err = h.storage.EnqueueEnrichment(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no songs found with ID `%v`, err: %v", id, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to schedule song enrichment: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(SongCreatedMessage{
+		ID:               id,
+		EnrichmentStatus: entities.EnrichmentPending,
+	})
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(jsonAnswer)
+	return
+}
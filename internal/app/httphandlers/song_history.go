@@ -0,0 +1,142 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// RevertMessage names the revision a song is reverted to.
+type RevertMessage struct {
+	Revision int `json:"revision" example:"3"`
+}
+
+// GetSongHistory godoc
+// @Summary Returns the change history of the song
+// @Description Every change of the song is a revision with changed fields, the actor (`X-Actor` header of the request) and the request ID.
+// @Description The latest revisions go first, songs in the trash have a history too.
+// @Produce json
+// @Param id path uint64 true "Song ID"
+// @Param offset query int false "Offset"
+// @Param limit query int false "Page size, 50 by default, 500 at most"
+// @Success 200 {array} entities.SongRevision "Revisions"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id}/history [get]
+func (h *handler) GetSongHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return
+	}
+	limit, err := intQueryParam(r, "limit", 0)
+	if err != nil {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer")
+		return
+	}
+	limit = entities.SongListQuery{Limit: limit}.PageLimit()
+
+	//get history
+	revisions, err := h.storage.GetSongHistory(r.Context(), id, offset, limit)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no song found with id %d", id)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to get song history: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(revisions)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
+
+// PostSongRevert godoc
+// @Summary Reverts the song to a revision
+// @Description Brings the song back to its state after the revision, the revert is recorded as a new revision.
+// @Description A group which was removed since is found by its name again, a removed album is unset.
+// @Accept  json
+// @Produce json
+// @Param id path uint64 true "Song ID"
+// @Param revision body RevertMessage true "Revision number"
+// @Success 200 {object} entities.Song "Reverted song"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Song or revision not found"
+// @Failure 409 {object} ProblemDetails "Group already has a song of the same name, Location points to it"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id}/revert [post]
+func (h *handler) PostSongRevert(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+
+	//get revision from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	message := RevertMessage{}
+	err = json.Unmarshal(bodyBytes, &message)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeBodyProblem(w, r, err)
+		return
+	}
+	if message.Revision == 0 {
+		h.logger.Debugf("revision is empty")
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "revision", "revision is empty")
+		return
+	}
+	if message.Revision < 0 {
+		h.logger.Debugf("revision is negative")
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidField, "revision", "revision must be a positive integer")
+		return
+	}
+
+	//revert
+	song, err := h.storage.RevertSong(r.Context(), id, message.Revision)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no revision %d of song %d, err: %v", message.Revision, id, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song or revision not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to revert song: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(song)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
@@ -0,0 +1,272 @@
+package httphandlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_handler_GetSongHistory(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	createdAt := time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongHistory(gomock.Any(), uint64(1), 0, entities.DefaultPageLimit).Return([]entities.SongRevision{
+						{
+							SongID:    1,
+							Revision:  2,
+							Action:    entities.RevisionUpdate,
+							Actor:     "admin",
+							RequestID: "host/abcdef-000001",
+							Changes:   []entities.FieldChange{{Field: "song", Before: "some song", After: "new song"}},
+							Song:      &entities.Song{ID: 1, Song: "new song", Group: "some group"},
+							CreatedAt: createdAt,
+						},
+					}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/history", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"song_id":1,"revision":2,"action":"update","actor":"admin","request_id":"host/abcdef-000001",
+				"changes":[{"field":"song","before":"some song","after":"new song"}],
+				"song":{"id":1,"song":"new song","group":"some group"},"created_at":"2024-10-10T12:00:00Z"}]`,
+		},
+		{
+			name: "Without revisions",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongHistory(gomock.Any(), uint64(1), 5, 10).Return([]entities.SongRevision{}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/history?offset=5&limit=10", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/abc/history", nil), map[string]string{"id": "abc"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer", "/api/v1/songs/abc/history"),
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongHistory(gomock.Any(), uint64(1), 0, entities.DefaultPageLimit).Return(nil, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/history", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "song not found", "/api/v1/songs/1/history"),
+		},
+		{
+			name: "Storage Error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongHistory(gomock.Any(), uint64(1), 0, entities.DefaultPageLimit).Return(nil, errors.New("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/history", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs/1/history"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.GetSongHistory(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
+
+func Test_handler_PostSongRevert(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RevertSong(gomock.Any(), uint64(1), 2).Return(entities.Song{ID: 1, Song: "some song", Group: "some group"}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("POST", "/api/v1/songs/1/revert", bytes.NewBufferString(`{"revision":2}`)), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"song":"some song","group":"some group"}`,
+		},
+		{
+			name: "Empty revision",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("POST", "/api/v1/songs/1/revert", bytes.NewBufferString(`{}`)), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeRequiredField, "revision", "revision is empty", "/api/v1/songs/1/revert"),
+		},
+		{
+			name: "Negative revision",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("POST", "/api/v1/songs/1/revert", bytes.NewBufferString(`{"revision":-1}`)), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidField, "revision", "revision must be a positive integer", "/api/v1/songs/1/revert"),
+		},
+		{
+			name: "Bad body",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("POST", "/api/v1/songs/1/revert", bytes.NewBufferString(`{"revision":"2"}`)), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON", "/api/v1/songs/1/revert"),
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RevertSong(gomock.Any(), uint64(1), 7).Return(entities.Song{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("POST", "/api/v1/songs/1/revert", bytes.NewBufferString(`{"revision":7}`)), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "song or revision not found", "/api/v1/songs/1/revert"),
+		},
+		{
+			name: "Storage Error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().RevertSong(gomock.Any(), uint64(1), 2).Return(entities.Song{}, errors.New("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("POST", "/api/v1/songs/1/revert", bytes.NewBufferString(`{"revision":2}`)), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs/1/revert"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.PostSongRevert(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
@@ -15,10 +15,13 @@ import (
 // @Param song_id query uint64 true "Song ID"
 // @Param couplet_num query int true "Couplet Number"
 // @Success 200 {string} string "Couplet text"
-// @Failure 400 {object} nil "Bad request"
-// @Failure 404 {object} nil "Song not found"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Song not found"
 // @Failure 204 {object} nil "Requested couplet number is bigger than the number of couplets"
-// @Failure 500 {object} nil "Internal server error"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Deprecated
 // @Router /lyrics [get]
 func (h *handler) GetSongLyrics(w http.ResponseWriter, r *http.Request) {
 	// Get query parameters
@@ -29,14 +32,14 @@ func (h *handler) GetSongLyrics(w http.ResponseWriter, r *http.Request) {
 	songID, err := strconv.ParseUint(songIDStr, 10, 64)
 	if err != nil {
 		h.logger.Debugf("invalid song_id: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "song_id", "song_id must be a positive integer")
 		return
 	}
 
 	coupletNum, err := strconv.Atoi(coupletNumStr)
 	if err != nil {
 		h.logger.Debugf("invalid couplet_num: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "couplet_num", "couplet_num must be an integer")
 		return
 	}
 
@@ -44,25 +47,91 @@ func (h *handler) GetSongLyrics(w http.ResponseWriter, r *http.Request) {
 	text, err := h.storage.GetSongLyrics(r.Context(), songID)
 	if errors.Is(err, dberrors.NewNotFoundErr()) {
 		h.logger.Debugf("no song found with id %d", songID)
-		w.WriteHeader(http.StatusNotFound)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
 		return
 	} else if err != nil {
 		h.logger.Debugf("failed get song lyrics from db: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeStorageError(w, r, err)
 		return
 	}
 
 	// Get couplet
-	couplets := strings.Split(text, "\n\n")
-	if coupletNum > len(couplets)-1 {
-		h.logger.Debugf("requested couplet num is `%v`, but this song has only `%v` couplets.", coupletNum, len(couplets))
+	couplet, ok := coupletOf(text, coupletNum)
+	if !ok {
+		h.logger.Debugf("requested couplet num is `%v`, but this song has less couplets.", coupletNum)
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	answer := []byte(couplets[coupletNum])
+	answer := []byte(couplet)
 
 	// Answer
 	w.WriteHeader(http.StatusOK)
 	w.Write(answer)
 	return
 }
+
+// GetSongLyricsByID godoc
+// @Summary Returns the song's lyrics
+// @Description Retrieves the whole lyrics of the song or only the requested couplet.
+// @Produce text/plain
+// @Param id path uint64 true "Song ID"
+// @Param couplet query int false "Couplet Number, starting from 0"
+// @Success 200 {string} string "Lyrics or couplet text"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Song or couplet not found"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id}/lyrics [get]
+func (h *handler) GetSongLyricsByID(w http.ResponseWriter, r *http.Request) {
+	songID, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+	coupletNum, err := intQueryParam(r, "couplet", -1)
+	if err != nil || coupletNum < -1 {
+		h.logger.Debugf("invalid couplet: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "couplet", "couplet must be a non-negative integer")
+		return
+	}
+
+	// Get lyrics
+	text, err := h.storage.GetSongLyrics(r.Context(), songID)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no song found with id %d", songID)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed get song lyrics from db: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	// Get couplet
+	if coupletNum >= 0 {
+		var ok bool
+		text, ok = coupletOf(text, coupletNum)
+		if !ok {
+			h.logger.Debugf("requested couplet num is `%v`, but this song has less couplets.", coupletNum)
+			h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "couplet", "song has less couplets")
+			return
+		}
+	}
+
+	// Answer
+	w.Header().Add("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(text))
+	return
+}
+
+// coupletOf returns a couplet of lyrics by its number, couplets are separated by an empty line.
+func coupletOf(text string, coupletNum int) (string, bool) {
+	couplets := strings.Split(text, "\n\n")
+	if coupletNum < 0 || coupletNum > len(couplets)-1 {
+		return "", false
+	}
+	return couplets[coupletNum], true
+}
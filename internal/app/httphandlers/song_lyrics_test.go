@@ -62,7 +62,7 @@ func Test_handler_GetSongLyrics(t *testing.T) {
 				r: httptest.NewRequest("GET", "/lyrics?song_id=1&couplet_num=abc", nil),
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "couplet_num", "couplet_num must be an integer", "/lyrics"),
 		},
 		{
 			name: "No song ID",
@@ -76,7 +76,7 @@ func Test_handler_GetSongLyrics(t *testing.T) {
 				r: httptest.NewRequest("GET", "/lyrics?couplet_num=1", nil),
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "song_id", "song_id must be a positive integer", "/lyrics"),
 		},
 		{
 			name: "Song Not Found",
@@ -92,7 +92,7 @@ func Test_handler_GetSongLyrics(t *testing.T) {
 				r: httptest.NewRequest("GET", "/lyrics?song_id=2&couplet_num=1", nil),
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "song not found", "/lyrics"),
 		},
 		{
 			name: "Storage Error",
@@ -108,7 +108,7 @@ func Test_handler_GetSongLyrics(t *testing.T) {
 				r: httptest.NewRequest("GET", "/lyrics?song_id=2&couplet_num=1", nil),
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/lyrics"),
 		},
 		{
 			name: "No Content - Couplet Number Too High",
@@ -176,7 +176,155 @@ func Test_handler_GetSongLyrics(t *testing.T) {
 			h.GetSongLyrics(tt.args.w, tt.args.r)
 
 			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
-			assert.Equal(t, tt.expectedBody, tt.args.w.Body.String())
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
+
+func Test_handler_GetSongLyricsByID(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	lyrics := "First couplet.\nStill first couplet.\n\nSecond couplet."
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Whole lyrics",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongLyrics(gomock.Any(), uint64(1)).Return(lyrics, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/lyrics", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   lyrics,
+		},
+		{
+			name: "Couplet",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongLyrics(gomock.Any(), uint64(1)).Return(lyrics, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/lyrics?couplet=1", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Second couplet.",
+		},
+		{
+			name: "Couplet Number Too High",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongLyrics(gomock.Any(), uint64(1)).Return(lyrics, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/lyrics?couplet=2", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "couplet", "song has less couplets", "/api/v1/songs/1/lyrics"),
+		},
+		{
+			name: "Bad couplet",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/1/lyrics?couplet=abc", nil), map[string]string{"id": "1"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "couplet", "couplet must be a non-negative integer", "/api/v1/songs/1/lyrics"),
+		},
+		{
+			name: "Bad ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/abc/lyrics", nil), map[string]string{"id": "abc"}),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer", "/api/v1/songs/abc/lyrics"),
+		},
+		{
+			name: "Song Not Found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongLyrics(gomock.Any(), uint64(2)).Return("", dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/2/lyrics", nil), map[string]string{"id": "2"}),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "song not found", "/api/v1/songs/2/lyrics"),
+		},
+		{
+			name: "Storage Error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongLyrics(gomock.Any(), uint64(2)).Return("", errors.New("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: withURLParams(httptest.NewRequest("GET", "/api/v1/songs/2/lyrics", nil), map[string]string{"id": "2"}),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs/2/lyrics"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := &handler{
+				storage: tt.fields.storage(ctrl),
+				logger:  sugar,
+			}
+
+			h.GetSongLyricsByID(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
 		})
 	}
 }
@@ -0,0 +1,46 @@
+package httphandlers
+
+import (
+	"errors"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// PostSongRestore godoc
+// @Summary Restores the song from the trash
+// @Description Takes a removed song out of the trash, a pending song is enriched again.
+// @Description A song whose group has got a song of the same name meanwhile can not be restored.
+// @Produce plain
+// @Param id path uint64 true "Song ID"
+// @Success 204 {object} nil "Success"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Song is not in the trash"
+// @Failure 409 {object} ProblemDetails "Group already has a song of the same name, Location points to it"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/{id}/restore [post]
+func (h *handler) PostSongRestore(w http.ResponseWriter, r *http.Request) {
+	id, err := idFromURL(r)
+	if err != nil {
+		h.logger.Debugf("invalid song id: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "id", "song id must be a positive integer")
+		return
+	}
+
+	//restore
+	err = h.storage.RestoreSong(r.Context(), id)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no songs in the trash with ID `%v`, err: %v", id, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song not found in the trash")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to restore song: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	w.WriteHeader(http.StatusNoContent)
+	return
+}
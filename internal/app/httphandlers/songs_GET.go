@@ -0,0 +1,229 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SongListMessage is a page of songs. Cursors are omitted when there are no songs in their direction.
+type SongListMessage struct {
+	Songs      []entities.Song `json:"songs"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	PrevCursor string          `json:"prev_cursor,omitempty"`
+	Total      *int64          `json:"total,omitempty"`
+}
+
+// GetSongs godoc
+// @Summary Returns list of songs
+// @Description filtration, sorting and cursor pagination are supported, an empty list is returned if nothing was found.
+// @Description Pass `next_cursor` or `prev_cursor` of a page as `cursor` to get the next or the previous page.
+// @Description Text filters ignore case, `contains` is the default match mode.
+// @Produce json
+// @Param ids query string false "Comma separated song IDs"
+// @Param song query string false "Song name"
+// @Param song_match query string false "How song name is matched" Enums(contains, prefix, exact)
+// @Param group query string false "Group name"
+// @Param group_match query string false "How group name is matched" Enums(contains, prefix, exact)
+// @Param group_id query string false "Comma separated group IDs, any of them matches"
+// @Param groups query []string false "Exact group names, any of them matches" collectionFormat(multi)
+// @Param release_date query string false "Songs released on this date, month (2006-01) or year (2006)"
+// @Param release_date_from query string false "Songs released on this date or later, 2006-01-02, 02.01.2006, 2006-01 or 2006"
+// @Param release_date_to query string false "Songs released on this date or earlier, 2006-01-02, 02.01.2006, 2006-01 or 2006"
+// @Param has_lyrics query bool false "Only songs with (true) or without (false) lyrics"
+// @Param has_link query bool false "Only songs with (true) or without (false) a link"
+// @Param sort query string false "Sort field, `-` prefix for descending order" Enums(id, song, group, release_date, disc_number, track_number, -id, -song, -group, -release_date, -disc_number, -track_number)
+// @Param cursor query string false "Cursor of the page"
+// @Param limit query int false "Page size, 50 by default, 500 at most"
+// @Param with_total query bool false "Count all songs matching the filter"
+// @Param offset query int false "Offset, can not be used with a cursor"
+// @Success 200 {object} SongListMessage "Page of songs"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs [get]
+func (h *handler) GetSongs(w http.ResponseWriter, r *http.Request) {
+	//get filter from request
+	filter, err := songFilterFromQuery(r.URL.Query())
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		h.logger.Debugf("invalid songs filter: %v", err)
+		h.writeParameterProblem(w, r, err)
+		return
+	}
+	listQuery, ok := h.songListQuery(w, r, filter)
+	if !ok {
+		return
+	}
+
+	//get songs list
+	page, err := h.storage.GetSongList(r.Context(), listQuery)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no songs with requested params: %v", err)
+		page.Songs = []entities.Song{}
+	} else if err != nil {
+		h.logger.Debugf("failed get songs: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	answer := SongListMessage{
+		Songs: page.Songs,
+		Total: page.Total,
+	}
+	if page.NextCursor != nil {
+		answer.NextCursor = page.NextCursor.Encode()
+	}
+	if page.PrevCursor != nil {
+		answer.PrevCursor = page.PrevCursor.Encode()
+	}
+	jsonAnswer, err := json.Marshal(answer)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
+
+// songFilterFromQuery reads a songs filter from query params. Errors are entities.FieldError.
+func songFilterFromQuery(query url.Values) (entities.SongFilter, error) {
+	filter := entities.SongFilter{
+		Song:       query.Get("song"),
+		SongMatch:  entities.MatchMode(query.Get("song_match")),
+		Group:      query.Get("group"),
+		GroupMatch: entities.MatchMode(query.Get("group_match")),
+		Groups:     query["groups"],
+	}
+	var err error
+
+	filter.IDs, err = idList(query.Get("ids"))
+	if err != nil {
+		return entities.SongFilter{}, entities.FieldError{Field: "ids", Message: "ids must be comma separated positive integers"}
+	}
+	filter.GroupIDs, err = idList(strings.Join(query["group_id"], ","))
+	if err != nil {
+		return entities.SongFilter{}, entities.FieldError{Field: "group_id", Message: "group_id must be a positive integer"}
+	}
+	filter.ReleaseDate, err = releaseDateParam("release_date", query.Get("release_date"))
+	if err != nil {
+		return entities.SongFilter{}, err
+	}
+	filter.ReleaseDateFrom, err = releaseDateParam("release_date_from", query.Get("release_date_from"))
+	if err != nil {
+		return entities.SongFilter{}, err
+	}
+	filter.ReleaseDateTo, err = releaseDateParam("release_date_to", query.Get("release_date_to"))
+	if err != nil {
+		return entities.SongFilter{}, err
+	}
+	for name, flag := range map[string]**bool{"has_lyrics": &filter.HasLyrics, "has_link": &filter.HasLink} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return entities.SongFilter{}, entities.FieldError{Field: name, Message: name + " must be a boolean"}
+			}
+			*flag = &parsed
+		}
+	}
+	return filter, nil
+}
+
+// ParseSongFilter reads a songs filter written like query params of the song list, e.g. `group=Muse&has_lyrics=true`.
+// Errors are entities.FieldError.
+func ParseSongFilter(query string) (entities.SongFilter, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return entities.SongFilter{}, entities.FieldError{Message: "filter must look like query params"}
+	}
+	filter, err := songFilterFromQuery(values)
+	if err != nil {
+		return entities.SongFilter{}, err
+	}
+	return filter, filter.Validate()
+}
+
+// releaseDateParam parses an optional date of a filter, nil is returned for an empty value.
+func releaseDateParam(name, value string) (*entities.ReleaseDate, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := entities.ParseReleaseDate(value)
+	if err != nil {
+		return nil, entities.FieldError{Field: name, Message: name + " must look like 2006-01-02, 02.01.2006, 2006-01 or 2006"}
+	}
+	return &date, nil
+}
+
+// idList parses comma separated IDs.
+func idList(value string) ([]uint64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var ids []uint64
+	for _, item := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(item), 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid id `%v`", item)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// songListQuery reads sorting and pagination params of a songs list. A problem is written if they are invalid.
+func (h *handler) songListQuery(w http.ResponseWriter, r *http.Request, filter entities.SongFilter) (entities.SongListQuery, bool) {
+	query := r.URL.Query()
+	listQuery := entities.SongListQuery{Filter: filter}
+	var err error
+
+	listQuery.Sort, err = entities.ParseSongSort(query.Get("sort"))
+	if err != nil {
+		h.logger.Debugf("invalid sort: %v", err)
+		h.writeParameterProblem(w, r, err)
+		return entities.SongListQuery{}, false
+	}
+	if token := query.Get("cursor"); token != "" {
+		cursor, err := entities.DecodeCursor(token)
+		if err != nil || cursor.Sort != listQuery.Sort.String() {
+			h.logger.Debugf("invalid cursor: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "cursor", "cursor is invalid or was made for another sort")
+			return entities.SongListQuery{}, false
+		}
+		listQuery.Cursor = &cursor
+	}
+	listQuery.Offset, err = intQueryParam(r, "offset", 0)
+	if err != nil || listQuery.Offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return entities.SongListQuery{}, false
+	}
+	if listQuery.Offset > 0 && listQuery.Cursor != nil {
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset can not be used with a cursor")
+		return entities.SongListQuery{}, false
+	}
+	listQuery.Limit, err = intQueryParam(r, "limit", entities.DefaultPageLimit)
+	if err != nil || listQuery.Limit < 1 || listQuery.Limit > entities.MaxPageLimit {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit",
+			fmt.Sprintf("limit must be an integer from 1 to %d", entities.MaxPageLimit))
+		return entities.SongListQuery{}, false
+	}
+	if withTotal := query.Get("with_total"); withTotal != "" {
+		listQuery.WithTotal, err = strconv.ParseBool(withTotal)
+		if err != nil {
+			h.logger.Debugf("invalid with_total: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "with_total", "with_total must be a boolean")
+			return entities.SongListQuery{}, false
+		}
+	}
+	return listQuery, true
+}
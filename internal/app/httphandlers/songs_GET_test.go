@@ -0,0 +1,327 @@
+package httphandlers
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GetSongs(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	songs := []entities.Song{
+		{
+			ID:          1,
+			Song:        "some song",
+			GroupID:     4,
+			Group:       "some group",
+			ReleaseDate: entities.MustParseReleaseDate("10.10.2010"),
+		},
+		{
+			ID:      2,
+			Song:    "some song 2",
+			GroupID: 4,
+			Group:   "some group",
+			Link:    "https://example.com/song2",
+		},
+	}
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					filter := entities.SongFilter{
+						Song:     "some song",
+						Group:    "some group",
+						GroupIDs: []uint64{4},
+					}
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{Filter: filter, Sort: entities.SongSort{Field: "id"}, Offset: 20, Limit: 10}).
+						Return(entities.SongPage{Songs: songs, PrevCursor: &entities.Cursor{Sort: "id", ID: 1, Backward: true}}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?song=some+song&group=some+group&group_id=4&offset=20&limit=10", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"songs":[
+				{"id":1,"song":"some song","group_id":4,"group":"some group","release_date":"2010-10-10"},
+				{"id":2,"song":"some song 2","group_id":4,"group":"some group","link":"https://example.com/song2"}
+			],"prev_cursor":"` + entities.Cursor{Sort: "id", ID: 1, Backward: true}.Encode() + `"}`,
+		},
+		{
+			name: "Cursor and total",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					total := int64(12)
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{
+						Sort:      entities.SongSort{Field: "release_date", Desc: true},
+						Cursor:    &entities.Cursor{Sort: "-release_date", Key: "2010-10-10", ID: 7},
+						Limit:     entities.DefaultPageLimit,
+						WithTotal: true,
+					}).Return(entities.SongPage{Songs: songs[:1], NextCursor: &entities.Cursor{Sort: "-release_date", Key: "2010-10-10", ID: 1}, Total: &total}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?with_total=true&sort=-release_date&cursor="+entities.Cursor{Sort: "-release_date", Key: "2010-10-10", ID: 7}.Encode(), nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"songs":[
+				{"id":1,"song":"some song","group_id":4,"group":"some group","release_date":"2010-10-10"}
+			],"next_cursor":"` + entities.Cursor{Sort: "-release_date", Key: "2010-10-10", ID: 1}.Encode() + `","total":12}`,
+		},
+		{
+			name: "All filters",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					hasLyrics, hasLink := true, false
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{
+						Filter: entities.SongFilter{
+							IDs:             []uint64{1, 2, 3},
+							Song:            "some",
+							SongMatch:       entities.MatchPrefix,
+							GroupMatch:      entities.MatchExact,
+							Group:           "some group",
+							GroupIDs:        []uint64{4, 5, 6},
+							Groups:          []string{"Muse", "Queen"},
+							ReleaseDate:     entities.MustParseReleaseDate("2005"),
+							ReleaseDateFrom: entities.MustParseReleaseDate("2000-01-01"),
+							ReleaseDateTo:   entities.MustParseReleaseDate("12.2010"),
+							HasLyrics:       &hasLyrics,
+							HasLink:         &hasLink,
+						},
+						Sort:  entities.SongSort{Field: "song"},
+						Limit: entities.DefaultPageLimit,
+					}).Return(entities.SongPage{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?ids=1,2,3&song=some&song_match=prefix&group=some+group&group_match=exact"+
+					"&group_id=4,5&group_id=6&groups=Muse&groups=Queen&release_date=2005&release_date_from=2000-01-01&release_date_to=12.2010"+
+					"&has_lyrics=true&has_link=false&sort=song", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"songs":[]}`,
+		},
+		{
+			name: "Bad match mode",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?song_match=regex", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "song_match", "match mode must be `contains`, `prefix` or `exact`", "/api/v1/songs"),
+		},
+		{
+			name: "Bad date range",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?release_date_from=2010-01-01&release_date_to=2000-01-01", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "release_date_from", "release_date_from is after release_date_to", "/api/v1/songs"),
+		},
+		{
+			name: "Bad release date",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?release_date=yesterday", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "release_date", "release_date must look like 2006-01-02, 02.01.2006, 2006-01 or 2006", "/api/v1/songs"),
+		},
+		{
+			name: "Bad sort",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?sort=text", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "sort", "songs can be sorted by id, song, group, release_date, disc_number, track_number", "/api/v1/songs"),
+		},
+		{
+			name: "Cursor of another sort",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?sort=song&cursor="+entities.Cursor{Sort: "id", ID: 7}.Encode(), nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "cursor", "cursor is invalid or was made for another sort", "/api/v1/songs"),
+		},
+		{
+			name: "Bad cursor",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?cursor=abc", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "cursor", "cursor is invalid or was made for another sort", "/api/v1/songs"),
+		},
+		{
+			name: "Cursor with offset",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?offset=5&cursor="+entities.Cursor{Sort: "id", ID: 7}.Encode(), nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "offset", "offset can not be used with a cursor", "/api/v1/songs"),
+		},
+		{
+			name: "Too big limit",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?limit=501", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer from 1 to 500", "/api/v1/songs"),
+		},
+		{
+			name: "No songs found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{Sort: entities.SongSort{Field: "id"}, Limit: entities.DefaultPageLimit}).Return(entities.SongPage{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"songs":[]}`,
+		},
+		{
+			name: "Bad group ID",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?group_id=x", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "group_id", "group_id must be a positive integer", "/api/v1/songs"),
+		},
+		{
+			name: "Bad limit",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs?limit=ten", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer from 1 to 500", "/api/v1/songs"),
+		},
+		{
+			name: "Storage error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongList(gomock.Any(), gomock.Any()).Return(entities.SongPage{}, fmt.Errorf("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/api/v1/songs", nil),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			h := handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+
+			h.GetSongs(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
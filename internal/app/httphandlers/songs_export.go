@@ -0,0 +1,134 @@
+package httphandlers
+
+import (
+	"compress/gzip"
+	"musiclib/internal/app/services/songExporter"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GetSongsExport godoc
+// @Summary Exports songs
+// @Description Streams all songs matching the filter in the order of IDs, filters are the same as of the song list.
+// @Description The output is gzipped if the client accepts gzip. If the export fails in the middle, the output is cut.
+// @Produce json,text/csv,application/x-ndjson
+// @Param format query string false "Output format, json by default" Enums(json, csv, ndjson)
+// @Param ids query string false "Comma separated song IDs"
+// @Param song query string false "Song name"
+// @Param song_match query string false "How song name is matched" Enums(contains, prefix, exact)
+// @Param group query string false "Group name"
+// @Param group_match query string false "How group name is matched" Enums(contains, prefix, exact)
+// @Param group_id query string false "Comma separated group IDs, any of them matches"
+// @Param groups query []string false "Exact group names, any of them matches" collectionFormat(multi)
+// @Param release_date query string false "Songs released on this date, month (2006-01) or year (2006)"
+// @Param release_date_from query string false "Songs released on this date or later, 2006-01-02, 02.01.2006, 2006-01 or 2006"
+// @Param release_date_to query string false "Songs released on this date or earlier, 2006-01-02, 02.01.2006, 2006-01 or 2006"
+// @Param has_lyrics query bool false "Only songs with (true) or without (false) lyrics"
+// @Param has_link query bool false "Only songs with (true) or without (false) a link"
+// @Success 200 {array} entities.Song "Songs"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/export [get]
+func (h *handler) GetSongsExport(w http.ResponseWriter, r *http.Request) {
+	//get filter and format from request
+	filter, err := songFilterFromQuery(r.URL.Query())
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		h.logger.Debugf("invalid songs filter: %v", err)
+		h.writeParameterProblem(w, r, err)
+		return
+	}
+	format := songExporter.FormatJSON
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err = songExporter.ParseFormat(name)
+		if err != nil {
+			h.logger.Debugf("invalid format: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "format", "format must be json, csv or ndjson")
+			return
+		}
+	}
+
+	//export
+	out := &exportWriter{w: w, format: format, gzip: acceptsGzip(r)}
+	exported, err := songExporter.NewExporter(h.storage).Export(r.Context(), out, filter, format)
+	if r.Context().Err() != nil {
+		h.logger.Debugf("export was cancelled after %d songs: %v", exported, err)
+		return
+	} else if err != nil && !out.started {
+		h.logger.Debugf("failed to export songs: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	} else if err != nil {
+		h.logger.Errorf("export failed after %d songs, the output is cut: %v", exported, err)
+		return
+	}
+	out.start()
+	err = out.Close()
+	if err != nil {
+		h.logger.Debugf("failed to finish export: %v", err)
+	}
+}
+
+// exportWriter answers 200 on the first write, so an export which fails before it can still answer with a problem.
+type exportWriter struct {
+	w       http.ResponseWriter
+	format  songExporter.Format
+	gzip    bool
+	gz      *gzip.Writer
+	started bool
+}
+
+func (e *exportWriter) start() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.w.Header().Set("Content-Type", e.format.ContentType())
+	e.w.Header().Set("Content-Disposition", `attachment; filename="songs.`+string(e.format)+`"`)
+	e.w.Header().Add("Vary", "Accept-Encoding")
+	if e.gzip {
+		e.w.Header().Set("Content-Encoding", "gzip")
+		e.gz = gzip.NewWriter(e.w)
+	}
+	e.w.WriteHeader(http.StatusOK)
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	e.start()
+	if e.gz != nil {
+		return e.gz.Write(p)
+	}
+	return e.w.Write(p)
+}
+
+// Close finishes the gzip stream.
+func (e *exportWriter) Close() error {
+	if e.gz != nil {
+		return e.gz.Close()
+	}
+	return nil
+}
+
+// acceptsGzip tells whether Accept-Encoding allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(coding), ";")
+			if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+				continue
+			}
+			q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+			if !ok {
+				return true
+			}
+			weight, err := strconv.ParseFloat(q, 64)
+			return err == nil && weight > 0
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package httphandlers
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_GetSongsExport(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	songs := []entities.Song{
+		{ID: 1, Song: "Hysteria", GroupID: 2, Group: "Muse", Version: 3},
+		{ID: 4, Song: "Uprising", GroupID: 2, Group: "Muse", Version: 1},
+	}
+	export := func(songs []entities.Song, err error) func(context.Context, entities.SongFilter, func(entities.Song) error) error {
+		return func(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error {
+			for _, song := range songs {
+				if fnErr := fn(song); fnErr != nil {
+					return fnErr
+				}
+			}
+			return err
+		}
+	}
+	tests := []struct {
+		name                string
+		storage             func(c *gomock.Controller) requiredinterfaces.SongStorage
+		r                   *http.Request
+		gzip                bool
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: "CSV",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ExportSongs(gomock.Any(), entities.SongFilter{Group: "muse", GroupMatch: entities.MatchExact}, gomock.Any()).DoAndReturn(export(songs, nil))
+				return storage
+			},
+			r:                   httptest.NewRequest("GET", "/api/v1/songs/export?format=csv&group=muse&group_match=exact", nil),
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,group,song,album_id,disc_number,track_number,release_date,text,link,enrichment_status,version\n" +
+				"1,Muse,Hysteria,,,,,,,,3\n" +
+				"4,Muse,Uprising,,,,,,,,1\n",
+		},
+		{
+			name: "Gzipped NDJSON",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ExportSongs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(export(songs[:1], nil))
+				return storage
+			},
+			r: func() *http.Request {
+				r := httptest.NewRequest("GET", "/api/v1/songs/export?format=ndjson", nil)
+				r.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+				return r
+			}(),
+			gzip:                true,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"id":1,"song":"Hysteria","group_id":2,"group":"Muse","version":3}` + "\n",
+		},
+		{
+			name: "Empty NDJSON",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ExportSongs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(export(nil, nil))
+				return storage
+			},
+			r:                   httptest.NewRequest("GET", "/api/v1/songs/export?format=ndjson", nil),
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+		},
+		{
+			name: "Bad format",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:                   httptest.NewRequest("GET", "/api/v1/songs/export?format=xml", nil),
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: problemContentType,
+			expectedBody:        problemBody(http.StatusBadRequest, codeInvalidParameter, "format", "format must be json, csv or ndjson", "/api/v1/songs/export"),
+		},
+		{
+			name: "Bad filter",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:                   httptest.NewRequest("GET", "/api/v1/songs/export?has_link=sometimes", nil),
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: problemContentType,
+			expectedBody:        problemBody(http.StatusBadRequest, codeInvalidParameter, "has_link", "has_link must be a boolean", "/api/v1/songs/export"),
+		},
+		{
+			name: "Db error",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ExportSongs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(export(nil, fmt.Errorf("test error")))
+				return storage
+			},
+			r:                   httptest.NewRequest("GET", "/api/v1/songs/export", nil),
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: problemContentType,
+			expectedBody:        problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs/export"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.storage(c),
+				logger:  sugar,
+			}
+			w := httptest.NewRecorder()
+
+			h.GetSongsExport(w, tt.r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			if tt.expectedContentType == problemContentType {
+				assertBody(t, tt.expectedBody, w)
+				return
+			}
+			body := io.Reader(w.Body)
+			if tt.gzip {
+				assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+				gz, err := gzip.NewReader(w.Body)
+				assert.NoError(t, err)
+				body = gz
+			}
+			got, err := io.ReadAll(body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(got))
+		})
+	}
+}
+
+func Test_acceptsGzip(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, GZIP", true},
+		{"br;q=1.0, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"identity", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/songs/export", nil)
+			r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			assert.Equal(t, tt.expected, acceptsGzip(r))
+		})
+	}
+}
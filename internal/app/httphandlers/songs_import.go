@@ -0,0 +1,102 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"musiclib/internal/app/services/songImporter"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"strconv"
+)
+
+// importMediaTypes map media types of an import body to its format.
+var importMediaTypes = map[string]songImporter.Format{
+	"text/csv":                songImporter.FormatCSV,
+	"application/x-ndjson":    songImporter.FormatJSONL,
+	"application/jsonl":       songImporter.FormatJSONL,
+	"application/x-jsonlines": songImporter.FormatJSONL,
+}
+
+// PostSongsImport godoc
+// @Summary Imports songs
+// @Description Streams songs from CSV with a header of field names or from JSON Lines and saves them in batches.
+// @Description Every row is reported as created, skipped (the song of the same group and name is in the library already or earlier in the input) or failed.
+// @Description The format is taken from the format parameter or else from Content-Type. With enrich=true extra data of new songs is asked in background.
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param format query string false "Input format" Enums(csv, jsonl)
+// @Param enrich query bool false "Enrich new songs"
+// @Param songs body string true "Songs with the fields group, song, album_id, disc_number, track_number, release_date, text and link"
+// @Success 200 {object} entities.ImportReport "Outcome of every row"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 415 {object} ProblemDetails "Unknown input format"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/import [post]
+func (h *handler) PostSongsImport(w http.ResponseWriter, r *http.Request) {
+	//get options from request
+	opts := songImporter.Options{}
+	if format := r.URL.Query().Get("format"); format != "" {
+		var err error
+		opts.Format, err = songImporter.ParseFormat(format)
+		if err != nil {
+			h.logger.Debugf("invalid format: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "format", "format must be csv or jsonl")
+			return
+		}
+	} else {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format, ok := importMediaTypes[mediaType]
+		if !ok {
+			h.logger.Debugf("unknown import media type `%v`", mediaType)
+			h.writeProblem(w, r, http.StatusUnsupportedMediaType, codeUnsupportedMediaType, "", "body must be text/csv or application/x-ndjson")
+			return
+		}
+		opts.Format = format
+	}
+	if enrich := r.URL.Query().Get("enrich"); enrich != "" {
+		var err error
+		opts.Enrich, err = strconv.ParseBool(enrich)
+		if err != nil {
+			h.logger.Debugf("invalid enrich: %v", err)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "enrich", "enrich must be a boolean")
+			return
+		}
+	}
+	defer r.Body.Close()
+
+	//import
+	importer := songImporter.NewImporter(h.storage, h.logger, songImporter.Config{})
+	report, err := importer.Import(r.Context(), r.Body, opts)
+	var inputErr songImporter.InputError
+	if r.Context().Err() != nil {
+		h.logger.Debugf("import was cancelled after %d rows: %v", len(report.Rows), err)
+		return
+	} else if errors.As(err, &inputErr) {
+		h.logger.Debugf("invalid import input: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", inputErr.Message)
+		return
+	} else if dberrors.KindOf(err) != dberrors.KindUnknown {
+		h.logger.Debugf("import stopped after %d rows by the storage: %v", len(report.Rows), err)
+		h.writeStorageError(w, r, err)
+		return
+	} else if err != nil {
+		h.logger.Debugf("import stopped after %d rows: %v", len(report.Rows), err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidBody, "", "request body could not be read")
+		return
+	}
+	h.logger.Debugf("imported songs: %d created, %d skipped, %d failed", report.Created, report.Skipped, report.Failed)
+
+	//answer
+	jsonAnswer, err := json.Marshal(report)
+	if err != nil {
+		h.logger.Debugf("failed to marshal import report: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+}
@@ -0,0 +1,112 @@
+package httphandlers
+
+import (
+	"bytes"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_PostSongsImport(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	request := func(target, contentType, body string) *http.Request {
+		r := httptest.NewRequest("POST", target, bytes.NewBufferString(body))
+		r.Header.Set("Content-Type", contentType)
+		return r
+	}
+	tests := []struct {
+		name           string
+		storage        func(c *gomock.Controller) requiredinterfaces.SongStorage
+		r              *http.Request
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "CSV",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{
+					{Song: "Hysteria", Group: "Muse", EnrichmentStatus: entities.EnrichmentPending},
+				}).Return([]entities.ImportedSong{{ID: 10}}, nil)
+				return storage
+			},
+			r:              request("/api/v1/songs/import?enrich=true", "text/csv; charset=utf-8", "group,song\nMuse,Hysteria\nMuse,\n"),
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"created":1,"skipped":0,"failed":1,"rows":[` +
+				`{"row":1,"status":"created","id":10,"group":"Muse","song":"Hysteria"},` +
+				`{"row":2,"status":"failed","group":"Muse","field":"song","message":"song name is empty"}]}`,
+		},
+		{
+			name: "Format parameter",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				storage := mocks.NewMockSongStorage(c)
+				storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{{Song: "Hysteria", Group: "Muse"}}).
+					Return([]entities.ImportedSong{{ID: 3, Existed: true}}, nil)
+				return storage
+			},
+			r:              request("/api/v1/songs/import?format=jsonl", "application/octet-stream", `{"group":"Muse","song":"Hysteria"}`),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"created":0,"skipped":1,"failed":0,"rows":[{"row":1,"status":"skipped","id":3,"group":"Muse","song":"Hysteria","message":"song is in the library already"}]}`,
+		},
+		{
+			name: "Unknown media type",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request("/api/v1/songs/import", "application/json", `[]`),
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   problemBody(http.StatusUnsupportedMediaType, codeUnsupportedMediaType, "", "body must be text/csv or application/x-ndjson", "/api/v1/songs/import"),
+		},
+		{
+			name: "Unknown format",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request("/api/v1/songs/import?format=xml", "text/csv", "group,song\n"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "format", "format must be csv or jsonl", "/api/v1/songs/import"),
+		},
+		{
+			name: "Bad enrich",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request("/api/v1/songs/import?enrich=maybe", "text/csv", "group,song\n"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidParameter, "enrich", "enrich must be a boolean", "/api/v1/songs/import"),
+		},
+		{
+			name: "Missing CSV column",
+			storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+				return mocks.NewMockSongStorage(c)
+			},
+			r:              request("/api/v1/songs/import", "text/csv", "group\nMuse\n"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidBody, "", "CSV column `song` is missing", "/api/v1/songs/import"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.storage(c),
+				logger:  sugar,
+			}
+			w := httptest.NewRecorder()
+
+			h.PostSongsImport(w, tt.r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertBody(t, tt.expectedBody, w)
+		})
+	}
+}
@@ -14,25 +14,85 @@ type FilterRequest struct {
 	Offset int `json:"offset"`
 	Limit  int `json:"limit"`
 	Filter entities.Song
+	// Sort is a field name, "-" prefix gives a descending order.
+	Sort            string             `json:"sort,omitempty" example:"-release_date"`
+	IDs             []uint64           `json:"ids,omitempty"`
+	SongMatch       entities.MatchMode `json:"song_match,omitempty" enums:"contains,prefix,exact"`
+	GroupMatch      entities.MatchMode `json:"group_match,omitempty" enums:"contains,prefix,exact"`
+	GroupIDs        []uint64           `json:"group_ids,omitempty"`
+	Groups          []string           `json:"groups,omitempty"`
+	ReleaseDateFrom string             `json:"release_date_from,omitempty" example:"2006-01-02"`
+	ReleaseDateTo   string             `json:"release_date_to,omitempty" example:"2006-01-02"`
+	HasLyrics       *bool              `json:"has_lyrics,omitempty"`
+	HasLink         *bool              `json:"has_link,omitempty"`
+}
+
+// songListQuery turns the request into a storage query. Errors are entities.FieldError.
+func (f FilterRequest) songListQuery() (entities.SongListQuery, error) {
+	filter := entities.SongFilter{
+		IDs:         f.IDs,
+		Song:        f.Filter.Song,
+		SongMatch:   f.SongMatch,
+		Group:       f.Filter.Group,
+		GroupMatch:  f.GroupMatch,
+		GroupIDs:    f.GroupIDs,
+		Groups:      f.Groups,
+		ReleaseDate: f.Filter.ReleaseDate,
+		HasLyrics:   f.HasLyrics,
+		HasLink:     f.HasLink,
+	}
+	if f.Filter.ID != 0 {
+		filter.IDs = append(filter.IDs, f.Filter.ID)
+	}
+	if f.Filter.GroupID != 0 {
+		filter.GroupIDs = append(filter.GroupIDs, f.Filter.GroupID)
+	}
+	var err error
+	filter.ReleaseDateFrom, err = releaseDateParam("release_date_from", f.ReleaseDateFrom)
+	if err != nil {
+		return entities.SongListQuery{}, err
+	}
+	filter.ReleaseDateTo, err = releaseDateParam("release_date_to", f.ReleaseDateTo)
+	if err != nil {
+		return entities.SongListQuery{}, err
+	}
+	err = filter.Validate()
+	if err != nil {
+		return entities.SongListQuery{}, err
+	}
+
+	sort, err := entities.ParseSongSort(f.Sort)
+	if err != nil {
+		return entities.SongListQuery{}, err
+	}
+	return entities.SongListQuery{
+		Filter: filter,
+		Sort:   sort,
+		Offset: f.Offset,
+		Limit:  f.Limit,
+	}, nil
 }
 
 // SongsListGet godoc
 // @Summary Returns list of songs
-// @Description filtration and pagination are supported
+// @Description filtration, sorting and pagination are supported, limit is 50 by default and 500 at most
 // @Accept  json
 // @Produce application/json
 // @Param filter body FilterRequest false "Filter params"
 // @Success 201 {array} FilterRequest "Songs list"
 // @Failure 204 {object} nil "No songs found"
-// @Failure 400 {object} nil "Bad request"
-// @Failure 500 {object} nil "Internal server error"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Deprecated
 // @Router /songs [post]
 func (h *handler) SongsListGet(w http.ResponseWriter, r *http.Request) {
 	//get filter from request
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		h.logger.Debugf("failed to read body: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeInternalError(w, r)
 		return
 	}
 	defer r.Body.Close()
@@ -42,25 +102,32 @@ func (h *handler) SongsListGet(w http.ResponseWriter, r *http.Request) {
 		err = json.Unmarshal(bodyBytes, &filter)
 		if err != nil {
 			h.logger.Debugf("failed to unmarshal body: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
+			h.writeBodyProblem(w, r, err)
 			return
 		}
 	}
 
+	listQuery, err := filter.songListQuery()
+	if err != nil {
+		h.logger.Debugf("invalid filter: %v", err)
+		h.writeValidationProblem(w, r, err)
+		return
+	}
+
 	//get songs list
-	songs, err := h.storage.GetSongList(r.Context(), filter.Filter, filter.Offset, filter.Limit)
+	page, err := h.storage.GetSongList(r.Context(), listQuery)
 	if errors.Is(err, dberrors.NewNotFoundErr()) {
 		h.logger.Debugf("no songs with rerquested params: %v", err)
 		w.WriteHeader(http.StatusNoContent)
 		return
 	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		h.logger.Debugf("failed get songs: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		h.writeStorageError(w, r, err)
 		return
 	}
 
 	//answer
-	jsonAnswer, err := json.Marshal(songs)
+	jsonAnswer, err := json.Marshal(page.Songs)
 	w.Header().Add("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write(jsonAnswer)
@@ -2,7 +2,6 @@ package httphandlers
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -39,17 +38,16 @@ func Test_handler_SongsListGet(t *testing.T) {
 			fields: fields{
 				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
 					storage := mocks.NewMockSongStorage(c)
-					filter := entities.Song{
+					expectedFilter := entities.SongFilter{
 						Song:  "some song",
 						Group: "some group",
 					}
-					expectedFilter := filter
 					songs := []entities.Song{
 						{
 							ID:          1,
 							Song:        "some song",
 							Group:       "some group",
-							ReleaseDate: "10.10.2010",
+							ReleaseDate: entities.MustParseReleaseDate("10.10.2010"),
 							Text:        "Sample text",
 							Link:        "https://example.com/song1",
 						},
@@ -57,12 +55,12 @@ func Test_handler_SongsListGet(t *testing.T) {
 							ID:          2,
 							Song:        "some song",
 							Group:       "some group",
-							ReleaseDate: "12.12.2012",
+							ReleaseDate: entities.MustParseReleaseDate("12.12.2012"),
 							Text:        "Another sample text",
 							Link:        "https://example.com/song2",
 						},
 					}
-					storage.EXPECT().GetSongList(gomock.Any(), expectedFilter, 20, 10).Return(songs, nil)
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{Filter: expectedFilter, Sort: entities.SongSort{Field: "id"}, Offset: 20, Limit: 10}).Return(entities.SongPage{Songs: songs}, nil)
 					return storage
 				},
 			},
@@ -83,7 +81,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 					"id":1,
 					"song":"some song",
 					"group":"some group",
-					"release_date":"10.10.2010",
+					"release_date":"2010-10-10",
 					"text":"Sample text",
 					"link":"https://example.com/song1"
 				},
@@ -91,7 +89,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 					"id":2,
 					"song":"some song",
 					"group":"some group",
-					"release_date":"12.12.2012",
+					"release_date":"2012-12-12",
 					"text":"Another sample text",
 					"link":"https://example.com/song2"
 				}
@@ -102,7 +100,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 			fields: fields{
 				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
 					storage := mocks.NewMockSongStorage(c)
-					storage.EXPECT().GetSongList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, dberrors.NewNotFoundErr())
+					storage.EXPECT().GetSongList(gomock.Any(), gomock.Any()).Return(entities.SongPage{}, dberrors.NewNotFoundErr())
 					return storage
 				},
 			},
@@ -120,12 +118,45 @@ func Test_handler_SongsListGet(t *testing.T) {
 			expectedStatus: http.StatusNoContent,
 			expectedBody:   "",
 		},
+		{
+			name: "Sorted by release date",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{
+						Filter: entities.SongFilter{IDs: []uint64{5}, GroupIDs: []uint64{2, 4}, ReleaseDateFrom: entities.MustParseReleaseDate("10.10.2010")},
+						Sort:   entities.SongSort{Field: "release_date", Desc: true},
+					}).Return(entities.SongPage{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/songs", bytes.NewBufferString(`{"filter":{"id":5,"group_id":4},"group_ids":[2],"release_date_from":"10.10.2010","sort":"-release_date"}`)),
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
+		{
+			name: "Bad sort",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/songs", bytes.NewBufferString(`{"sort":"text"}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidField, "sort", "songs can be sorted by id, song, group, release_date, disc_number, track_number", "/songs"),
+		},
 		{
 			name: "Storage error",
 			fields: fields{
 				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
 					storage := mocks.NewMockSongStorage(c)
-					storage.EXPECT().GetSongList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("test error"))
+					storage.EXPECT().GetSongList(gomock.Any(), gomock.Any()).Return(entities.SongPage{}, fmt.Errorf("test error"))
 					return storage
 				},
 			},
@@ -141,7 +172,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 				}`)),
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "",
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/songs"),
 		},
 		{
 			name: "Empty request body",
@@ -153,7 +184,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 							ID:          1,
 							Song:        "some song",
 							Group:       "some group",
-							ReleaseDate: "10.10.2010",
+							ReleaseDate: entities.MustParseReleaseDate("10.10.2010"),
 							Text:        "Sample text",
 							Link:        "https://example.com/song1",
 						},
@@ -161,12 +192,12 @@ func Test_handler_SongsListGet(t *testing.T) {
 							ID:          2,
 							Song:        "some song",
 							Group:       "some group",
-							ReleaseDate: "12.12.2012",
+							ReleaseDate: entities.MustParseReleaseDate("12.12.2012"),
 							Text:        "Another sample text",
 							Link:        "https://example.com/song2",
 						},
 					}
-					storage.EXPECT().GetSongList(gomock.Any(), entities.Song{}, 0, 0).Return(songs, nil)
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{Sort: entities.SongSort{Field: "id"}}).Return(entities.SongPage{Songs: songs}, nil)
 					return storage
 				},
 			},
@@ -180,7 +211,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 					"id":1,
 					"song":"some song",
 					"group":"some group",
-					"release_date":"10.10.2010",
+					"release_date":"2010-10-10",
 					"text":"Sample text",
 					"link":"https://example.com/song1"
 				},
@@ -188,7 +219,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 					"id":2,
 					"song":"some song",
 					"group":"some group",
-					"release_date":"12.12.2012",
+					"release_date":"2012-12-12",
 					"text":"Another sample text",
 					"link":"https://example.com/song2"
 				}
@@ -204,7 +235,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 							ID:          1,
 							Song:        "some song",
 							Group:       "some group",
-							ReleaseDate: "10.10.2010",
+							ReleaseDate: entities.MustParseReleaseDate("10.10.2010"),
 							Text:        "Sample text",
 							Link:        "https://example.com/song1",
 						},
@@ -212,12 +243,12 @@ func Test_handler_SongsListGet(t *testing.T) {
 							ID:          2,
 							Song:        "some song",
 							Group:       "some group",
-							ReleaseDate: "12.12.2012",
+							ReleaseDate: entities.MustParseReleaseDate("12.12.2012"),
 							Text:        "Another sample text",
 							Link:        "https://example.com/song2",
 						},
 					}
-					storage.EXPECT().GetSongList(gomock.Any(), entities.Song{}, 0, 0).Return(songs, nil)
+					storage.EXPECT().GetSongList(gomock.Any(), entities.SongListQuery{Sort: entities.SongSort{Field: "id"}}).Return(entities.SongPage{Songs: songs}, nil)
 					return storage
 				},
 			},
@@ -231,7 +262,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 					"id":1,
 					"song":"some song",
 					"group":"some group",
-					"release_date":"10.10.2010",
+					"release_date":"2010-10-10",
 					"text":"Sample text",
 					"link":"https://example.com/song1"
 				},
@@ -239,7 +270,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 					"id":2,
 					"song":"some song",
 					"group":"some group",
-					"release_date":"12.12.2012",
+					"release_date":"2012-12-12",
 					"text":"Another sample text",
 					"link":"https://example.com/song2"
 				}
@@ -260,17 +291,7 @@ func Test_handler_SongsListGet(t *testing.T) {
 			h.SongsListGet(tt.args.w, tt.args.r)
 
 			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
-			//assert.Equal(t, tt.expectedBody, tt.args.w.Body.String())
-			if tt.expectedBody != "" {
-				var expected, actual interface{}
-				err := json.Unmarshal([]byte(tt.expectedBody), &expected)
-				assert.NoError(t, err)
-				err = json.Unmarshal(tt.args.w.Body.Bytes(), &actual)
-				assert.NoError(t, err)
-				assert.Equal(t, expected, actual)
-			} else {
-				assert.Equal(t, tt.expectedBody, tt.args.w.Body.String())
-			}
+			assertBody(t, tt.expectedBody, tt.args.w)
 		})
 	}
 }
@@ -0,0 +1,93 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// SongsMergeMessage names the song to keep and its duplicates.
+type SongsMergeMessage struct {
+	ID           uint64   `json:"id" example:"3"`
+	DuplicateIDs []uint64 `json:"duplicate_ids" example:"5,8"`
+}
+
+// PostSongsMerge godoc
+// @Summary Merges duplicate songs
+// @Description Merges metadata of the duplicates into the song and moves the duplicates to the trash in one step.
+// @Description The song keeps its name and takes the most precise release date and the longest lyrics of all of them,
+// @Description a link and an album position are taken from the duplicates only if the song has none.
+// @Description Duplicates must be songs of the same group, GET /api/v1/duplicates suggests them.
+// @Description The merge is recorded in the history of the song, the duplicates can be restored from the trash.
+// @Accept  json
+// @Produce json
+// @Param merge body SongsMergeMessage true "The song to keep and its duplicates"
+// @Success 200 {object} entities.Song "Merged song"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 404 {object} ProblemDetails "Song or duplicate not found"
+// @Failure 422 {object} ProblemDetails "A duplicate is of another group"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/songs/merge [post]
+func (h *handler) PostSongsMerge(w http.ResponseWriter, r *http.Request) {
+	//get songs from request
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Debugf("failed to read body: %v", err)
+		h.writeInternalError(w, r)
+		return
+	}
+	defer r.Body.Close()
+
+	message := SongsMergeMessage{}
+	err = json.Unmarshal(bodyBytes, &message)
+	if err != nil {
+		h.logger.Debugf("failed to unmarshal body: %v", err)
+		h.writeBodyProblem(w, r, err)
+		return
+	}
+	if message.ID == 0 {
+		h.logger.Debugf("song id is empty")
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "id", "song id is empty")
+		return
+	}
+	if len(message.DuplicateIDs) == 0 {
+		h.logger.Debugf("duplicate ids are empty")
+		h.writeProblem(w, r, http.StatusBadRequest, codeRequiredField, "duplicate_ids", "duplicate ids are empty")
+		return
+	}
+	for _, id := range message.DuplicateIDs {
+		if id == 0 || id == message.ID {
+			h.logger.Debugf("invalid duplicate id %d", id)
+			h.writeProblem(w, r, http.StatusBadRequest, codeInvalidField, "duplicate_ids", "duplicate ids must be positive and differ from the song id")
+			return
+		}
+	}
+
+	//merge
+	song, err := h.storage.MergeSongs(r.Context(), message.ID, message.DuplicateIDs)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		h.logger.Debugf("no song %d or some of its duplicates %v, err: %v", message.ID, message.DuplicateIDs, err)
+		h.writeProblem(w, r, http.StatusNotFound, codeNotFound, "", "song or duplicate not found")
+		return
+	} else if dberrors.KindOf(err) == dberrors.KindInvalid {
+		h.logger.Debugf("songs can not be merged: %v", err)
+		h.writeProblem(w, r, http.StatusUnprocessableEntity, codeUnprocessable, "duplicate_ids", "duplicates must be songs of the same group")
+		return
+	} else if err != nil {
+		h.logger.Debugf("failed to merge songs: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(song)
+	w.Header().Add("Content-Type", "application/json")
+	writeETag(w, song.Version)
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
@@ -0,0 +1,180 @@
+package httphandlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_handler_PostSongsMerge(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	type fields struct {
+		storage func(c *gomock.Controller) requiredinterfaces.SongStorage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		args           args
+		expectedStatus int
+		expectedBody   string
+		expectedETag   string
+	}{
+		{
+			name: "Normal",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().MergeSongs(gomock.Any(), uint64(3), []uint64{1, 5}).
+						Return(entities.Song{ID: 3, Song: "Hysteria", GroupID: 2, Group: "Muse", Text: "It's bugging me", Version: 4}, nil)
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":3,"duplicate_ids":[1,5]}`)),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":3,"song":"Hysteria","group_id":2,"group":"Muse","text":"It's bugging me","version":4}`,
+			expectedETag:   `"4"`,
+		},
+		{
+			name: "Invalid JSON",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeInvalidBody, "", "request body is not a valid JSON", "/api/v1/songs/merge"),
+		},
+		{
+			name: "Without song",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"duplicate_ids":[1]}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeRequiredField, "id", "song id is empty", "/api/v1/songs/merge"),
+		},
+		{
+			name: "Without duplicates",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":3,"duplicate_ids":[]}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, codeRequiredField, "duplicate_ids", "duplicate ids are empty", "/api/v1/songs/merge"),
+		},
+		{
+			name: "Song merged into itself",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					return mocks.NewMockSongStorage(c)
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":3,"duplicate_ids":[1,3]}`)),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problemBody(http.StatusBadRequest, codeInvalidField, "duplicate_ids",
+				"duplicate ids must be positive and differ from the song id", "/api/v1/songs/merge"),
+		},
+		{
+			name: "Not found",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().MergeSongs(gomock.Any(), uint64(3), []uint64{1}).Return(entities.Song{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":3,"duplicate_ids":[1]}`)),
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, codeNotFound, "", "song or duplicate not found", "/api/v1/songs/merge"),
+		},
+		{
+			name: "Duplicate of another group",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().MergeSongs(gomock.Any(), uint64(3), []uint64{1}).
+						Return(entities.Song{}, dberrors.NewInvalidErr(fmt.Errorf("song 1 is of another group")))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":3,"duplicate_ids":[1]}`)),
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: problemBody(http.StatusUnprocessableEntity, codeUnprocessable, "duplicate_ids",
+				"duplicates must be songs of the same group", "/api/v1/songs/merge"),
+		},
+		{
+			name: "Db error",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.SongStorage {
+					storage := mocks.NewMockSongStorage(c)
+					storage.EXPECT().MergeSongs(gomock.Any(), uint64(3), []uint64{1}).Return(entities.Song{}, errors.New("test error"))
+					return storage
+				},
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("POST", "/api/v1/songs/merge", bytes.NewBufferString(`{"id":3,"duplicate_ids":[1]}`)),
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, codeInternal, "", "internal server error", "/api/v1/songs/merge"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			h := &handler{
+				storage: tt.fields.storage(c),
+				logger:  sugar,
+			}
+			h.PostSongsMerge(tt.args.w, tt.args.r)
+
+			assert.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			assert.Equal(t, tt.expectedETag, tt.args.w.Header().Get("ETag"))
+			assertBody(t, tt.expectedBody, tt.args.w)
+		})
+	}
+}
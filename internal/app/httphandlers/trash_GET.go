@@ -0,0 +1,55 @@
+package httphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"net/http"
+)
+
+// GetTrash godoc
+// @Summary Returns removed songs
+// @Description Songs in the trash can be restored until they are purged, the most recently removed go first.
+// @Description An empty list is returned if the trash is empty.
+// @Produce json
+// @Param offset query int false "Offset"
+// @Param limit query int false "Page size, 50 by default, 500 at most"
+// @Success 200 {array} entities.TrashedSong "Removed songs"
+// @Failure 400 {object} ProblemDetails "Bad request"
+// @Failure 503 {object} ProblemDetails "Storage is unavailable, retry after Retry-After"
+// @Failure 504 {object} ProblemDetails "Storage timed out, retry after Retry-After"
+// @Failure 500 {object} ProblemDetails "Internal server error"
+// @Router /api/v1/trash [get]
+func (h *handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		h.logger.Debugf("invalid offset: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "offset", "offset must be a non-negative integer")
+		return
+	}
+	limit, err := intQueryParam(r, "limit", 0)
+	if err != nil {
+		h.logger.Debugf("invalid limit: %v", err)
+		h.writeProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "limit", "limit must be an integer")
+		return
+	}
+	limit = entities.SongListQuery{Limit: limit}.PageLimit()
+
+	//get trash
+	trash, err := h.storage.GetTrash(r.Context(), offset, limit)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		trash = []entities.TrashedSong{}
+	} else if err != nil {
+		h.logger.Debugf("failed to get trash: %v", err)
+		h.writeStorageError(w, r, err)
+		return
+	}
+
+	//answer
+	jsonAnswer, err := json.Marshal(trash)
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonAnswer)
+	return
+}
@@ -0,0 +1,21 @@
+package httphandlers
+
+import (
+	"github.com/go-chi/chi"
+	"net/http"
+	"strconv"
+)
+
+// idFromURL parses the "id" URL parameter of the route.
+func idFromURL(r *http.Request) (uint64, error) {
+	return strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+}
+
+// intQueryParam parses an optional integer query parameter, def is returned if the parameter is absent.
+func intQueryParam(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,55 @@
+package httphandlers
+
+import (
+	"context"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withURLParams adds chi URL parameters to the request, as if it was routed by chi.
+func withURLParams(r *http.Request, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func Test_idFromURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *http.Request
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "Normal",
+			r:    withURLParams(httptest.NewRequest("GET", "/groups/5", nil), map[string]string{"id": "5"}),
+			want: 5,
+		},
+		{
+			name:    "Not a number",
+			r:       withURLParams(httptest.NewRequest("GET", "/groups/abc", nil), map[string]string{"id": "abc"}),
+			wantErr: true,
+		},
+		{
+			name:    "No param",
+			r:       httptest.NewRequest("GET", "/groups", nil),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := idFromURL(tt.r)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
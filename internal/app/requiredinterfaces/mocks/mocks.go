@@ -8,6 +8,7 @@ import (
 	context "context"
 	entities "musiclib/internal/app/entities"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,20 +37,18 @@ func (m *MockExtraDataProvider) EXPECT() *MockExtraDataProviderMockRecorder {
 }
 
 // GetExtraSongData mocks base method.
-func (m *MockExtraDataProvider) GetExtraSongData(song entities.Song) (string, string, string, error) {
+func (m *MockExtraDataProvider) GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExtraSongData", song)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(string)
-	ret3, _ := ret[3].(error)
-	return ret0, ret1, ret2, ret3
+	ret := m.ctrl.Call(m, "GetExtraSongData", ctx, song)
+	ret0, _ := ret[0].(entities.ExtraSongData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetExtraSongData indicates an expected call of GetExtraSongData.
-func (mr *MockExtraDataProviderMockRecorder) GetExtraSongData(song interface{}) *gomock.Call {
+func (mr *MockExtraDataProviderMockRecorder) GetExtraSongData(ctx, song interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExtraSongData", reflect.TypeOf((*MockExtraDataProvider)(nil).GetExtraSongData), song)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExtraSongData", reflect.TypeOf((*MockExtraDataProvider)(nil).GetExtraSongData), ctx, song)
 }
 
 // MockSongStorage is a mock of SongStorage interface.
@@ -75,19 +74,167 @@ func (m *MockSongStorage) EXPECT() *MockSongStorageMockRecorder {
 	return m.recorder
 }
 
-// GetSongList mocks base method.
-func (m *MockSongStorage) GetSongList(ctx context.Context, filter entities.Song, offset, limit int) ([]entities.Song, error) {
+// EnqueueEnrichment mocks base method.
+func (m *MockSongStorage) EnqueueEnrichment(ctx context.Context, songID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueEnrichment", ctx, songID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueEnrichment indicates an expected call of EnqueueEnrichment.
+func (mr *MockSongStorageMockRecorder) EnqueueEnrichment(ctx, songID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueEnrichment", reflect.TypeOf((*MockSongStorage)(nil).EnqueueEnrichment), ctx, songID)
+}
+
+// ExportSongs mocks base method.
+func (m *MockSongStorage) ExportSongs(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSongs", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSongs indicates an expected call of ExportSongs.
+func (mr *MockSongStorageMockRecorder) ExportSongs(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSongs", reflect.TypeOf((*MockSongStorage)(nil).ExportSongs), ctx, filter, fn)
+}
+
+// GetAlbum mocks base method.
+func (m *MockSongStorage) GetAlbum(ctx context.Context, id uint64) (entities.Album, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbum", ctx, id)
+	ret0, _ := ret[0].(entities.Album)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbum indicates an expected call of GetAlbum.
+func (mr *MockSongStorageMockRecorder) GetAlbum(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbum", reflect.TypeOf((*MockSongStorage)(nil).GetAlbum), ctx, id)
+}
+
+// GetAlbumList mocks base method.
+func (m *MockSongStorage) GetAlbumList(ctx context.Context, filter entities.Album, offset, limit int) ([]entities.Album, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbumList", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]entities.Album)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbumList indicates an expected call of GetAlbumList.
+func (mr *MockSongStorageMockRecorder) GetAlbumList(ctx, filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbumList", reflect.TypeOf((*MockSongStorage)(nil).GetAlbumList), ctx, filter, offset, limit)
+}
+
+// GetAlbumTracks mocks base method.
+func (m *MockSongStorage) GetAlbumTracks(ctx context.Context, id uint64) ([]entities.Song, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSongList", ctx, filter, offset, limit)
+	ret := m.ctrl.Call(m, "GetAlbumTracks", ctx, id)
 	ret0, _ := ret[0].([]entities.Song)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetAlbumTracks indicates an expected call of GetAlbumTracks.
+func (mr *MockSongStorageMockRecorder) GetAlbumTracks(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbumTracks", reflect.TypeOf((*MockSongStorage)(nil).GetAlbumTracks), ctx, id)
+}
+
+// GetDuplicateSongs mocks base method.
+func (m *MockSongStorage) GetDuplicateSongs(ctx context.Context, similarity float64, offset, limit int) ([]entities.DuplicateSongs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicateSongs", ctx, similarity, offset, limit)
+	ret0, _ := ret[0].([]entities.DuplicateSongs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicateSongs indicates an expected call of GetDuplicateSongs.
+func (mr *MockSongStorageMockRecorder) GetDuplicateSongs(ctx, similarity, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicateSongs", reflect.TypeOf((*MockSongStorage)(nil).GetDuplicateSongs), ctx, similarity, offset, limit)
+}
+
+// GetGroup mocks base method.
+func (m *MockSongStorage) GetGroup(ctx context.Context, id uint64) (entities.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", ctx, id)
+	ret0, _ := ret[0].(entities.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockSongStorageMockRecorder) GetGroup(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockSongStorage)(nil).GetGroup), ctx, id)
+}
+
+// GetGroupList mocks base method.
+func (m *MockSongStorage) GetGroupList(ctx context.Context, filter entities.Group, offset, limit int) ([]entities.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupList", ctx, filter, offset, limit)
+	ret0, _ := ret[0].([]entities.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupList indicates an expected call of GetGroupList.
+func (mr *MockSongStorageMockRecorder) GetGroupList(ctx, filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupList", reflect.TypeOf((*MockSongStorage)(nil).GetGroupList), ctx, filter, offset, limit)
+}
+
+// GetSong mocks base method.
+func (m *MockSongStorage) GetSong(ctx context.Context, id uint64) (entities.Song, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSong", ctx, id)
+	ret0, _ := ret[0].(entities.Song)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSong indicates an expected call of GetSong.
+func (mr *MockSongStorageMockRecorder) GetSong(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSong", reflect.TypeOf((*MockSongStorage)(nil).GetSong), ctx, id)
+}
+
+// GetSongHistory mocks base method.
+func (m *MockSongStorage) GetSongHistory(ctx context.Context, id uint64, offset, limit int) ([]entities.SongRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSongHistory", ctx, id, offset, limit)
+	ret0, _ := ret[0].([]entities.SongRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSongHistory indicates an expected call of GetSongHistory.
+func (mr *MockSongStorageMockRecorder) GetSongHistory(ctx, id, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSongHistory", reflect.TypeOf((*MockSongStorage)(nil).GetSongHistory), ctx, id, offset, limit)
+}
+
+// GetSongList mocks base method.
+func (m *MockSongStorage) GetSongList(ctx context.Context, query entities.SongListQuery) (entities.SongPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSongList", ctx, query)
+	ret0, _ := ret[0].(entities.SongPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // GetSongList indicates an expected call of GetSongList.
-func (mr *MockSongStorageMockRecorder) GetSongList(ctx, filter, offset, limit interface{}) *gomock.Call {
+func (mr *MockSongStorageMockRecorder) GetSongList(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSongList", reflect.TypeOf((*MockSongStorage)(nil).GetSongList), ctx, filter, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSongList", reflect.TypeOf((*MockSongStorage)(nil).GetSongList), ctx, query)
 }
 
 // GetSongLyrics mocks base method.
@@ -105,18 +252,164 @@ func (mr *MockSongStorageMockRecorder) GetSongLyrics(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSongLyrics", reflect.TypeOf((*MockSongStorage)(nil).GetSongLyrics), ctx, id)
 }
 
+// GetTrash mocks base method.
+func (m *MockSongStorage) GetTrash(ctx context.Context, offset, limit int) ([]entities.TrashedSong, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, offset, limit)
+	ret0, _ := ret[0].([]entities.TrashedSong)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockSongStorageMockRecorder) GetTrash(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockSongStorage)(nil).GetTrash), ctx, offset, limit)
+}
+
+// ImportSongs mocks base method.
+func (m *MockSongStorage) ImportSongs(ctx context.Context, songs []entities.Song) ([]entities.ImportedSong, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSongs", ctx, songs)
+	ret0, _ := ret[0].([]entities.ImportedSong)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSongs indicates an expected call of ImportSongs.
+func (mr *MockSongStorageMockRecorder) ImportSongs(ctx, songs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSongs", reflect.TypeOf((*MockSongStorage)(nil).ImportSongs), ctx, songs)
+}
+
+// MergeSongs mocks base method.
+func (m *MockSongStorage) MergeSongs(ctx context.Context, id uint64, duplicateIDs []uint64) (entities.Song, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeSongs", ctx, id, duplicateIDs)
+	ret0, _ := ret[0].(entities.Song)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeSongs indicates an expected call of MergeSongs.
+func (mr *MockSongStorageMockRecorder) MergeSongs(ctx, id, duplicateIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeSongs", reflect.TypeOf((*MockSongStorage)(nil).MergeSongs), ctx, id, duplicateIDs)
+}
+
+// RemoveAlbum mocks base method.
+func (m *MockSongStorage) RemoveAlbum(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAlbum", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAlbum indicates an expected call of RemoveAlbum.
+func (mr *MockSongStorageMockRecorder) RemoveAlbum(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAlbum", reflect.TypeOf((*MockSongStorage)(nil).RemoveAlbum), ctx, id)
+}
+
+// RemoveGroup mocks base method.
+func (m *MockSongStorage) RemoveGroup(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveGroup", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveGroup indicates an expected call of RemoveGroup.
+func (mr *MockSongStorageMockRecorder) RemoveGroup(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGroup", reflect.TypeOf((*MockSongStorage)(nil).RemoveGroup), ctx, id)
+}
+
 // RemoveSong mocks base method.
-func (m *MockSongStorage) RemoveSong(ctx context.Context, id uint64) error {
+func (m *MockSongStorage) RemoveSong(ctx context.Context, id uint64, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveSong", ctx, id)
+	ret := m.ctrl.Call(m, "RemoveSong", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveSong indicates an expected call of RemoveSong.
-func (mr *MockSongStorageMockRecorder) RemoveSong(ctx, id interface{}) *gomock.Call {
+func (mr *MockSongStorageMockRecorder) RemoveSong(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSong", reflect.TypeOf((*MockSongStorage)(nil).RemoveSong), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSong", reflect.TypeOf((*MockSongStorage)(nil).RemoveSong), ctx, id, version)
+}
+
+// ReplaceSong mocks base method.
+func (m *MockSongStorage) ReplaceSong(ctx context.Context, song entities.Song) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSong", ctx, song)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceSong indicates an expected call of ReplaceSong.
+func (mr *MockSongStorageMockRecorder) ReplaceSong(ctx, song interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSong", reflect.TypeOf((*MockSongStorage)(nil).ReplaceSong), ctx, song)
+}
+
+// RestoreSong mocks base method.
+func (m *MockSongStorage) RestoreSong(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSong", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSong indicates an expected call of RestoreSong.
+func (mr *MockSongStorageMockRecorder) RestoreSong(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSong", reflect.TypeOf((*MockSongStorage)(nil).RestoreSong), ctx, id)
+}
+
+// RevertSong mocks base method.
+func (m *MockSongStorage) RevertSong(ctx context.Context, id uint64, revision int) (entities.Song, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertSong", ctx, id, revision)
+	ret0, _ := ret[0].(entities.Song)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertSong indicates an expected call of RevertSong.
+func (mr *MockSongStorageMockRecorder) RevertSong(ctx, id, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertSong", reflect.TypeOf((*MockSongStorage)(nil).RevertSong), ctx, id, revision)
+}
+
+// SaveAlbum mocks base method.
+func (m *MockSongStorage) SaveAlbum(ctx context.Context, album entities.Album) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAlbum", ctx, album)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveAlbum indicates an expected call of SaveAlbum.
+func (mr *MockSongStorageMockRecorder) SaveAlbum(ctx, album interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAlbum", reflect.TypeOf((*MockSongStorage)(nil).SaveAlbum), ctx, album)
+}
+
+// SaveGroup mocks base method.
+func (m *MockSongStorage) SaveGroup(ctx context.Context, group entities.Group) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveGroup", ctx, group)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveGroup indicates an expected call of SaveGroup.
+func (mr *MockSongStorageMockRecorder) SaveGroup(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGroup", reflect.TypeOf((*MockSongStorage)(nil).SaveGroup), ctx, group)
 }
 
 // SaveSong mocks base method.
@@ -134,6 +427,64 @@ func (mr *MockSongStorageMockRecorder) SaveSong(ctx, song interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSong", reflect.TypeOf((*MockSongStorage)(nil).SaveSong), ctx, song)
 }
 
+// SearchSongs mocks base method.
+func (m *MockSongStorage) SearchSongs(ctx context.Context, query entities.SearchQuery) ([]entities.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchSongs", ctx, query)
+	ret0, _ := ret[0].([]entities.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchSongs indicates an expected call of SearchSongs.
+func (mr *MockSongStorageMockRecorder) SearchSongs(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchSongs", reflect.TypeOf((*MockSongStorage)(nil).SearchSongs), ctx, query)
+}
+
+// SuggestNames mocks base method.
+func (m *MockSongStorage) SuggestNames(ctx context.Context, text string, similarity float64, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestNames", ctx, text, similarity, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestNames indicates an expected call of SuggestNames.
+func (mr *MockSongStorageMockRecorder) SuggestNames(ctx, text, similarity, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestNames", reflect.TypeOf((*MockSongStorage)(nil).SuggestNames), ctx, text, similarity, limit)
+}
+
+// UpdateAlbum mocks base method.
+func (m *MockSongStorage) UpdateAlbum(ctx context.Context, album entities.Album) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlbum", ctx, album)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlbum indicates an expected call of UpdateAlbum.
+func (mr *MockSongStorageMockRecorder) UpdateAlbum(ctx, album interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlbum", reflect.TypeOf((*MockSongStorage)(nil).UpdateAlbum), ctx, album)
+}
+
+// UpdateGroup mocks base method.
+func (m *MockSongStorage) UpdateGroup(ctx context.Context, group entities.Group) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGroup", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGroup indicates an expected call of UpdateGroup.
+func (mr *MockSongStorageMockRecorder) UpdateGroup(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroup", reflect.TypeOf((*MockSongStorage)(nil).UpdateGroup), ctx, group)
+}
+
 // UpdateSong mocks base method.
 func (m *MockSongStorage) UpdateSong(ctx context.Context, song entities.Song) error {
 	m.ctrl.T.Helper()
@@ -147,3 +498,278 @@ func (mr *MockSongStorageMockRecorder) UpdateSong(ctx, song interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSong", reflect.TypeOf((*MockSongStorage)(nil).UpdateSong), ctx, song)
 }
+
+// MockEnrichmentJobStorage is a mock of EnrichmentJobStorage interface.
+type MockEnrichmentJobStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnrichmentJobStorageMockRecorder
+}
+
+// MockEnrichmentJobStorageMockRecorder is the mock recorder for MockEnrichmentJobStorage.
+type MockEnrichmentJobStorageMockRecorder struct {
+	mock *MockEnrichmentJobStorage
+}
+
+// NewMockEnrichmentJobStorage creates a new mock instance.
+func NewMockEnrichmentJobStorage(ctrl *gomock.Controller) *MockEnrichmentJobStorage {
+	mock := &MockEnrichmentJobStorage{ctrl: ctrl}
+	mock.recorder = &MockEnrichmentJobStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEnrichmentJobStorage) EXPECT() *MockEnrichmentJobStorageMockRecorder {
+	return m.recorder
+}
+
+// ClaimEnrichmentJob mocks base method.
+func (m *MockEnrichmentJobStorage) ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (entities.EnrichmentJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimEnrichmentJob", ctx, lease)
+	ret0, _ := ret[0].(entities.EnrichmentJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimEnrichmentJob indicates an expected call of ClaimEnrichmentJob.
+func (mr *MockEnrichmentJobStorageMockRecorder) ClaimEnrichmentJob(ctx, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimEnrichmentJob", reflect.TypeOf((*MockEnrichmentJobStorage)(nil).ClaimEnrichmentJob), ctx, lease)
+}
+
+// CompleteEnrichmentJob mocks base method.
+func (m *MockEnrichmentJobStorage) CompleteEnrichmentJob(ctx context.Context, songID uint64, data entities.ExtraSongData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteEnrichmentJob", ctx, songID, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteEnrichmentJob indicates an expected call of CompleteEnrichmentJob.
+func (mr *MockEnrichmentJobStorageMockRecorder) CompleteEnrichmentJob(ctx, songID, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteEnrichmentJob", reflect.TypeOf((*MockEnrichmentJobStorage)(nil).CompleteEnrichmentJob), ctx, songID, data)
+}
+
+// FailEnrichmentJob mocks base method.
+func (m *MockEnrichmentJobStorage) FailEnrichmentJob(ctx context.Context, songID uint64, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailEnrichmentJob", ctx, songID, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailEnrichmentJob indicates an expected call of FailEnrichmentJob.
+func (mr *MockEnrichmentJobStorageMockRecorder) FailEnrichmentJob(ctx, songID, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailEnrichmentJob", reflect.TypeOf((*MockEnrichmentJobStorage)(nil).FailEnrichmentJob), ctx, songID, lastError)
+}
+
+// GetSong mocks base method.
+func (m *MockEnrichmentJobStorage) GetSong(ctx context.Context, id uint64) (entities.Song, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSong", ctx, id)
+	ret0, _ := ret[0].(entities.Song)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSong indicates an expected call of GetSong.
+func (mr *MockEnrichmentJobStorageMockRecorder) GetSong(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSong", reflect.TypeOf((*MockEnrichmentJobStorage)(nil).GetSong), ctx, id)
+}
+
+// RescheduleEnrichmentJob mocks base method.
+func (m *MockEnrichmentJobStorage) RescheduleEnrichmentJob(ctx context.Context, songID uint64, nextAttemptAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleEnrichmentJob", ctx, songID, nextAttemptAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleEnrichmentJob indicates an expected call of RescheduleEnrichmentJob.
+func (mr *MockEnrichmentJobStorageMockRecorder) RescheduleEnrichmentJob(ctx, songID, nextAttemptAt, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleEnrichmentJob", reflect.TypeOf((*MockEnrichmentJobStorage)(nil).RescheduleEnrichmentJob), ctx, songID, nextAttemptAt, lastError)
+}
+
+// MockExtraDataCacheStorage is a mock of ExtraDataCacheStorage interface.
+type MockExtraDataCacheStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockExtraDataCacheStorageMockRecorder
+}
+
+// MockExtraDataCacheStorageMockRecorder is the mock recorder for MockExtraDataCacheStorage.
+type MockExtraDataCacheStorageMockRecorder struct {
+	mock *MockExtraDataCacheStorage
+}
+
+// NewMockExtraDataCacheStorage creates a new mock instance.
+func NewMockExtraDataCacheStorage(ctrl *gomock.Controller) *MockExtraDataCacheStorage {
+	mock := &MockExtraDataCacheStorage{ctrl: ctrl}
+	mock.recorder = &MockExtraDataCacheStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExtraDataCacheStorage) EXPECT() *MockExtraDataCacheStorageMockRecorder {
+	return m.recorder
+}
+
+// GetCachedExtraData mocks base method.
+func (m *MockExtraDataCacheStorage) GetCachedExtraData(ctx context.Context, key string) (entities.CachedExtraData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCachedExtraData", ctx, key)
+	ret0, _ := ret[0].(entities.CachedExtraData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCachedExtraData indicates an expected call of GetCachedExtraData.
+func (mr *MockExtraDataCacheStorageMockRecorder) GetCachedExtraData(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedExtraData", reflect.TypeOf((*MockExtraDataCacheStorage)(nil).GetCachedExtraData), ctx, key)
+}
+
+// PurgeCachedExtraData mocks base method.
+func (m *MockExtraDataCacheStorage) PurgeCachedExtraData(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeCachedExtraData", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeCachedExtraData indicates an expected call of PurgeCachedExtraData.
+func (mr *MockExtraDataCacheStorageMockRecorder) PurgeCachedExtraData(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCachedExtraData", reflect.TypeOf((*MockExtraDataCacheStorage)(nil).PurgeCachedExtraData), ctx, before)
+}
+
+// SaveCachedExtraData mocks base method.
+func (m *MockExtraDataCacheStorage) SaveCachedExtraData(ctx context.Context, entry entities.CachedExtraData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCachedExtraData", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCachedExtraData indicates an expected call of SaveCachedExtraData.
+func (mr *MockExtraDataCacheStorageMockRecorder) SaveCachedExtraData(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCachedExtraData", reflect.TypeOf((*MockExtraDataCacheStorage)(nil).SaveCachedExtraData), ctx, entry)
+}
+
+// MockTrashStorage is a mock of TrashStorage interface.
+type MockTrashStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashStorageMockRecorder
+}
+
+// MockTrashStorageMockRecorder is the mock recorder for MockTrashStorage.
+type MockTrashStorageMockRecorder struct {
+	mock *MockTrashStorage
+}
+
+// NewMockTrashStorage creates a new mock instance.
+func NewMockTrashStorage(ctrl *gomock.Controller) *MockTrashStorage {
+	mock := &MockTrashStorage{ctrl: ctrl}
+	mock.recorder = &MockTrashStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashStorage) EXPECT() *MockTrashStorageMockRecorder {
+	return m.recorder
+}
+
+// PurgeTrash mocks base method.
+func (m *MockTrashStorage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockTrashStorageMockRecorder) PurgeTrash(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockTrashStorage)(nil).PurgeTrash), ctx, before)
+}
+
+// MockSongImportStorage is a mock of SongImportStorage interface.
+type MockSongImportStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSongImportStorageMockRecorder
+}
+
+// MockSongImportStorageMockRecorder is the mock recorder for MockSongImportStorage.
+type MockSongImportStorageMockRecorder struct {
+	mock *MockSongImportStorage
+}
+
+// NewMockSongImportStorage creates a new mock instance.
+func NewMockSongImportStorage(ctrl *gomock.Controller) *MockSongImportStorage {
+	mock := &MockSongImportStorage{ctrl: ctrl}
+	mock.recorder = &MockSongImportStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSongImportStorage) EXPECT() *MockSongImportStorageMockRecorder {
+	return m.recorder
+}
+
+// ImportSongs mocks base method.
+func (m *MockSongImportStorage) ImportSongs(ctx context.Context, songs []entities.Song) ([]entities.ImportedSong, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSongs", ctx, songs)
+	ret0, _ := ret[0].([]entities.ImportedSong)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSongs indicates an expected call of ImportSongs.
+func (mr *MockSongImportStorageMockRecorder) ImportSongs(ctx, songs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSongs", reflect.TypeOf((*MockSongImportStorage)(nil).ImportSongs), ctx, songs)
+}
+
+// MockSongExportStorage is a mock of SongExportStorage interface.
+type MockSongExportStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSongExportStorageMockRecorder
+}
+
+// MockSongExportStorageMockRecorder is the mock recorder for MockSongExportStorage.
+type MockSongExportStorageMockRecorder struct {
+	mock *MockSongExportStorage
+}
+
+// NewMockSongExportStorage creates a new mock instance.
+func NewMockSongExportStorage(ctrl *gomock.Controller) *MockSongExportStorage {
+	mock := &MockSongExportStorage{ctrl: ctrl}
+	mock.recorder = &MockSongExportStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSongExportStorage) EXPECT() *MockSongExportStorageMockRecorder {
+	return m.recorder
+}
+
+// ExportSongs mocks base method.
+func (m *MockSongExportStorage) ExportSongs(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSongs", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSongs indicates an expected call of ExportSongs.
+func (mr *MockSongExportStorageMockRecorder) ExportSongs(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSongs", reflect.TypeOf((*MockSongExportStorage)(nil).ExportSongs), ctx, filter, fn)
+}
@@ -2,19 +2,104 @@ package requiredinterfaces
 
 import (
 	"context"
+	"errors"
 	"musiclib/internal/app/entities"
+	"time"
 )
 
 //go:generate mockgen -source=required_interfaces.go -destination=./mocks/mocks.go -package=mocks
 
+// ErrExtraDataNotFound is returned by an ExtraDataProvider which does not know the song.
+var ErrExtraDataNotFound = errors.New("extra song data not found")
+
+// ErrExtraDataIncomplete is returned with partial data when a source which could give some of the fields has failed.
+// Asking again later may give the rest.
+var ErrExtraDataIncomplete = errors.New("extra song data is incomplete")
+
 type ExtraDataProvider interface {
-	GetExtraSongData(song entities.Song) (releaseDate, text, link string, err error)
+	GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error)
 }
 
 type SongStorage interface {
+	// SaveSong saves a new song, a song of the same group and normalized name gives entities.DuplicateSongError.
 	SaveSong(ctx context.Context, song entities.Song) (id uint64, err error)
-	GetSongList(ctx context.Context, filter entities.Song, offset int, limit int) ([]entities.Song, error)
+	GetSong(ctx context.Context, id uint64) (entities.Song, error)
+	GetSongList(ctx context.Context, query entities.SongListQuery) (entities.SongPage, error)
 	GetSongLyrics(ctx context.Context, id uint64) (string, error)
-	RemoveSong(ctx context.Context, id uint64) error
+	SearchSongs(ctx context.Context, query entities.SearchQuery) ([]entities.SearchResult, error)
+	// SuggestNames returns song and group names similar to the text for a "did you mean" list.
+	SuggestNames(ctx context.Context, text string, similarity float64, limit int) ([]string, error)
+	// RemoveSong moves the song to the trash, a non-zero version must be the current one.
+	RemoveSong(ctx context.Context, id uint64, version int64) error
+	GetTrash(ctx context.Context, offset int, limit int) ([]entities.TrashedSong, error)
+	RestoreSong(ctx context.Context, id uint64) error
+	GetSongHistory(ctx context.Context, id uint64, offset int, limit int) ([]entities.SongRevision, error)
+	// RevertSong brings the song back to its state after the revision and returns it.
+	RevertSong(ctx context.Context, id uint64, revision int) (entities.Song, error)
+	// UpdateSong updates non-empty fields of the song, a non-zero Version must be the current one.
 	UpdateSong(ctx context.Context, song entities.Song) error
+	// ReplaceSong replaces all fields of the song a client can change, a non-zero Version must be the current one.
+	ReplaceSong(ctx context.Context, song entities.Song) error
+	EnqueueEnrichment(ctx context.Context, songID uint64) error
+	// ImportSongs saves the songs in one transaction, songs which are in the library already are not saved.
+	ImportSongs(ctx context.Context, songs []entities.Song) ([]entities.ImportedSong, error)
+	// ExportSongs calls fn for every song matching the filter in the order of IDs, an error of fn stops the export.
+	ExportSongs(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error
+	// GetDuplicateSongs returns sets of songs of a group whose normalized names have at least the similarity.
+	GetDuplicateSongs(ctx context.Context, similarity float64, offset int, limit int) ([]entities.DuplicateSongs, error)
+	// MergeSongs merges metadata of the duplicates into the song, moves them to the trash and returns the song.
+	MergeSongs(ctx context.Context, id uint64, duplicateIDs []uint64) (entities.Song, error)
+
+	SaveGroup(ctx context.Context, group entities.Group) (id uint64, err error)
+	GetGroup(ctx context.Context, id uint64) (entities.Group, error)
+	GetGroupList(ctx context.Context, filter entities.Group, offset int, limit int) ([]entities.Group, error)
+	RemoveGroup(ctx context.Context, id uint64) error
+	UpdateGroup(ctx context.Context, group entities.Group) error
+
+	SaveAlbum(ctx context.Context, album entities.Album) (id uint64, err error)
+	GetAlbum(ctx context.Context, id uint64) (entities.Album, error)
+	GetAlbumList(ctx context.Context, filter entities.Album, offset int, limit int) ([]entities.Album, error)
+	GetAlbumTracks(ctx context.Context, id uint64) ([]entities.Song, error)
+	RemoveAlbum(ctx context.Context, id uint64) error
+	UpdateAlbum(ctx context.Context, album entities.Album) error
+}
+
+// EnrichmentJobStorage keeps enrichment jobs, so pending work survives restarts.
+type EnrichmentJobStorage interface {
+	GetSong(ctx context.Context, id uint64) (entities.Song, error)
+	// ClaimEnrichmentJob takes a due job and hides it from other workers for the lease time.
+	ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (entities.EnrichmentJob, error)
+	// CompleteEnrichmentJob fills empty extra data fields of the song and removes its job.
+	CompleteEnrichmentJob(ctx context.Context, songID uint64, data entities.ExtraSongData) error
+	RescheduleEnrichmentJob(ctx context.Context, songID uint64, nextAttemptAt time.Time, lastError string) error
+	// FailEnrichmentJob marks the song as failed and removes its job.
+	FailEnrichmentJob(ctx context.Context, songID uint64, lastError string) error
+}
+
+// ExtraDataCacheStorage persists cached extra song data between restarts.
+type ExtraDataCacheStorage interface {
+	// GetCachedExtraData returns a not expired entry or a NotFound error.
+	GetCachedExtraData(ctx context.Context, key string) (entities.CachedExtraData, error)
+	SaveCachedExtraData(ctx context.Context, entry entities.CachedExtraData) error
+	// PurgeCachedExtraData removes entries which expired before the time and returns their number.
+	PurgeCachedExtraData(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TrashStorage keeps removed songs until they are purged.
+type TrashStorage interface {
+	// PurgeTrash removes songs which were moved to the trash before the time for good and returns their number.
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SongImportStorage saves imported songs in batches.
+type SongImportStorage interface {
+	// ImportSongs saves the songs in one transaction and returns their outcomes in the same order.
+	// A song of the same group and name as a song in the library or earlier in the batch is not saved.
+	ImportSongs(ctx context.Context, songs []entities.Song) ([]entities.ImportedSong, error)
+}
+
+// SongExportStorage streams songs of the library.
+type SongExportStorage interface {
+	// ExportSongs calls fn for every song matching the filter in the order of IDs, an error of fn stops the export.
+	ExportSongs(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error
 }
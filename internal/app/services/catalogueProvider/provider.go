@@ -0,0 +1,121 @@
+package catalogueProvider
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Record is an entry of a catalogue file.
+// A CSV file has a header with the same names as JSON keys, columns may go in any order.
+type Record struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"release_date"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// CatalogueProvider gives extra data from a local JSON or CSV file, which is read once at start.
+type CatalogueProvider struct {
+	records map[string]entities.ExtraSongData
+}
+
+// NewCatalogueProvider reads the catalogue file. Its format is chosen by the extension: ".json" or ".csv".
+func NewCatalogueProvider(path string) (*CatalogueProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open catalogue: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.NewDecoder(file).Decode(&records)
+	case ".csv":
+		records, err = readCSV(file)
+	default:
+		return nil, fmt.Errorf("unknown catalogue format `%v`, expected .json or .csv", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalogue: %w", err)
+	}
+	return NewCatalogueProviderFromRecords(records), nil
+}
+
+// NewCatalogueProviderFromRecords returns a provider of the records. Later records replace earlier ones of the same song.
+func NewCatalogueProviderFromRecords(records []Record) *CatalogueProvider {
+	p := &CatalogueProvider{records: make(map[string]entities.ExtraSongData, len(records))}
+	for _, record := range records {
+		p.records[entities.SongKey(record.Group, record.Song)] = entities.ExtraSongData{
+			ReleaseDate: record.ReleaseDate,
+			Text:        record.Text,
+			Link:        record.Link,
+		}
+	}
+	return p
+}
+
+// readCSV reads records from a CSV file with a header.
+func readCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["group"]; !ok {
+		return nil, fmt.Errorf("column `group` is missing")
+	}
+	if _, ok := columns["song"]; !ok {
+		return nil, fmt.Errorf("column `song` is missing")
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+		column := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return row[i]
+		}
+		records = append(records, Record{
+			Group:       column("group"),
+			Song:        column("song"),
+			ReleaseDate: column("release_date"),
+			Text:        column("text"),
+			Link:        column("link"),
+		})
+	}
+}
+
+// GetExtraSongData returns the song`s record or ErrExtraDataNotFound.
+func (p *CatalogueProvider) GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
+	data, ok := p.records[entities.SongKey(song.Group, song.Song)]
+	if !ok {
+		return entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound
+	}
+	return data, nil
+}
@@ -0,0 +1,74 @@
+package catalogueProvider
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCatalogueProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		song         entities.Song
+		expectedData entities.ExtraSongData
+		expectedErr  error
+		wantOpenErr  bool
+	}{
+		{
+			name:         "JSON",
+			file:         "catalogue.json",
+			content:      `[{"group":"Muse","song":"Uprising","release_date":"16.07.2009","link":"https://example.com"}]`,
+			song:         entities.Song{Group: "muse ", Song: "UPRISING"},
+			expectedData: entities.ExtraSongData{ReleaseDate: "16.07.2009", Link: "https://example.com"},
+		},
+		{
+			name:         "CSV",
+			file:         "catalogue.csv",
+			content:      "song,group,text\nUprising,Muse,\"line 1\nline 2\"\n",
+			song:         entities.Song{Group: "Muse", Song: "Uprising"},
+			expectedData: entities.ExtraSongData{Text: "line 1\nline 2"},
+		},
+		{
+			name:        "Unknown song",
+			file:        "catalogue.csv",
+			content:     "group,song\nMuse,Uprising\n",
+			song:        entities.Song{Group: "Muse", Song: "Hysteria"},
+			expectedErr: requiredinterfaces.ErrExtraDataNotFound,
+		},
+		{
+			name:        "CSV without song column",
+			file:        "catalogue.csv",
+			content:     "group,text\nMuse,some text\n",
+			wantOpenErr: true,
+		},
+		{
+			name:        "Unknown format",
+			file:        "catalogue.xml",
+			content:     "<songs/>",
+			wantOpenErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			p, err := NewCatalogueProvider(path)
+			if tt.wantOpenErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			data, err := p.GetExtraSongData(context.Background(), tt.song)
+			assert.Equal(t, tt.expectedData, data)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
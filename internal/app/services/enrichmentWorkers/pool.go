@@ -0,0 +1,156 @@
+package enrichmentWorkers
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"math/rand"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/pkg/databases/dberrors"
+	"sync"
+	"time"
+)
+
+// Config of a Pool. Zero values are replaced by defaults.
+type Config struct {
+	Workers      int
+	MaxAttempts  int
+	PollInterval time.Duration
+	// Lease is how long a claimed job is hidden from other workers.
+	Lease time.Duration
+	// BaseBackoff is the delay after the first failed attempt, it doubles with every next one up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.Lease <= 0 {
+		c.Lease = time.Minute
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = 2 * time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 10 * time.Minute
+	}
+	return c
+}
+
+// Pool is a set of workers which take enrichment jobs from a storage and ask an ExtraDataProvider for song data.
+type Pool struct {
+	storage  requiredinterfaces.EnrichmentJobStorage
+	provider requiredinterfaces.ExtraDataProvider
+	logger   *zap.SugaredLogger
+	conf     Config
+}
+
+func NewPool(storage requiredinterfaces.EnrichmentJobStorage, provider requiredinterfaces.ExtraDataProvider, logger *zap.SugaredLogger, conf Config) *Pool {
+	return &Pool{
+		storage:  storage,
+		provider: provider,
+		logger:   logger,
+		conf:     conf.withDefaults(),
+	}
+}
+
+// auditActor is recorded as the author of changes made by workers.
+const auditActor = "enrichment"
+
+// Run starts workers and blocks until ctx is done and all workers have finished.
+func (p *Pool) Run(ctx context.Context) {
+	ctx = entities.WithAuditInfo(ctx, entities.AuditInfo{Actor: auditActor})
+	wg := sync.WaitGroup{}
+	for i := 0; i < p.conf.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work processes jobs while there are due ones and waits for PollInterval otherwise.
+func (p *Pool) work(ctx context.Context) {
+	for {
+		job, err := p.storage.ClaimEnrichmentJob(ctx, p.conf.Lease)
+		if err == nil {
+			p.process(ctx, job)
+			continue
+		}
+		if !errors.Is(err, dberrors.NewNotFoundErr()) && ctx.Err() == nil {
+			p.logger.Errorf("failed to claim enrichment job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.conf.PollInterval):
+		}
+	}
+}
+
+// process asks the provider for data of the job`s song and saves the result.
+// Failed attempts are retried with an exponential backoff until MaxAttempts is reached,
+// songs unknown to the provider are failed at once. Incomplete data is retried too,
+// the last attempt saves what was found.
+func (p *Pool) process(ctx context.Context, job entities.EnrichmentJob) {
+	song, err := p.storage.GetSong(ctx, job.SongID)
+	if errors.Is(err, dberrors.NewNotFoundErr()) {
+		//the song was removed, its job is removed with it
+		return
+	} else if err != nil {
+		p.logger.Errorf("failed to get song %d for enrichment: %v", job.SongID, err)
+		return
+	}
+
+	data, err := p.provider.GetExtraSongData(ctx, song)
+	if errors.Is(err, requiredinterfaces.ErrExtraDataIncomplete) && job.Attempts >= p.conf.MaxAttempts {
+		p.logger.Warnf("saving incomplete extra data of song %d after %d attempts: %v", song.ID, job.Attempts, err)
+		err = nil
+	}
+	if err == nil {
+		err = p.storage.CompleteEnrichmentJob(ctx, song.ID, data)
+		if err != nil {
+			p.logger.Errorf("failed to save extra data of song %d: %v", song.ID, err)
+		}
+		return
+	}
+
+	if errors.Is(err, requiredinterfaces.ErrExtraDataNotFound) || job.Attempts >= p.conf.MaxAttempts {
+		p.logger.Warnf("giving up enrichment of song %d after %d attempts: %v", song.ID, job.Attempts, err)
+		err = p.storage.FailEnrichmentJob(ctx, song.ID, err.Error())
+		if err != nil {
+			p.logger.Errorf("failed to mark enrichment of song %d as failed: %v", song.ID, err)
+		}
+		return
+	}
+
+	p.logger.Debugf("enrichment attempt %d of song %d failed: %v", job.Attempts, song.ID, err)
+	err = p.storage.RescheduleEnrichmentJob(ctx, song.ID, time.Now().Add(p.backoff(job.Attempts)), err.Error())
+	if err != nil {
+		p.logger.Errorf("failed to reschedule enrichment of song %d: %v", song.ID, err)
+	}
+}
+
+// backoff returns a delay before the next attempt: BaseBackoff doubled per attempt, capped by MaxBackoff, with up to 20% of jitter.
+func (p *Pool) backoff(attempts int) time.Duration {
+	delay := p.conf.BaseBackoff
+	for i := 1; i < attempts && delay < p.conf.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.conf.MaxBackoff {
+		delay = p.conf.MaxBackoff
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
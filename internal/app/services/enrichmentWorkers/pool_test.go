@@ -0,0 +1,197 @@
+package enrichmentWorkers
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"testing"
+	"time"
+)
+
+func TestPool_process(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	song := entities.Song{ID: 10, Song: "some song", Group: "some group", EnrichmentStatus: entities.EnrichmentPending}
+
+	type fields struct {
+		storage  func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage
+		provider func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		job    entities.EnrichmentJob
+	}{
+		{
+			name: "Ok",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(song, nil)
+					storage.EXPECT().CompleteEnrichmentJob(gomock.Any(), uint64(10), entities.ExtraSongData{
+						ReleaseDate: "10.10.2010",
+						Text:        "some text",
+						Link:        "https://example.com/somesong",
+						Sources:     map[string]string{entities.FieldText: "api"},
+					}).Return(nil)
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{
+						ReleaseDate: "10.10.2010",
+						Text:        "some text",
+						Link:        "https://example.com/somesong",
+						Sources:     map[string]string{entities.FieldText: "api"},
+					}, nil)
+					return provider
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 1},
+		},
+		{
+			name: "Provider error, retry",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(song, nil)
+					storage.EXPECT().RescheduleEnrichmentJob(gomock.Any(), uint64(10), gomock.Any(), "test error").Return(nil)
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
+					return provider
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 2},
+		},
+		{
+			name: "Provider error, last attempt",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(song, nil)
+					storage.EXPECT().FailEnrichmentJob(gomock.Any(), uint64(10), "test error").Return(nil)
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
+					return provider
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 3},
+		},
+		{
+			name: "Incomplete data, retry",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(song, nil)
+					storage.EXPECT().RescheduleEnrichmentJob(gomock.Any(), uint64(10), gomock.Any(), gomock.Any()).Return(nil)
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{
+						Text:    "some text",
+						Sources: map[string]string{entities.FieldText: "lyrics"},
+					}, fmt.Errorf("%w: test error", requiredinterfaces.ErrExtraDataIncomplete))
+					return provider
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 2},
+		},
+		{
+			name: "Incomplete data, last attempt",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(song, nil)
+					storage.EXPECT().CompleteEnrichmentJob(gomock.Any(), uint64(10), entities.ExtraSongData{
+						Text:    "some text",
+						Sources: map[string]string{entities.FieldText: "lyrics"},
+					}).Return(nil)
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{
+						Text:    "some text",
+						Sources: map[string]string{entities.FieldText: "lyrics"},
+					}, fmt.Errorf("%w: test error", requiredinterfaces.ErrExtraDataIncomplete))
+					return provider
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 3},
+		},
+		{
+			name: "Song is unknown to provider",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(song, nil)
+					storage.EXPECT().FailEnrichmentJob(gomock.Any(), uint64(10), gomock.Any()).Return(nil)
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound)
+					return provider
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 1},
+		},
+		{
+			name: "Song was removed",
+			fields: fields{
+				storage: func(c *gomock.Controller) requiredinterfaces.EnrichmentJobStorage {
+					storage := mocks.NewMockEnrichmentJobStorage(c)
+					storage.EXPECT().GetSong(gomock.Any(), uint64(10)).Return(entities.Song{}, dberrors.NewNotFoundErr())
+					return storage
+				},
+				provider: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					return mocks.NewMockExtraDataProvider(c)
+				},
+			},
+			job: entities.EnrichmentJob{SongID: 10, Attempts: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			p := NewPool(tt.fields.storage(c), tt.fields.provider(c), sugar, Config{MaxAttempts: 3})
+			p.process(context.Background(), tt.job)
+		})
+	}
+}
+
+func TestPool_backoff(t *testing.T) {
+	p := NewPool(nil, nil, nil, Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	tests := []struct {
+		attempts int
+		min      time.Duration
+	}{
+		{attempts: 1, min: time.Second},
+		{attempts: 2, min: 2 * time.Second},
+		{attempts: 3, min: 4 * time.Second},
+		{attempts: 10, min: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempts), func(t *testing.T) {
+			delay := p.backoff(tt.attempts)
+			assert.GreaterOrEqual(t, delay, tt.min)
+			assert.LessOrEqual(t, delay, tt.min+tt.min/5)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package extraDataAPIProvider
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without asking the API while it is considered down.
+var ErrCircuitOpen = errors.New("extra data API circuit is open")
+
+// breaker is a circuit breaker. After `threshold` failures in a row it opens and rejects calls for `cooldown`,
+// then lets a single trial call through: its success closes the circuit, its failure opens it again.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow tells whether a call may be done now.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return ErrCircuitOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// success closes the circuit.
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+// release ends a call whose outcome is unknown, a trial call may be done again.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// failure counts a failed call and opens the circuit when the threshold is reached.
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
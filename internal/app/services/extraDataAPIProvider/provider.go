@@ -1,59 +1,199 @@
 package extraDataAPIProvider
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const songDataPath = "/info"
 
+// Config of an ExtraDataAPIProvider. Zero values are replaced by defaults.
+type Config struct {
+	Client *http.Client
+	// Timeout limits a single request to the API.
+	Timeout time.Duration
+	// MaxRetries is how many times a request is repeated after a 5xx status or a timeout.
+	MaxRetries int
+	// BaseBackoff is the delay before the first retry, it doubles with every next one up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold failures in a row stop requests to the API for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = 200 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 5 * time.Second
+	}
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = 5
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = 30 * time.Second
+	}
+	return c
+}
+
 // ExtraDataAPIProvider uses API to get extra data.
 type ExtraDataAPIProvider struct {
 	address string
+	conf    Config
+	breaker *breaker
 }
 
-func NewExtraDataAPIProvider(address string) *ExtraDataAPIProvider {
+func NewExtraDataAPIProvider(address string, conf Config) *ExtraDataAPIProvider {
+	conf = conf.withDefaults()
 	return &ExtraDataAPIProvider{
 		address: address,
+		conf:    conf,
+		breaker: newBreaker(conf.BreakerThreshold, conf.BreakerCooldown),
 	}
 }
 
-type extraSongData struct {
-	ReleaseDate string `json:"release_date"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+// retryableError is a failure which may pass if the request is repeated.
+type retryableError struct {
+	err error
 }
 
-// GetExtraSongData sends a GET request to and ExtraDataAPIProvider.address and returns extra data.
-func (p *ExtraDataAPIProvider) GetExtraSongData(song entities.Song) (releaseDate, text, link string, err error) {
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
 
-	//ask api
+func (e retryableError) Unwrap() error {
+	return e.err
+}
+
+// clientError is a 4xx answer of the API, it is up and has refused the request.
+type clientError struct {
+	err error
+}
+
+func (e clientError) Error() string {
+	return e.err.Error()
+}
+
+func (e clientError) Unwrap() error {
+	return e.err
+}
+
+// GetExtraSongData sends a GET request to and ExtraDataAPIProvider.address and returns extra data.
+// 5xx statuses and timeouts are retried with a jittered exponential backoff, other failures are returned at once.
+func (p *ExtraDataAPIProvider) GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
 	query := url.Values{}
 	query.Add("group", song.Group)
 	query.Add("song", song.Song)
-	res, err := http.Get(p.address + songDataPath + "?" + query.Encode())
+	address := p.address + songDataPath + "?" + query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		err := p.breaker.allow()
+		if err != nil {
+			return entities.ExtraSongData{}, err
+		}
+
+		data, err := p.get(ctx, address)
+		var client clientError
+		switch {
+		case err == nil || errors.As(err, &client):
+			//the API has answered, even if it did not like the request
+			p.breaker.success()
+		case ctx.Err() != nil:
+			//the caller has given up, the API may have not answered yet
+			p.breaker.release()
+		default:
+			p.breaker.failure()
+		}
+		if err == nil {
+			return data, nil
+		}
+		var retryable retryableError
+		if !errors.As(err, &retryable) || attempt >= p.conf.MaxRetries || ctx.Err() != nil {
+			return entities.ExtraSongData{}, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return entities.ExtraSongData{}, ctx.Err()
+		case <-time.After(p.backoff(attempt)):
+		}
+	}
+}
+
+// get sends a single request to the API.
+func (p *ExtraDataAPIProvider) get(ctx context.Context, address string) (entities.ExtraSongData, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.conf.Timeout)
+	defer cancel()
+
+	//ask api
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return entities.ExtraSongData{}, fmt.Errorf("error creating request: %w", err)
+	}
+	res, err := p.conf.Client.Do(req)
 	if err != nil {
-		return "", "", "", fmt.Errorf("error sending request: %w", err)
+		if errors.Is(err, context.Canceled) {
+			//the caller does not need the data anymore
+			return entities.ExtraSongData{}, fmt.Errorf("error sending request: %w", err)
+		}
+		//timeouts and connection errors
+		return entities.ExtraSongData{}, retryableError{fmt.Errorf("error sending request: %w", err)}
 	}
 	defer res.Body.Close()
+	if res.StatusCode >= http.StatusInternalServerError {
+		return entities.ExtraSongData{}, retryableError{fmt.Errorf("error sending request: status code %d", res.StatusCode)}
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return entities.ExtraSongData{}, clientError{fmt.Errorf("error sending request: %w", requiredinterfaces.ErrExtraDataNotFound)}
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return entities.ExtraSongData{}, clientError{fmt.Errorf("error sending request: status code %d", res.StatusCode)}
+	}
 	if res.StatusCode != http.StatusOK {
-		return "", "", "", fmt.Errorf("error sending request: status code %d", res.StatusCode)
+		return entities.ExtraSongData{}, fmt.Errorf("error sending request: status code %d", res.StatusCode)
 	}
 
 	//parse data
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return "", "", "", fmt.Errorf("error reading response body: %w", err)
+		return entities.ExtraSongData{}, retryableError{fmt.Errorf("error reading response body: %w", err)}
 	}
-	data := extraSongData{}
+	data := entities.ExtraSongData{}
 	err = json.Unmarshal(bodyBytes, &data)
 	if err != nil {
-		return "", "", "", fmt.Errorf("error unmarshalling response body: %w", err)
+		return entities.ExtraSongData{}, fmt.Errorf("error unmarshalling response body: %w", err)
 	}
+	return data, nil
+}
 
-	return data.ReleaseDate, data.Text, data.Link, nil
+// backoff returns a delay before the retry: BaseBackoff doubled per attempt, capped by MaxBackoff, with a full jitter.
+func (p *ExtraDataAPIProvider) backoff(attempt int) time.Duration {
+	delay := p.conf.BaseBackoff
+	for i := 0; i < attempt && delay < p.conf.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.conf.MaxBackoff {
+		delay = p.conf.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
 }
@@ -0,0 +1,182 @@
+package extraDataAPIProvider
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtraDataAPIProvider_GetExtraSongData(t *testing.T) {
+	song := entities.Song{Song: "some song", Group: "some group"}
+
+	tests := []struct {
+		name string
+		// statuses are answered one by one, the last one is repeated
+		statuses        []int
+		conf            Config
+		expectedCalls   int32
+		expectedText    string
+		expectedErr     bool
+		expectedErrorIs error
+	}{
+		{
+			name:          "Ok",
+			statuses:      []int{http.StatusOK},
+			expectedCalls: 1,
+			expectedText:  "some text",
+		},
+		{
+			name:          "Retry after 5xx",
+			statuses:      []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			conf:          Config{MaxRetries: 2},
+			expectedCalls: 3,
+			expectedText:  "some text",
+		},
+		{
+			name:          "Retries are over",
+			statuses:      []int{http.StatusInternalServerError},
+			conf:          Config{MaxRetries: 2},
+			expectedCalls: 3,
+			expectedErr:   true,
+		},
+		{
+			name:          "4xx is not retried",
+			statuses:      []int{http.StatusBadRequest},
+			conf:          Config{MaxRetries: 2},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			name:            "Song is unknown",
+			statuses:        []int{http.StatusNotFound},
+			conf:            Config{MaxRetries: 2},
+			expectedCalls:   1,
+			expectedErr:     true,
+			expectedErrorIs: requiredinterfaces.ErrExtraDataNotFound,
+		},
+		{
+			name:          "Retries are off",
+			statuses:      []int{http.StatusInternalServerError},
+			conf:          Config{MaxRetries: 0},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			name:            "Circuit opens",
+			statuses:        []int{http.StatusInternalServerError},
+			conf:            Config{MaxRetries: 5, BreakerThreshold: 2},
+			expectedCalls:   2,
+			expectedErr:     true,
+			expectedErrorIs: ErrCircuitOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&calls, 1))
+				status := tt.statuses[len(tt.statuses)-1]
+				if n <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				assert.Equal(t, "some song", r.URL.Query().Get("song"))
+				w.WriteHeader(status)
+				w.Write([]byte(`{"release_date":"10.10.2010","text":"some text","link":"https://example.com"}`))
+			}))
+			defer server.Close()
+
+			tt.conf.BaseBackoff = time.Millisecond
+			p := NewExtraDataAPIProvider(server.URL, tt.conf)
+			data, err := p.GetExtraSongData(context.Background(), song)
+
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&calls))
+			assert.Equal(t, tt.expectedText, data.Text)
+			assert.Equal(t, tt.expectedErr, err != nil)
+			if tt.expectedErrorIs != nil {
+				assert.ErrorIs(t, err, tt.expectedErrorIs)
+			}
+		})
+	}
+}
+
+func TestExtraDataAPIProvider_GetExtraSongData_cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	p := NewExtraDataAPIProvider(server.URL, Config{MaxRetries: 3})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := p.GetExtraSongData(ctx, entities.Song{})
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	//cancellation is not a failure of the API
+	assert.NoError(t, p.breaker.allow())
+	assert.Equal(t, 0, p.breaker.failures)
+}
+
+func TestExtraDataAPIProvider_GetExtraSongData_cancelTrial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	p := NewExtraDataAPIProvider(server.URL, Config{BreakerThreshold: 1, BreakerCooldown: 10 * time.Millisecond})
+	p.breaker.failure()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := p.GetExtraSongData(ctx, entities.Song{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	//a cancelled trial call neither closes the circuit nor blocks the next trial
+	assert.Equal(t, 1, p.breaker.failures)
+	assert.NoError(t, p.breaker.allow())
+	assert.ErrorIs(t, p.breaker.allow(), ErrCircuitOpen)
+}
+
+func TestExtraDataAPIProvider_GetExtraSongData_timeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	p := NewExtraDataAPIProvider(server.URL, Config{Timeout: 10 * time.Millisecond, MaxRetries: 1, BaseBackoff: time.Millisecond})
+	_, err := p.GetExtraSongData(context.Background(), entities.Song{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func Test_breaker(t *testing.T) {
+	b := newBreaker(2, 20*time.Millisecond)
+
+	b.failure()
+	assert.NoError(t, b.allow())
+	b.failure()
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	//a single trial call after the cooldown
+	time.Sleep(30 * time.Millisecond)
+	assert.NoError(t, b.allow())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+	b.failure()
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.NoError(t, b.allow())
+	b.success()
+	assert.NoError(t, b.allow())
+	assert.NoError(t, b.allow())
+}
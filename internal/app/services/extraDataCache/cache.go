@@ -0,0 +1,171 @@
+package extraDataCache
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"go.uber.org/zap"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/pkg/databases/dberrors"
+	"time"
+)
+
+// metrics are published on /debug/vars as "extra_data_cache".
+var metrics = expvar.NewMap("extra_data_cache")
+
+const (
+	metricMemoryHits     = "memory_hits"
+	metricPersistentHits = "persistent_hits"
+	metricNegativeHits   = "negative_hits"
+	metricMisses         = "misses"
+)
+
+// Config of a Cache. Zero values are replaced by defaults.
+type Config struct {
+	// Size is how many entries are kept in memory.
+	Size int
+	TTL  time.Duration
+	// NegativeTTL is how long a song unknown to the provider is not asked again.
+	NegativeTTL time.Duration
+	// PurgeInterval is how often expired entries are removed from the persistent storage.
+	PurgeInterval time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Size <= 0 {
+		c.Size = 1000
+	}
+	if c.TTL <= 0 {
+		c.TTL = 24 * time.Hour
+	}
+	if c.NegativeTTL <= 0 {
+		c.NegativeTTL = time.Hour
+	}
+	if c.PurgeInterval <= 0 {
+		c.PurgeInterval = time.Hour
+	}
+	return c
+}
+
+// Cache is an ExtraDataProvider which remembers answers of another one.
+// Entries are looked up in memory first and then in an optional persistent storage.
+type Cache struct {
+	next       requiredinterfaces.ExtraDataProvider
+	persistent requiredinterfaces.ExtraDataCacheStorage
+	logger     *zap.SugaredLogger
+	conf       Config
+	memory     *lru
+}
+
+// NewCache wraps the provider. Persistent storage may be nil, then entries are kept in memory only.
+func NewCache(next requiredinterfaces.ExtraDataProvider, persistent requiredinterfaces.ExtraDataCacheStorage, logger *zap.SugaredLogger, conf Config) *Cache {
+	conf = conf.withDefaults()
+	return &Cache{
+		next:       next,
+		persistent: persistent,
+		logger:     logger,
+		conf:       conf,
+		memory:     newLRU(conf.Size),
+	}
+}
+
+// GetExtraSongData returns cached data or asks the wrapped provider.
+// Successful answers and ErrExtraDataNotFound are cached, other errors are not.
+func (c *Cache) GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
+	key := entities.SongKey(song.Group, song.Song)
+	now := time.Now()
+
+	//memory
+	entry, ok := c.memory.get(key, now)
+	if ok {
+		metrics.Add(metricMemoryHits, 1)
+		return answer(entry)
+	}
+
+	//persistent storage
+	if c.persistent != nil {
+		entry, err := c.persistent.GetCachedExtraData(ctx, key)
+		if err == nil && now.Before(entry.ExpiresAt) {
+			metrics.Add(metricPersistentHits, 1)
+			c.memory.put(entry)
+			return answer(entry)
+		} else if err != nil && !errors.Is(err, dberrors.NewNotFoundErr()) {
+			c.logger.Warnf("failed to read extra data cache: %v", err)
+		}
+	}
+
+	//provider
+	metrics.Add(metricMisses, 1)
+	data, err := c.next.GetExtraSongData(ctx, song)
+	if errors.Is(err, requiredinterfaces.ErrExtraDataNotFound) {
+		entry = entities.CachedExtraData{Key: key, NotFound: true, ExpiresAt: now.Add(c.conf.NegativeTTL)}
+	} else if err == nil {
+		entry = entities.CachedExtraData{
+			Key:         key,
+			ReleaseDate: data.ReleaseDate,
+			Text:        data.Text,
+			Link:        data.Link,
+			Sources:     data.Sources,
+			ExpiresAt:   now.Add(c.conf.TTL),
+		}
+	} else {
+		return entities.ExtraSongData{}, err
+	}
+
+	c.memory.put(entry)
+	if c.persistent != nil {
+		saveErr := c.persistent.SaveCachedExtraData(ctx, entry)
+		if saveErr != nil {
+			c.logger.Warnf("failed to save extra data cache: %v", saveErr)
+		}
+	}
+	return data, err
+}
+
+// Run removes expired entries from the persistent storage at once and then every PurgeInterval,
+// it blocks until ctx is done. It returns at once if there is no persistent storage.
+func (c *Cache) Run(ctx context.Context) {
+	if c.persistent == nil {
+		return
+	}
+	ticker := time.NewTicker(c.conf.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		c.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge removes entries which have expired from the persistent storage.
+func (c *Cache) purge(ctx context.Context) {
+	purged, err := c.persistent.PurgeCachedExtraData(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			c.logger.Errorf("failed to purge extra data cache: %v", err)
+		}
+		return
+	}
+	if purged > 0 {
+		c.logger.Infof("purged %d expired extra data cache entries", purged)
+	}
+}
+
+// answer turns a cache entry into GetExtraSongData results.
+func answer(entry entities.CachedExtraData) (entities.ExtraSongData, error) {
+	if entry.NotFound {
+		metrics.Add(metricNegativeHits, 1)
+		return entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound
+	}
+	return entities.ExtraSongData{
+		ReleaseDate: entry.ReleaseDate,
+		Text:        entry.Text,
+		Link:        entry.Link,
+		Sources:     entry.Sources,
+	}, nil
+}
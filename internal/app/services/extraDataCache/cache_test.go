@@ -0,0 +1,212 @@
+package extraDataCache
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"testing"
+	"time"
+)
+
+func TestCache_GetExtraSongData(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	song := entities.Song{Song: "some song", Group: "some group"}
+	key := "some group\nsome song"
+
+	type fields struct {
+		next       func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider
+		persistent func(c *gomock.Controller) requiredinterfaces.ExtraDataCacheStorage
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		// songs are asked one by one
+		songs        []entities.Song
+		expectedText string
+		expectedErr  error
+	}{
+		{
+			name: "Second call is served from memory",
+			fields: fields{
+				next: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{ReleaseDate: "10.10.2010", Text: "some text", Link: "https://example.com"}, nil).Times(1)
+					return provider
+				},
+			},
+			songs:        []entities.Song{song, {Song: "Some  Song", Group: "SOME GROUP "}},
+			expectedText: "some text",
+		},
+		{
+			name: "Not found is cached",
+			fields: fields{
+				next: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, fmt.Errorf("test: %w", requiredinterfaces.ErrExtraDataNotFound)).Times(1)
+					return provider
+				},
+			},
+			songs:       []entities.Song{song, song},
+			expectedErr: requiredinterfaces.ErrExtraDataNotFound,
+		},
+		{
+			name: "Other errors are not cached",
+			fields: fields{
+				next: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, fmt.Errorf("test error")).Times(1)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{ReleaseDate: "10.10.2010", Text: "some text", Link: "https://example.com"}, nil).Times(1)
+					return provider
+				},
+			},
+			songs:        []entities.Song{song, song},
+			expectedText: "some text",
+		},
+		{
+			name: "Persistent hit",
+			fields: fields{
+				next: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					return mocks.NewMockExtraDataProvider(c)
+				},
+				persistent: func(c *gomock.Controller) requiredinterfaces.ExtraDataCacheStorage {
+					storage := mocks.NewMockExtraDataCacheStorage(c)
+					storage.EXPECT().GetCachedExtraData(gomock.Any(), key).Return(entities.CachedExtraData{
+						Key:       key,
+						Text:      "stored text",
+						ExpiresAt: time.Now().Add(time.Hour),
+					}, nil).Times(1)
+					return storage
+				},
+			},
+			songs:        []entities.Song{song, song},
+			expectedText: "stored text",
+		},
+		{
+			name: "Persistent miss is saved",
+			fields: fields{
+				next: func(c *gomock.Controller) requiredinterfaces.ExtraDataProvider {
+					provider := mocks.NewMockExtraDataProvider(c)
+					provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{ReleaseDate: "10.10.2010", Text: "some text", Link: "https://example.com"}, nil)
+					return provider
+				},
+				persistent: func(c *gomock.Controller) requiredinterfaces.ExtraDataCacheStorage {
+					storage := mocks.NewMockExtraDataCacheStorage(c)
+					storage.EXPECT().GetCachedExtraData(gomock.Any(), key).Return(entities.CachedExtraData{}, dberrors.NewNotFoundErr())
+					storage.EXPECT().SaveCachedExtraData(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry entities.CachedExtraData) error {
+						assert.Equal(t, key, entry.Key)
+						assert.Equal(t, "some text", entry.Text)
+						assert.False(t, entry.NotFound)
+						return nil
+					})
+					return storage
+				},
+			},
+			songs:        []entities.Song{song},
+			expectedText: "some text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			var persistent requiredinterfaces.ExtraDataCacheStorage
+			if tt.fields.persistent != nil {
+				persistent = tt.fields.persistent(c)
+			}
+			cache := NewCache(tt.fields.next(c), persistent, sugar, Config{})
+
+			var data entities.ExtraSongData
+			var err error
+			for _, s := range tt.songs {
+				data, err = cache.GetExtraSongData(context.Background(), s)
+			}
+			assert.Equal(t, tt.expectedText, data.Text)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestCache_Run(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := mocks.NewMockExtraDataCacheStorage(c)
+	purged := make(chan struct{}, 2)
+	storage.EXPECT().PurgeCachedExtraData(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+		select {
+		case purged <- struct{}{}:
+		default:
+		}
+		return 0, fmt.Errorf("test error")
+	}).MinTimes(2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewCache(mocks.NewMockExtraDataProvider(c), storage, sugar, Config{PurgeInterval: 10 * time.Millisecond}).Run(ctx)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-purged:
+		case <-time.After(time.Second):
+			t.Fatal("extra data cache was not purged")
+		}
+	}
+	cancel()
+	<-done
+
+	//without persistent storage there is nothing to purge
+	NewCache(mocks.NewMockExtraDataProvider(c), nil, sugar, Config{}).Run(context.Background())
+}
+
+func TestCache_expiration(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	song := entities.Song{Song: "some song", Group: "some group"}
+	provider := mocks.NewMockExtraDataProvider(c)
+	provider.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{Text: "some text"}, nil).Times(2)
+
+	cache := NewCache(provider, nil, zaptest.NewLogger(t).Sugar(), Config{TTL: 10 * time.Millisecond})
+	cache.GetExtraSongData(context.Background(), song)
+	time.Sleep(20 * time.Millisecond)
+	cache.GetExtraSongData(context.Background(), song)
+}
+
+func Test_lru(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	c := newLRU(2)
+
+	c.put(entities.CachedExtraData{Key: "a", ExpiresAt: later})
+	c.put(entities.CachedExtraData{Key: "b", ExpiresAt: later})
+	_, ok := c.get("a", now)
+	assert.True(t, ok)
+
+	//"b" is the least recently used one
+	c.put(entities.CachedExtraData{Key: "c", ExpiresAt: later})
+	_, ok = c.get("b", now)
+	assert.False(t, ok)
+	_, ok = c.get("a", now)
+	assert.True(t, ok)
+	_, ok = c.get("c", now)
+	assert.True(t, ok)
+
+	//expired entries are dropped
+	_, ok = c.get("a", later)
+	assert.False(t, ok)
+}
@@ -0,0 +1,61 @@
+package extraDataCache
+
+import (
+	"container/list"
+	"musiclib/internal/app/entities"
+	"sync"
+	"time"
+)
+
+// lru is an in-memory cache which drops the least recently used entry when it is full.
+type lru struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// get returns a not expired entry.
+func (c *lru) get(key string, now time.Time) (entities.CachedExtraData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return entities.CachedExtraData{}, false
+	}
+	entry := elem.Value.(entities.CachedExtraData)
+	if !now.Before(entry.ExpiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return entities.CachedExtraData{}, false
+	}
+	c.order.MoveToFront(elem)
+	return entry, true
+}
+
+func (c *lru) put(entry entities.CachedExtraData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.Key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(entities.CachedExtraData).Key)
+	}
+}
@@ -0,0 +1,192 @@
+package extraDataComposite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"slices"
+	"sync"
+)
+
+// Source is a named ExtraDataProvider. The name is recorded in song sources.
+type Source struct {
+	Name     string
+	Provider requiredinterfaces.ExtraDataProvider
+	// Fields the source can give, all of entities.ExtraDataFields if empty.
+	// A source is asked and waited for only for its fields.
+	Fields []string
+}
+
+// gives tells whether the source can give the field.
+func (s Source) gives(field string) bool {
+	return len(s.Fields) == 0 || slices.Contains(s.Fields, field)
+}
+
+type Config struct {
+	// Precedence lists source names per field, the first source with a value wins.
+	// Sources which are not listed but give the field follow in their own order.
+	Precedence map[string][]string
+	// Parallel asks all sources at once. Otherwise sources are asked one by one
+	// until every field has a value from the most preferred source which can still give it.
+	Parallel bool
+}
+
+// Composite is an ExtraDataProvider which merges answers of several sources field by field.
+type Composite struct {
+	sources    []Source
+	precedence map[string][]string
+	parallel   bool
+}
+
+// NewComposite checks the config and returns a new Composite. Sources are given in priority order.
+func NewComposite(sources []Source, conf Config) (*Composite, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no extra data sources")
+	}
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if slices.Contains(names, source.Name) {
+			return nil, fmt.Errorf("extra data source `%v` is given twice", source.Name)
+		}
+		for _, field := range source.Fields {
+			if !slices.Contains(entities.ExtraDataFields, field) {
+				return nil, fmt.Errorf("extra data source `%v` gives unknown field `%v`", source.Name, field)
+			}
+		}
+		names = append(names, source.Name)
+	}
+
+	precedence := make(map[string][]string, len(entities.ExtraDataFields))
+	for field, order := range conf.Precedence {
+		if !slices.Contains(entities.ExtraDataFields, field) {
+			return nil, fmt.Errorf("unknown extra data field `%v`", field)
+		}
+		for _, name := range order {
+			i := slices.Index(names, name)
+			if i < 0 {
+				return nil, fmt.Errorf("precedence of `%v` uses unknown source `%v`", field, name)
+			}
+			if !sources[i].gives(field) {
+				return nil, fmt.Errorf("precedence of `%v` uses source `%v` which does not give it", field, name)
+			}
+		}
+	}
+	for _, field := range entities.ExtraDataFields {
+		order := slices.Clone(conf.Precedence[field])
+		for _, source := range sources {
+			if source.gives(field) && !slices.Contains(order, source.Name) {
+				order = append(order, source.Name)
+			}
+		}
+		precedence[field] = order
+	}
+
+	return &Composite{
+		sources:    sources,
+		precedence: precedence,
+		parallel:   conf.Parallel,
+	}, nil
+}
+
+// answer is a result of a single source.
+type answer struct {
+	data entities.ExtraSongData
+	err  error
+}
+
+// GetExtraSongData asks sources and merges their answers.
+// ErrExtraDataNotFound is returned when no source knows the song, other errors only when no field was found.
+// When a source preferred for a field has failed, the merged data is returned with ErrExtraDataIncomplete.
+func (c *Composite) GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
+	answers := make(map[string]answer, len(c.sources))
+
+	if c.parallel {
+		mu := sync.Mutex{}
+		wg := sync.WaitGroup{}
+		for _, source := range c.sources {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				data, err := source.Provider.GetExtraSongData(ctx, song)
+				mu.Lock()
+				answers[source.Name] = answer{data: data, err: err}
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+	} else {
+		for _, source := range c.sources {
+			data, err := source.Provider.GetExtraSongData(ctx, song)
+			answers[source.Name] = answer{data: data, err: err}
+			if c.resolved(answers) || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	return c.merge(answers)
+}
+
+// resolved tells whether asking the rest of sources can not change the result.
+func (c *Composite) resolved(answers map[string]answer) bool {
+	for _, field := range entities.ExtraDataFields {
+		for _, name := range c.precedence[field] {
+			a, asked := answers[name]
+			if !asked {
+				return false
+			}
+			if a.err == nil && a.data.Field(field) != "" {
+				break
+			}
+		}
+	}
+	return true
+}
+
+// merge takes every field from the most preferred source which has it.
+func (c *Composite) merge(answers map[string]answer) (entities.ExtraSongData, error) {
+	merged := entities.ExtraSongData{}
+	//sources which have failed before a field was found
+	failed := map[string]bool{}
+	for _, field := range entities.ExtraDataFields {
+		for _, name := range c.precedence[field] {
+			a, asked := answers[name]
+			if !asked {
+				continue
+			}
+			if a.err != nil && !errors.Is(a.err, requiredinterfaces.ErrExtraDataNotFound) {
+				failed[name] = true
+			}
+			if a.err != nil || a.data.Field(field) == "" {
+				continue
+			}
+			merged.SetField(field, a.data.Field(field))
+			if merged.Sources == nil {
+				merged.Sources = map[string]string{}
+			}
+			merged.Sources[field] = name
+			break
+		}
+	}
+
+	var errs []error
+	for _, source := range c.sources {
+		if failed[source.Name] {
+			errs = append(errs, fmt.Errorf("source `%v`: %w", source.Name, answers[source.Name].err))
+		}
+	}
+	if merged.Sources != nil {
+		if len(errs) > 0 {
+			return merged, fmt.Errorf("%w: %w", requiredinterfaces.ErrExtraDataIncomplete, errors.Join(errs...))
+		}
+		return merged, nil
+	}
+
+	//nothing was found
+	if len(errs) > 0 {
+		return entities.ExtraSongData{}, errors.Join(errs...)
+	}
+	return entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound
+}
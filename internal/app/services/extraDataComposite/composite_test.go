@@ -0,0 +1,201 @@
+package extraDataComposite
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"testing"
+)
+
+func TestComposite_GetExtraSongData(t *testing.T) {
+	song := entities.Song{Song: "some song", Group: "some group"}
+	apiData := entities.ExtraSongData{ReleaseDate: "10.10.2010", Text: "api text", Link: "https://example.com"}
+	lyricsData := entities.ExtraSongData{Text: "lyrics text"}
+	lyricsFields := []string{entities.FieldText}
+
+	// provider returns a mock which is asked once, or never if asked is false
+	provider := func(c *gomock.Controller, data entities.ExtraSongData, err error, asked bool) requiredinterfaces.ExtraDataProvider {
+		p := mocks.NewMockExtraDataProvider(c)
+		if asked {
+			p.EXPECT().GetExtraSongData(gomock.Any(), song).Return(data, err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name         string
+		sources      func(c *gomock.Controller) []Source
+		conf         Config
+		expectedData entities.ExtraSongData
+		expectedErr  error
+	}{
+		{
+			name: "First source has everything",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "api", Provider: provider(c, apiData, nil, true)},
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, lyricsData, nil, false)},
+				}
+			},
+			expectedData: entities.ExtraSongData{
+				ReleaseDate: "10.10.2010", Text: "api text", Link: "https://example.com",
+				Sources: map[string]string{"release_date": "api", "text": "api", "link": "api"},
+			},
+		},
+		{
+			name: "Field precedence",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "api", Provider: provider(c, apiData, nil, true)},
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, lyricsData, nil, true)},
+				}
+			},
+			conf: Config{Precedence: map[string][]string{"text": {"lyrics", "api"}}},
+			expectedData: entities.ExtraSongData{
+				ReleaseDate: "10.10.2010", Text: "lyrics text", Link: "https://example.com",
+				Sources: map[string]string{"release_date": "api", "text": "lyrics", "link": "api"},
+			},
+		},
+		{
+			name: "Preferred source fails",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "api", Provider: provider(c, apiData, nil, true)},
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, entities.ExtraSongData{}, fmt.Errorf("test error"), true)},
+				}
+			},
+			conf: Config{Precedence: map[string][]string{"text": {"lyrics"}}, Parallel: true},
+			expectedData: entities.ExtraSongData{
+				ReleaseDate: "10.10.2010", Text: "api text", Link: "https://example.com",
+				Sources: map[string]string{"release_date": "api", "text": "api", "link": "api"},
+			},
+			expectedErr: requiredinterfaces.ErrExtraDataIncomplete,
+		},
+		{
+			name: "Less preferred source fails",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, lyricsData, nil, true)},
+					{Name: "api", Provider: provider(c, entities.ExtraSongData{}, fmt.Errorf("test error"), true)},
+				}
+			},
+			expectedData: entities.ExtraSongData{
+				Text:    "lyrics text",
+				Sources: map[string]string{"text": "lyrics"},
+			},
+			expectedErr: requiredinterfaces.ErrExtraDataIncomplete,
+		},
+		{
+			name: "Failed source is not needed",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "api", Provider: provider(c, apiData, nil, true)},
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, entities.ExtraSongData{}, fmt.Errorf("test error"), true)},
+				}
+			},
+			conf: Config{Parallel: true},
+			expectedData: entities.ExtraSongData{
+				ReleaseDate: "10.10.2010", Text: "api text", Link: "https://example.com",
+				Sources: map[string]string{"release_date": "api", "text": "api", "link": "api"},
+			},
+		},
+		{
+			name: "Failed source does not give missing fields",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, entities.ExtraSongData{}, fmt.Errorf("test error"), true)},
+					{Name: "api", Provider: provider(c, apiData, nil, true)},
+				}
+			},
+			conf: Config{Precedence: map[string][]string{"text": {"api"}}},
+			expectedData: entities.ExtraSongData{
+				ReleaseDate: "10.10.2010", Text: "api text", Link: "https://example.com",
+				Sources: map[string]string{"release_date": "api", "text": "api", "link": "api"},
+			},
+		},
+		{
+			name: "Missing fields are asked from next sources",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "catalogue", Provider: provider(c, entities.ExtraSongData{ReleaseDate: "01.01.2001"}, nil, true)},
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, lyricsData, nil, true)},
+					{Name: "api", Provider: provider(c, apiData, nil, true)},
+				}
+			},
+			expectedData: entities.ExtraSongData{
+				ReleaseDate: "01.01.2001", Text: "lyrics text", Link: "https://example.com",
+				Sources: map[string]string{"release_date": "catalogue", "text": "lyrics", "link": "api"},
+			},
+		},
+		{
+			name: "Nobody knows the song",
+			sources: func(c *gomock.Controller) []Source {
+				return []Source{
+					{Name: "api", Provider: provider(c, entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound, true)},
+					{Name: "lyrics", Fields: lyricsFields, Provider: provider(c, entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound, true)},
+				}
+			},
+			expectedErr: requiredinterfaces.ErrExtraDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			composite, err := NewComposite(tt.sources(c), tt.conf)
+			assert.NoError(t, err)
+			data, err := composite.GetExtraSongData(context.Background(), song)
+			assert.Equal(t, tt.expectedData, data)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestComposite_errors(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	song := entities.Song{Song: "some song", Group: "some group"}
+	api := mocks.NewMockExtraDataProvider(c)
+	api.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, fmt.Errorf("test error"))
+	lyrics := mocks.NewMockExtraDataProvider(c)
+	lyrics.EXPECT().GetExtraSongData(gomock.Any(), song).Return(entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound)
+
+	composite, err := NewComposite([]Source{{Name: "api", Provider: api}, {Name: "lyrics", Provider: lyrics}}, Config{})
+	assert.NoError(t, err)
+	_, err = composite.GetExtraSongData(context.Background(), song)
+	assert.EqualError(t, err, "source `api`: test error")
+}
+
+func TestNewComposite(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []Source
+		conf    Config
+		wantErr bool
+	}{
+		{name: "Ok", sources: []Source{{Name: "api"}, {Name: "lyrics"}}, conf: Config{Precedence: map[string][]string{"text": {"lyrics"}}}},
+		{name: "No sources", wantErr: true},
+		{name: "Same name", sources: []Source{{Name: "api"}, {Name: "api"}}, wantErr: true},
+		{name: "Unknown field", sources: []Source{{Name: "api"}}, conf: Config{Precedence: map[string][]string{"cover": {"api"}}}, wantErr: true},
+		{name: "Unknown source", sources: []Source{{Name: "api"}}, conf: Config{Precedence: map[string][]string{"text": {"lyrics"}}}, wantErr: true},
+		{name: "Unknown field of a source", sources: []Source{{Name: "api", Fields: []string{"cover"}}}, wantErr: true},
+		{
+			name:    "Source does not give the field",
+			sources: []Source{{Name: "api"}, {Name: "lyrics", Fields: []string{"text"}}},
+			conf:    Config{Precedence: map[string][]string{"link": {"lyrics"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewComposite(tt.sources, tt.conf)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
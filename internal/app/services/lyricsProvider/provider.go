@@ -0,0 +1,78 @@
+package lyricsProvider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LyricsProvider asks a lyrics-only API compatible with lyrics.ovh (GET /v1/{artist}/{title}).
+// It gives the text of a song and nothing else.
+type LyricsProvider struct {
+	address string
+	client  *http.Client
+	timeout time.Duration
+}
+
+// NewLyricsProvider returns a new provider. A nil client is replaced by a default one.
+func NewLyricsProvider(address string, client *http.Client, timeout time.Duration) *LyricsProvider {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &LyricsProvider{
+		address: strings.TrimSuffix(address, "/"),
+		client:  client,
+		timeout: timeout,
+	}
+}
+
+// Fields are the extra data fields the provider gives.
+var Fields = []string{entities.FieldText}
+
+type lyricsAnswer struct {
+	Lyrics string `json:"lyrics"`
+}
+
+// GetExtraSongData returns the text of the song, lines are separated by "\n".
+func (p *LyricsProvider) GetExtraSongData(ctx context.Context, song entities.Song) (entities.ExtraSongData, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	//ask api
+	address := p.address + "/v1/" + url.PathEscape(song.Group) + "/" + url.PathEscape(song.Song)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return entities.ExtraSongData{}, fmt.Errorf("error creating request: %w", err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return entities.ExtraSongData{}, fmt.Errorf("error sending request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return entities.ExtraSongData{}, fmt.Errorf("error sending request: status code %d", res.StatusCode)
+	}
+
+	//parse data
+	answer := lyricsAnswer{}
+	err = json.NewDecoder(res.Body).Decode(&answer)
+	if err != nil {
+		return entities.ExtraSongData{}, fmt.Errorf("error unmarshalling response body: %w", err)
+	}
+	if answer.Lyrics == "" {
+		return entities.ExtraSongData{}, requiredinterfaces.ErrExtraDataNotFound
+	}
+	return entities.ExtraSongData{Text: strings.ReplaceAll(answer.Lyrics, "\r\n", "\n")}, nil
+}
@@ -0,0 +1,59 @@
+package lyricsProvider
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLyricsProvider_GetExtraSongData(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		expectedData entities.ExtraSongData
+		expectedErr  error
+		wantErr      bool
+	}{
+		{
+			name:         "Ok",
+			status:       http.StatusOK,
+			body:         `{"lyrics":"line 1\r\nline 2\r\n\r\nline 3"}`,
+			expectedData: entities.ExtraSongData{Text: "line 1\nline 2\n\nline 3"},
+		},
+		{
+			name:        "Not found",
+			status:      http.StatusNotFound,
+			body:        `{"error":"No lyrics found"}`,
+			expectedErr: requiredinterfaces.ErrExtraDataNotFound,
+			wantErr:     true,
+		},
+		{
+			name:    "Server error",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/AC/DC/Back In Black", r.URL.Path)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			p := NewLyricsProvider(server.URL+"/", nil, 0)
+			data, err := p.GetExtraSongData(context.Background(), entities.Song{Group: "AC/DC", Song: "Back In Black"})
+			assert.Equal(t, tt.expectedData, data)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
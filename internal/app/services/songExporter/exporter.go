@@ -0,0 +1,186 @@
+package songExporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"strconv"
+	"strings"
+)
+
+// Format of an export output.
+type Format string
+
+const (
+	// FormatJSON is a JSON array of songs.
+	FormatJSON Format = "json"
+	// FormatCSV is a CSV file with a header, see csvColumns.
+	FormatCSV Format = "csv"
+	// FormatNDJSON is a song object on every line.
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat returns the format by its name or a file extension without the dot, "jsonl" is NDJSON too.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", fmt.Errorf("unknown export format `%v`, expected json, csv or ndjson", name)
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+// csvColumns is the CSV header.
+var csvColumns = []string{"id", "group", "song", "album_id", "disc_number", "track_number", entities.FieldReleaseDate, entities.FieldText, entities.FieldLink, "enrichment_status", "version"}
+
+// Exporter streams songs of the library as JSON, CSV or NDJSON.
+type Exporter struct {
+	storage requiredinterfaces.SongExportStorage
+}
+
+func NewExporter(storage requiredinterfaces.SongExportStorage) *Exporter {
+	return &Exporter{storage: storage}
+}
+
+// songWriter writes songs of one format.
+type songWriter interface {
+	begin() error
+	write(song entities.Song) error
+	end() error
+}
+
+// Export writes songs matching the filter to w in the order of IDs and returns their number.
+// The output is buffered, so nothing is written to w if the songs can not be read at all.
+// A failure later leaves the output cut.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, filter entities.SongFilter, format Format) (int, error) {
+	buffered := bufio.NewWriter(w)
+	var writer songWriter
+	switch format {
+	case FormatJSON:
+		writer = &jsonWriter{w: buffered}
+	case FormatCSV:
+		writer = &csvWriter{w: csv.NewWriter(buffered)}
+	case FormatNDJSON:
+		writer = &ndjsonWriter{w: buffered}
+	default:
+		return 0, fmt.Errorf("unknown export format `%v`", format)
+	}
+
+	err := writer.begin()
+	if err != nil {
+		return 0, err
+	}
+	exported := 0
+	err = e.storage.ExportSongs(ctx, filter, func(song entities.Song) error {
+		exported++
+		return writer.write(song)
+	})
+	if err != nil {
+		return exported, err
+	}
+	err = writer.end()
+	if err != nil {
+		return exported, err
+	}
+	return exported, buffered.Flush()
+}
+
+type jsonWriter struct {
+	w     *bufio.Writer
+	first bool
+}
+
+func (j *jsonWriter) begin() error {
+	j.first = true
+	_, err := j.w.WriteString("[\n")
+	return err
+}
+
+func (j *jsonWriter) write(song entities.Song) error {
+	if !j.first {
+		j.w.WriteString(",")
+	}
+	j.first = false
+	return json.NewEncoder(j.w).Encode(song)
+}
+
+func (j *jsonWriter) end() error {
+	_, err := j.w.WriteString("]\n")
+	return err
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (n *ndjsonWriter) begin() error {
+	return nil
+}
+
+func (n *ndjsonWriter) write(song entities.Song) error {
+	return json.NewEncoder(n.w).Encode(song)
+}
+
+func (n *ndjsonWriter) end() error {
+	return nil
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) begin() error {
+	return c.w.Write(csvColumns)
+}
+
+func (c *csvWriter) write(song entities.Song) error {
+	albumID := ""
+	if song.AlbumID != nil {
+		albumID = strconv.FormatUint(*song.AlbumID, 10)
+	}
+	return c.w.Write([]string{
+		strconv.FormatUint(song.ID, 10),
+		song.Group,
+		song.Song,
+		albumID,
+		optionalInt(song.DiscNumber),
+		optionalInt(song.TrackNumber),
+		song.ReleaseDateString(),
+		song.Text,
+		song.Link,
+		string(song.EnrichmentStatus),
+		strconv.FormatInt(song.Version, 10),
+	})
+}
+
+func (c *csvWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// optionalInt formats the number, zero is an empty cell.
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,108 @@
+package songExporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"testing"
+)
+
+func TestExporter_Export(t *testing.T) {
+	albumID := uint64(4)
+	songs := []entities.Song{
+		{ID: 1, Song: "Hysteria", GroupID: 2, Group: "Muse", AlbumID: &albumID, TrackNumber: 8, ReleaseDate: entities.MustParseReleaseDate("2003"), Text: "It's bugging me,\ngrating me", Version: 3},
+		{ID: 5, Song: "Innuendo", GroupID: 3, Group: "Queen", EnrichmentStatus: entities.EnrichmentPending, Version: 1},
+	}
+	filter := entities.SongFilter{Group: "e"}
+
+	tests := []struct {
+		name             string
+		format           Format
+		storage          func(c *gomock.Controller) requiredinterfaces.SongExportStorage
+		expectedOutput   string
+		expectedExported int
+		expectedErr      error
+	}{
+		{
+			name:   "CSV",
+			format: FormatCSV,
+			storage: func(c *gomock.Controller) requiredinterfaces.SongExportStorage {
+				return exportStorage(c, filter, songs, nil)
+			},
+			expectedOutput: "id,group,song,album_id,disc_number,track_number,release_date,text,link,enrichment_status,version\n" +
+				"1,Muse,Hysteria,4,,8,2003,\"It's bugging me,\ngrating me\",,,3\n" +
+				"5,Queen,Innuendo,,,,,,,pending,1\n",
+			expectedExported: 2,
+		},
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			storage: func(c *gomock.Controller) requiredinterfaces.SongExportStorage {
+				return exportStorage(c, filter, songs, nil)
+			},
+			expectedOutput: "[\n" +
+				`{"id":1,"song":"Hysteria","group_id":2,"group":"Muse","album_id":4,"track_number":8,"release_date":"2003","text":"It's bugging me,\ngrating me","version":3}` + "\n" +
+				`,{"id":5,"song":"Innuendo","group_id":3,"group":"Queen","enrichment_status":"pending","version":1}` + "\n" +
+				"]\n",
+			expectedExported: 2,
+		},
+		{
+			name:   "Empty JSON",
+			format: FormatJSON,
+			storage: func(c *gomock.Controller) requiredinterfaces.SongExportStorage {
+				return exportStorage(c, filter, nil, nil)
+			},
+			expectedOutput: "[\n]\n",
+		},
+		{
+			name:   "NDJSON",
+			format: FormatNDJSON,
+			storage: func(c *gomock.Controller) requiredinterfaces.SongExportStorage {
+				return exportStorage(c, filter, songs[1:], nil)
+			},
+			expectedOutput:   `{"id":5,"song":"Innuendo","group_id":3,"group":"Queen","enrichment_status":"pending","version":1}` + "\n",
+			expectedExported: 1,
+		},
+		{
+			name:   "Db error",
+			format: FormatCSV,
+			storage: func(c *gomock.Controller) requiredinterfaces.SongExportStorage {
+				return exportStorage(c, filter, nil, fmt.Errorf("test error"))
+			},
+			expectedErr: fmt.Errorf("test error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			e := NewExporter(tt.storage(c))
+			out := bytes.Buffer{}
+
+			exported, err := e.Export(context.Background(), &out, filter, tt.format)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedExported, exported)
+			assert.Equal(t, tt.expectedOutput, out.String())
+		})
+	}
+}
+
+// exportStorage returns a storage which exports the songs and then fails with err.
+func exportStorage(c *gomock.Controller, filter entities.SongFilter, songs []entities.Song, err error) requiredinterfaces.SongExportStorage {
+	storage := mocks.NewMockSongExportStorage(c)
+	storage.EXPECT().ExportSongs(gomock.Any(), filter, gomock.Any()).DoAndReturn(func(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error {
+		for _, song := range songs {
+			if fnErr := fn(song); fnErr != nil {
+				return fnErr
+			}
+		}
+		return err
+	})
+	return storage
+}
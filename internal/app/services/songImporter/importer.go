@@ -0,0 +1,197 @@
+package songImporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/pkg/databases/dberrors"
+)
+
+// Config of an Importer. Zero values are replaced by defaults.
+type Config struct {
+	// BatchSize is how many songs are saved in one transaction.
+	BatchSize int
+}
+
+func (c Config) withDefaults() Config {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 500
+	}
+	return c
+}
+
+// Options of an import.
+type Options struct {
+	Format Format
+	// Enrich makes new songs pending, so enrichment workers ask for their extra data.
+	Enrich bool
+}
+
+// Importer streams songs from CSV or JSON Lines into the library and reports the outcome of every row.
+type Importer struct {
+	storage requiredinterfaces.SongImportStorage
+	logger  *zap.SugaredLogger
+	conf    Config
+}
+
+func NewImporter(storage requiredinterfaces.SongImportStorage, logger *zap.SugaredLogger, conf Config) *Importer {
+	return &Importer{
+		storage: storage,
+		logger:  logger,
+		conf:    conf.withDefaults(),
+	}
+}
+
+// batchedSong is a valid song waiting to be saved with the index of its row in the report.
+type batchedSong struct {
+	row  int
+	song entities.Song
+}
+
+// Import reads the input row by row and saves valid songs in batches. Invalid rows fail, songs which are
+// in the library already or earlier in the input are skipped, neither stops the import.
+// An InputError, a read error or a cancelled ctx stop it, the report then has the rows processed so far.
+func (i *Importer) Import(ctx context.Context, r io.Reader, opts Options) (report entities.ImportReport, err error) {
+	report.Rows = []entities.ImportRowResult{}
+	defer report.Count()
+	reader, err := newRecordReader(r, opts.Format)
+	if err != nil {
+		return report, err
+	}
+
+	seen := map[string]int{}
+	batch := make([]batchedSong, 0, i.conf.BatchSize)
+	for row := 1; ; row++ {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rErr rowError
+		if errors.As(err, &rErr) {
+			report.Rows = append(report.Rows, failedRow(row, record, rErr.err))
+			continue
+		} else if err != nil {
+			return report, fmt.Errorf("failed to read row %d: %w", row, err)
+		}
+
+		song, err := record.song(opts.Enrich)
+		if err != nil {
+			report.Rows = append(report.Rows, failedRow(row, record, err))
+			continue
+		}
+		key := entities.SongKey(song.Group, song.Song)
+		if first, ok := seen[key]; ok {
+			report.Rows = append(report.Rows, entities.ImportRowResult{
+				Row:     row,
+				Status:  entities.ImportSkipped,
+				Group:   record.Group,
+				Song:    record.Song,
+				Message: fmt.Sprintf("duplicate of row %d", first),
+			})
+			continue
+		}
+		seen[key] = row
+
+		report.Rows = append(report.Rows, entities.ImportRowResult{Row: row, Group: record.Group, Song: record.Song})
+		batch = append(batch, batchedSong{row: len(report.Rows) - 1, song: song})
+		if len(batch) == i.conf.BatchSize {
+			err = i.save(ctx, &report, batch)
+			if err != nil {
+				return report, err
+			}
+			batch = batch[:0]
+		}
+	}
+	return report, i.save(ctx, &report, batch)
+}
+
+// save saves the batch in one transaction. If it fails, songs are saved one by one to find the failed ones.
+// An unavailable storage stops the import, saving songs one by one would fail too.
+func (i *Importer) save(ctx context.Context, report *entities.ImportReport, batch []batchedSong) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	songs := make([]entities.Song, 0, len(batch))
+	for _, b := range batch {
+		songs = append(songs, b.song)
+	}
+
+	imported, err := i.storage.ImportSongs(ctx, songs)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	} else if dberrors.IsRetryable(err) {
+		return err
+	} else if err != nil && len(batch) > 1 {
+		i.logger.Debugf("failed to save a batch of %d songs, saving them one by one: %v", len(batch), err)
+		for n := range batch {
+			err = i.save(ctx, report, batch[n:n+1])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	} else if err != nil {
+		i.logger.Errorf("failed to save imported song `%v` of `%v`: %v", batch[0].song.Song, batch[0].song.Group, err)
+		result := &report.Rows[batch[0].row]
+		result.Status, result.Message = entities.ImportFailed, "failed to save the song"
+		return nil
+	}
+
+	for n, b := range batch {
+		result := &report.Rows[b.row]
+		result.ID = imported[n].ID
+		result.Status = entities.ImportCreated
+		if imported[n].Existed {
+			result.Status, result.Message = entities.ImportSkipped, "song is in the library already"
+		}
+	}
+	return nil
+}
+
+// song turns the record into a valid song. Errors are entities.FieldError.
+func (r Record) song(enrich bool) (entities.Song, error) {
+	song := entities.Song{
+		Song:        r.Song,
+		Group:       r.Group,
+		AlbumID:     r.AlbumID,
+		DiscNumber:  r.DiscNumber,
+		TrackNumber: r.TrackNumber,
+		Text:        r.Text,
+		Link:        r.Link,
+	}
+	if r.ReleaseDate != "" {
+		date, err := entities.ParseReleaseDate(r.ReleaseDate)
+		if err != nil {
+			return entities.Song{}, entities.FieldError{Field: entities.FieldReleaseDate, Message: "release_date must look like 2006-01-02, 02.01.2006, 2006-01 or 2006"}
+		}
+		song.ReleaseDate = &date
+	}
+	err := song.Validate()
+	if err != nil {
+		return entities.Song{}, err
+	}
+	if enrich {
+		song.EnrichmentStatus = entities.EnrichmentPending
+	}
+	return song, nil
+}
+
+// failedRow is a result of a row which could not be read or is invalid.
+func failedRow(row int, record Record, err error) entities.ImportRowResult {
+	result := entities.ImportRowResult{
+		Row:     row,
+		Status:  entities.ImportFailed,
+		Group:   record.Group,
+		Song:    record.Song,
+		Message: err.Error(),
+	}
+	var fieldErr entities.FieldError
+	if errors.As(err, &fieldErr) {
+		result.Field, result.Message = fieldErr.Field, fieldErr.Message
+	}
+	return result
+}
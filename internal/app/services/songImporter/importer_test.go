@@ -0,0 +1,211 @@
+package songImporter
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"musiclib/pkg/databases/dberrors"
+	"strings"
+	"testing"
+)
+
+func TestImporter_Import(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	albumID := uint64(4)
+	tests := []struct {
+		name           string
+		input          string
+		opts           Options
+		storage        func(c *gomock.Controller) requiredinterfaces.SongImportStorage
+		expectedReport entities.ImportReport
+		expectedErr    error
+	}{
+		{
+			name: "CSV",
+			input: "song,group,album_id,track_number,release_date,link\n" +
+				"Hysteria,Muse,4,3,2003,https://example.com/hysteria\n" +
+				"Time Is Running Out,Muse,,,,\n" +
+				"hysteria , MUSE,,,,\n" +
+				",Muse,,,,\n" +
+				"Bohemian Rhapsody,Queen,,,next week,\n",
+			opts: Options{Format: FormatCSV, Enrich: true},
+			storage: func(c *gomock.Controller) requiredinterfaces.SongImportStorage {
+				storage := mocks.NewMockSongImportStorage(c)
+				storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{
+					{
+						Song:             "Hysteria",
+						Group:            "Muse",
+						AlbumID:          &albumID,
+						TrackNumber:      3,
+						ReleaseDate:      entities.MustParseReleaseDate("2003"),
+						Link:             "https://example.com/hysteria",
+						EnrichmentStatus: entities.EnrichmentPending,
+					},
+					{Song: "Time Is Running Out", Group: "Muse", EnrichmentStatus: entities.EnrichmentPending},
+				}).Return([]entities.ImportedSong{{ID: 10}, {ID: 3, Existed: true}}, nil)
+				return storage
+			},
+			expectedReport: entities.ImportReport{
+				Created: 1,
+				Skipped: 2,
+				Failed:  2,
+				Rows: []entities.ImportRowResult{
+					{Row: 1, Status: entities.ImportCreated, ID: 10, Group: "Muse", Song: "Hysteria"},
+					{Row: 2, Status: entities.ImportSkipped, ID: 3, Group: "Muse", Song: "Time Is Running Out", Message: "song is in the library already"},
+					{Row: 3, Status: entities.ImportSkipped, Group: "MUSE", Song: "hysteria", Message: "duplicate of row 1"},
+					{Row: 4, Status: entities.ImportFailed, Group: "Muse", Field: "song", Message: "song name is empty"},
+					{Row: 5, Status: entities.ImportFailed, Group: "Queen", Song: "Bohemian Rhapsody", Field: "release_date", Message: "release_date must look like 2006-01-02, 02.01.2006, 2006-01 or 2006"},
+				},
+			},
+		},
+		{
+			name: "JSON Lines",
+			input: `{"group":"Muse","song":"Hysteria","text":"It's bugging me"}` + "\n\n" +
+				`{"group":"Muse","song":"Uprising","track_number":"1"}` + "\n" +
+				`{"group":"Muse","song":"Starlight","year":2006}` + "\n" +
+				`{"group":"Muse",` + "\n" +
+				`{"group":"Queen","song":"Innuendo"}`,
+			opts: Options{Format: FormatJSONL},
+			storage: func(c *gomock.Controller) requiredinterfaces.SongImportStorage {
+				storage := mocks.NewMockSongImportStorage(c)
+				storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{
+					{Song: "Hysteria", Group: "Muse", Text: "It's bugging me"},
+					{Song: "Innuendo", Group: "Queen"},
+				}).Return([]entities.ImportedSong{{ID: 10}, {ID: 11}}, nil)
+				return storage
+			},
+			expectedReport: entities.ImportReport{
+				Created: 2,
+				Failed:  3,
+				Rows: []entities.ImportRowResult{
+					{Row: 1, Status: entities.ImportCreated, ID: 10, Group: "Muse", Song: "Hysteria"},
+					{Row: 2, Status: entities.ImportFailed, Field: "track_number", Message: "track_number has a wrong type"},
+					{Row: 3, Status: entities.ImportFailed, Message: `invalid JSON line: unknown field "year"`},
+					{Row: 4, Status: entities.ImportFailed, Message: "invalid JSON line: unexpected EOF"},
+					{Row: 5, Status: entities.ImportCreated, ID: 11, Group: "Queen", Song: "Innuendo"},
+				},
+			},
+		},
+		{
+			name:  "Failed batch is saved song by song",
+			input: "group,song\nMuse,Hysteria\nMuse,Uprising\n",
+			opts:  Options{Format: FormatCSV},
+			storage: func(c *gomock.Controller) requiredinterfaces.SongImportStorage {
+				storage := mocks.NewMockSongImportStorage(c)
+				gomock.InOrder(
+					storage.EXPECT().ImportSongs(gomock.Any(), gomock.Len(2)).Return(nil, fmt.Errorf("test error")),
+					storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{{Song: "Hysteria", Group: "Muse"}}).Return(nil, fmt.Errorf("test error")),
+					storage.EXPECT().ImportSongs(gomock.Any(), []entities.Song{{Song: "Uprising", Group: "Muse"}}).Return([]entities.ImportedSong{{ID: 11}}, nil),
+				)
+				return storage
+			},
+			expectedReport: entities.ImportReport{
+				Created: 1,
+				Failed:  1,
+				Rows: []entities.ImportRowResult{
+					{Row: 1, Status: entities.ImportFailed, Group: "Muse", Song: "Hysteria", Message: "failed to save the song"},
+					{Row: 2, Status: entities.ImportCreated, ID: 11, Group: "Muse", Song: "Uprising"},
+				},
+			},
+		},
+		{
+			name:  "Unknown CSV column",
+			input: "group,song,year\nMuse,Hysteria,2003\n",
+			opts:  Options{Format: FormatCSV},
+			storage: func(c *gomock.Controller) requiredinterfaces.SongImportStorage {
+				return mocks.NewMockSongImportStorage(c)
+			},
+			expectedReport: entities.ImportReport{Rows: []entities.ImportRowResult{}},
+			expectedErr:    InputError{Message: "unknown CSV column `year`"},
+		},
+		{
+			name:  "Missing CSV column",
+			input: "group,text\nMuse,It's bugging me\n",
+			opts:  Options{Format: FormatCSV},
+			storage: func(c *gomock.Controller) requiredinterfaces.SongImportStorage {
+				return mocks.NewMockSongImportStorage(c)
+			},
+			expectedReport: entities.ImportReport{Rows: []entities.ImportRowResult{}},
+			expectedErr:    InputError{Message: "CSV column `song` is missing"},
+		},
+		{
+			name:  "Empty input",
+			input: "",
+			opts:  Options{Format: FormatJSONL},
+			storage: func(c *gomock.Controller) requiredinterfaces.SongImportStorage {
+				return mocks.NewMockSongImportStorage(c)
+			},
+			expectedReport: entities.ImportReport{Rows: []entities.ImportRowResult{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			i := NewImporter(tt.storage(c), sugar, Config{})
+
+			report, err := i.Import(context.Background(), strings.NewReader(tt.input), tt.opts)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestImporter_Import_batches(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	storage := mocks.NewMockSongImportStorage(c)
+	gomock.InOrder(
+		storage.EXPECT().ImportSongs(gomock.Any(), gomock.Len(2)).Return([]entities.ImportedSong{{ID: 1}, {ID: 2}}, nil),
+		storage.EXPECT().ImportSongs(gomock.Any(), gomock.Len(1)).Return([]entities.ImportedSong{{ID: 3}}, nil),
+	)
+	i := NewImporter(storage, logger.Sugar(), Config{BatchSize: 2})
+
+	report, err := i.Import(context.Background(), strings.NewReader("group,song\nMuse,Hysteria\nMuse,Uprising\nMuse,Starlight\n"), Options{Format: FormatCSV})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, uint64(3), report.Rows[2].ID)
+}
+
+func TestImporter_Import_unavailable(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	storage := mocks.NewMockSongImportStorage(c)
+	storage.EXPECT().ImportSongs(gomock.Any(), gomock.Len(2)).Return(nil, dberrors.NewUnavailableErr(fmt.Errorf("connection refused")))
+	i := NewImporter(storage, logger.Sugar(), Config{})
+
+	_, err := i.Import(context.Background(), strings.NewReader("group,song\nMuse,Hysteria\nMuse,Uprising\n"), Options{Format: FormatCSV})
+
+	assert.True(t, dberrors.IsRetryable(err))
+}
+
+func TestImporter_Import_cancelled(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	storage := mocks.NewMockSongImportStorage(c)
+	storage.EXPECT().ImportSongs(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, []entities.Song) ([]entities.ImportedSong, error) {
+		cancel()
+		return nil, context.Canceled
+	})
+	i := NewImporter(storage, logger.Sugar(), Config{BatchSize: 1})
+
+	_, err := i.Import(ctx, strings.NewReader("group,song\nMuse,Hysteria\nMuse,Uprising\n"), Options{Format: FormatCSV})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,195 @@
+package songImporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"musiclib/internal/app/entities"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format of an import input.
+type Format string
+
+const (
+	// FormatCSV is a CSV file with a header of Record field names, columns may go in any order.
+	FormatCSV Format = "csv"
+	// FormatJSONL is JSON Lines: a Record object on every line.
+	FormatJSONL Format = "jsonl"
+)
+
+// ParseFormat returns the format by its name or a file extension without the dot, "ndjson" is JSON Lines too.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("unknown import format `%v`, expected csv or jsonl", name)
+}
+
+// Record is a row of an import input.
+type Record struct {
+	Group       string  `json:"group"`
+	Song        string  `json:"song"`
+	AlbumID     *uint64 `json:"album_id"`
+	DiscNumber  int     `json:"disc_number"`
+	TrackNumber int     `json:"track_number"`
+	ReleaseDate string  `json:"release_date"`
+	Text        string  `json:"text"`
+	Link        string  `json:"link"`
+}
+
+// InputError is an input which can not be imported at all, like a CSV header without the song column.
+type InputError struct {
+	Message string
+}
+
+func (e InputError) Error() string {
+	return e.Message
+}
+
+// rowError is a row which can not be read, the rows after it are still read.
+type rowError struct {
+	err error
+}
+
+func (e rowError) Error() string {
+	return e.err.Error()
+}
+
+func (e rowError) Unwrap() error {
+	return e.err
+}
+
+// recordReader reads records one by one. Next returns io.EOF after the last one and rowError for a bad row.
+type recordReader interface {
+	Next() (Record, error)
+}
+
+func newRecordReader(r io.Reader, format Format) (recordReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		return &jsonlReader{reader: bufio.NewReader(r)}, nil
+	}
+	return nil, InputError{Message: fmt.Sprintf("unknown import format `%v`, expected csv or jsonl", format)}
+}
+
+// csvColumns are names of Record fields in a CSV header.
+var csvColumns = []string{"group", "song", "album_id", "disc_number", "track_number", entities.FieldReleaseDate, entities.FieldText, entities.FieldLink}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// newCSVReader reads the header. Unknown columns are refused, so a misspelled one does not leave a field empty.
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, InputError{Message: "CSV header is missing"}
+	} else if err != nil {
+		return nil, InputError{Message: fmt.Sprintf("failed to read CSV header: %v", err)}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(csvColumns, name) {
+			return nil, InputError{Message: fmt.Sprintf("unknown CSV column `%v`", name)}
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"group", "song"} {
+		if _, ok := columns[name]; !ok {
+			return nil, InputError{Message: fmt.Sprintf("CSV column `%v` is missing", name)}
+		}
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvReader) Next() (Record, error) {
+	row, err := c.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Record{}, rowError{err: entities.FieldError{Message: fmt.Sprintf("invalid CSV row: %v", parseErr.Err)}}
+	} else if err != nil {
+		return Record{}, err
+	}
+	column := func(name string) string {
+		i, ok := c.columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	record := Record{
+		Group:       column("group"),
+		Song:        column("song"),
+		ReleaseDate: column(entities.FieldReleaseDate),
+		Text:        column(entities.FieldText),
+		Link:        column(entities.FieldLink),
+	}
+	if value := column("album_id"); value != "" {
+		albumID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || albumID == 0 {
+			return Record{}, rowError{err: entities.FieldError{Field: "album_id", Message: "album_id must be a positive integer"}}
+		}
+		record.AlbumID = &albumID
+	}
+	for name, target := range map[string]*int{"disc_number": &record.DiscNumber, "track_number": &record.TrackNumber} {
+		if value := column(name); value != "" {
+			*target, err = strconv.Atoi(value)
+			if err != nil {
+				return Record{}, rowError{err: entities.FieldError{Field: name, Message: name + " must be an integer"}}
+			}
+		}
+	}
+	return record, nil
+}
+
+type jsonlReader struct {
+	reader *bufio.Reader
+}
+
+// Next skips blank lines. Lines are not limited in length, lyrics may be long.
+func (j *jsonlReader) Next() (Record, error) {
+	for {
+		line, err := j.reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return Record{}, err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Record{}, err
+		}
+
+		record := Record{}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		decodeErr := decoder.Decode(&record)
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(decodeErr, &typeErr) {
+			return Record{}, rowError{err: entities.FieldError{Field: typeErr.Field, Message: typeErr.Field + " has a wrong type"}}
+		} else if decodeErr != nil {
+			return Record{}, rowError{err: entities.FieldError{Message: fmt.Sprintf("invalid JSON line: %v", strings.TrimPrefix(decodeErr.Error(), "json: "))}}
+		}
+		record.Group, record.Song = strings.TrimSpace(record.Group), strings.TrimSpace(record.Song)
+		return record, nil
+	}
+}
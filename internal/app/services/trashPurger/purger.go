@@ -0,0 +1,73 @@
+package trashPurger
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"musiclib/internal/app/requiredinterfaces"
+	"time"
+)
+
+// Config of a Purger. Zero values are replaced by defaults.
+type Config struct {
+	// Retention is how long removed songs stay in the trash.
+	Retention time.Duration
+	// Interval is how often the trash is purged.
+	Interval time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Retention <= 0 {
+		c.Retention = 30 * 24 * time.Hour
+	}
+	if c.Interval <= 0 {
+		c.Interval = time.Hour
+	}
+	return c
+}
+
+// Purger periodically removes songs which have been in the trash longer than the retention period.
+type Purger struct {
+	storage requiredinterfaces.TrashStorage
+	logger  *zap.SugaredLogger
+	conf    Config
+	now     func() time.Time
+}
+
+func NewPurger(storage requiredinterfaces.TrashStorage, logger *zap.SugaredLogger, conf Config) *Purger {
+	return &Purger{
+		storage: storage,
+		logger:  logger,
+		conf:    conf.withDefaults(),
+		now:     time.Now,
+	}
+}
+
+// Run purges the trash at once and then every Interval, it blocks until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.conf.Interval)
+	defer ticker.Stop()
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge removes songs which were moved to the trash before the retention period.
+func (p *Purger) purge(ctx context.Context) {
+	before := p.now().Add(-p.conf.Retention)
+	purged, err := p.storage.PurgeTrash(ctx, before)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Errorf("failed to purge the trash: %v", err)
+		}
+		return
+	}
+	if purged > 0 {
+		p.logger.Infof("purged %d songs removed before %v", purged, before.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,95 @@
+package trashPurger
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/internal/app/requiredinterfaces/mocks"
+	"testing"
+	"time"
+)
+
+func TestPurger_purge(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	now := time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		conf    Config
+		storage func(c *gomock.Controller) requiredinterfaces.TrashStorage
+	}{
+		{
+			name: "Ok",
+			conf: Config{Retention: 24 * time.Hour},
+			storage: func(c *gomock.Controller) requiredinterfaces.TrashStorage {
+				storage := mocks.NewMockTrashStorage(c)
+				storage.EXPECT().PurgeTrash(gomock.Any(), now.Add(-24*time.Hour)).Return(int64(3), nil)
+				return storage
+			},
+		},
+		{
+			name: "Default retention",
+			storage: func(c *gomock.Controller) requiredinterfaces.TrashStorage {
+				storage := mocks.NewMockTrashStorage(c)
+				storage.EXPECT().PurgeTrash(gomock.Any(), now.Add(-30*24*time.Hour)).Return(int64(0), nil)
+				return storage
+			},
+		},
+		{
+			name: "Db error",
+			conf: Config{Retention: time.Hour},
+			storage: func(c *gomock.Controller) requiredinterfaces.TrashStorage {
+				storage := mocks.NewMockTrashStorage(c)
+				storage.EXPECT().PurgeTrash(gomock.Any(), now.Add(-time.Hour)).Return(int64(0), fmt.Errorf("test error"))
+				return storage
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			p := NewPurger(tt.storage(c), sugar, tt.conf)
+			p.now = func() time.Time { return now }
+			p.purge(context.Background())
+		})
+	}
+}
+
+func TestPurger_Run(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sugar := logger.Sugar()
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := mocks.NewMockTrashStorage(c)
+	purged := make(chan struct{}, 2)
+	storage.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+		select {
+		case purged <- struct{}{}:
+		default:
+		}
+		return 1, nil
+	}).MinTimes(2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewPurger(storage, sugar, Config{Interval: 10 * time.Millisecond}).Run(ctx)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-purged:
+		case <-time.After(time.Second):
+			t.Fatal("trash was not purged")
+		}
+	}
+	cancel()
+	<-done
+}
@@ -1,9 +1,118 @@
 package dberrors
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-var errNotFound error = errors.New("no content found")
+// Kind tells what went wrong in a storage, so callers can answer without knowing the backend.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	// KindNotFound is for content which does not exist.
+	KindNotFound
+	// KindConflict is for content which conflicts with existing content, like a duplicate of a unique value.
+	KindConflict
+	// KindVersionMismatch is for content which was changed since the version the caller expected.
+	KindVersionMismatch
+	// KindInvalid is for content the storage rejects, like a reference to missing content or a broken constraint.
+	KindInvalid
+	// KindUnavailable is for a storage which cannot serve now: the connection is lost, it is overloaded or a transaction
+	// lost a race. Retrying later may succeed.
+	KindUnavailable
+	// KindTimeout is for an operation which took too long and was cancelled. Retrying later may succeed.
+	KindTimeout
+)
+
+var kindMessages = map[Kind]string{
+	KindUnknown:         "storage failed",
+	KindNotFound:        "no content found",
+	KindConflict:        "conflicts with existing content",
+	KindVersionMismatch: "content was changed since the expected version",
+	KindInvalid:         "content is invalid",
+	KindUnavailable:     "storage is unavailable",
+	KindTimeout:         "storage timed out",
+}
+
+// Error is a storage error of a kind. It matches any error of the same kind in errors.Is
+// and unwraps to the error of the backend it was made of.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return kindMessages[e.Kind]
+	}
+	return kindMessages[e.Kind] + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind
+}
+
+var errNotFound error = &Error{Kind: KindNotFound}
+var errConflict error = &Error{Kind: KindConflict}
+var errVersionMismatch error = &Error{Kind: KindVersionMismatch}
 
 func NewNotFoundErr() error {
 	return errNotFound
 }
+
+func NewConflictErr() error {
+	return errConflict
+}
+
+func NewVersionMismatchErr() error {
+	return errVersionMismatch
+}
+
+// NewInvalidErr returns an error of KindInvalid caused by err.
+func NewInvalidErr(err error) error {
+	return &Error{Kind: KindInvalid, Err: err}
+}
+
+// NewUnavailableErr returns an error of KindUnavailable caused by err.
+func NewUnavailableErr(err error) error {
+	return &Error{Kind: KindUnavailable, Err: err}
+}
+
+// NewTimeoutErr returns an error of KindTimeout caused by err.
+func NewTimeoutErr(err error) error {
+	return &Error{Kind: KindTimeout, Err: err}
+}
+
+// Wrap returns err as an error of the kind, keeping it as the cause. Errors which already have a kind are returned as is.
+func Wrap(kind Kind, err error) error {
+	var e *Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of the first storage error in the chain of err.
+// An expired context deadline is KindTimeout even if the backend did not wrap it.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return KindTimeout
+	}
+	return KindUnknown
+}
+
+// IsRetryable tells whether the same operation may succeed later.
+func IsRetryable(err error) bool {
+	kind := KindOf(err)
+	return kind == KindUnavailable || kind == KindTimeout
+}
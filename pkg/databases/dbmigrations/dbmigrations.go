@@ -0,0 +1,85 @@
+// Package dbmigrations reads versioned SQL migrations of the database backends.
+// Every backend keeps its own scripts and applies them in its own way.
+package dbmigrations
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// fileName is like "0002_groups.up.sql".
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration changes the schema to its version with the Up script and back with the Down one.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status tells whether a migration was applied, AppliedAt is nil for a pending one.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Load reads migrations from the directory of fsys ordered by version. Every version needs both scripts.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file `%v`", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		script, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both `%v` and `%v`", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(script)
+		} else {
+			m.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d `%v` needs both up and down scripts", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// CheckVersion refuses a database migrated by a newer binary, its schema is unknown to this one.
+// lastApplied is the version of the last applied migration, zero for a new database.
+func CheckVersion(migrations []Migration, lastApplied int) error {
+	if len(migrations) > 0 && lastApplied > migrations[len(migrations)-1].Version {
+		return fmt.Errorf("database schema version %d is newer than the latest known migration %d",
+			lastApplied, migrations[len(migrations)-1].Version)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package gormpostgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"musiclib/pkg/databases/dberrors"
+	"net"
+)
+
+// pgErrorKinds are kinds of Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+var pgErrorKinds = map[string]dberrors.Kind{
+	"23505": dberrors.KindConflict,    // unique_violation
+	"23P01": dberrors.KindConflict,    // exclusion_violation
+	"23503": dberrors.KindInvalid,     // foreign_key_violation
+	"23502": dberrors.KindInvalid,     // not_null_violation
+	"23514": dberrors.KindInvalid,     // check_violation
+	"40001": dberrors.KindUnavailable, // serialization_failure
+	"40P01": dberrors.KindUnavailable, // deadlock_detected
+	"55P03": dberrors.KindUnavailable, // lock_not_available
+	"57P01": dberrors.KindUnavailable, // admin_shutdown
+	"57P02": dberrors.KindUnavailable, // crash_shutdown
+	"57P03": dberrors.KindUnavailable, // cannot_connect_now
+	"57014": dberrors.KindTimeout,     // query_canceled, by statement_timeout
+}
+
+// pgErrorClassKinds are kinds of whole classes of Postgres error codes, the first two characters of a code.
+var pgErrorClassKinds = map[string]dberrors.Kind{
+	"08": dberrors.KindUnavailable, // connection exception
+	"22": dberrors.KindInvalid,     // data exception
+	"53": dberrors.KindUnavailable, // insufficient resources
+}
+
+// dialector translates errors of Postgres and the connection to it into dberrors, gorm calls it for every failed statement.
+type dialector struct {
+	*postgres.Dialector
+}
+
+func (d dialector) Translate(err error) error {
+	return translateError(err)
+}
+
+// translateError returns err as a dberrors error of its kind, errors of an unknown kind are returned as is.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		kind, ok := pgErrorKinds[pgErr.Code]
+		if !ok {
+			kind, ok = pgErrorClassKinds[pgErr.Code[:min(2, len(pgErr.Code))]]
+		}
+		if ok {
+			return dberrors.Wrap(kind, err)
+		}
+		return err
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	switch {
+	case pgconn.Timeout(err), errors.Is(err, context.DeadlineExceeded):
+		return dberrors.Wrap(dberrors.KindTimeout, err)
+	case errors.As(err, &connectErr), errors.As(err, &netErr), errors.Is(err, driver.ErrBadConn), pgconn.SafeToRetry(err):
+		return dberrors.Wrap(dberrors.KindUnavailable, err)
+	}
+	return err
+}
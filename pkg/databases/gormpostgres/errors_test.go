@@ -0,0 +1,42 @@
+package gormpostgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"musiclib/pkg/databases/dberrors"
+	"net"
+	"testing"
+)
+
+func Test_translateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected dberrors.Kind
+	}{
+		{name: "Unique violation", err: &pgconn.PgError{Code: "23505"}, expected: dberrors.KindConflict},
+		{name: "Foreign key violation", err: &pgconn.PgError{Code: "23503"}, expected: dberrors.KindInvalid},
+		{name: "Invalid text representation", err: &pgconn.PgError{Code: "22P02"}, expected: dberrors.KindInvalid},
+		{name: "Serialization failure", err: &pgconn.PgError{Code: "40001"}, expected: dberrors.KindUnavailable},
+		{name: "Too many connections", err: &pgconn.PgError{Code: "53300"}, expected: dberrors.KindUnavailable},
+		{name: "Statement timeout", err: &pgconn.PgError{Code: "57014"}, expected: dberrors.KindTimeout},
+		{name: "Syntax error", err: &pgconn.PgError{Code: "42601"}, expected: dberrors.KindUnknown},
+		{name: "Wrapped", err: fmt.Errorf("failed to save: %w", &pgconn.PgError{Code: "23505"}), expected: dberrors.KindConflict},
+		{name: "Deadline", err: context.DeadlineExceeded, expected: dberrors.KindTimeout},
+		{name: "Lost connection", err: &net.OpError{Op: "read", Err: fmt.Errorf("connection reset by peer")}, expected: dberrors.KindUnavailable},
+		{name: "Bad connection", err: driver.ErrBadConn, expected: dberrors.KindUnavailable},
+		{name: "Record not found", err: gorm.ErrRecordNotFound, expected: dberrors.KindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+
+			assert.Equal(t, tt.expected, dberrors.KindOf(err))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
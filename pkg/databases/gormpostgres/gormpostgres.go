@@ -1,97 +1,112 @@
+// Package gormpostgres is a storage in a Postgres database. Queries are the ones of gormstorage,
+// this package opens the database and gives them the SQL of Postgres.
 package gormpostgres
 
 import (
-	"context"
-	"errors"
+	"embed"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"musiclib/internal/app/entities"
-	"musiclib/pkg/databases/dberrors"
+	"gorm.io/gorm/clause"
+	"io/fs"
+	"musiclib/pkg/databases/gormstorage"
+	"strings"
 )
 
-type GormDB struct {
-	db *gorm.DB
-}
+// migrationsLockID is a key of the advisory lock which lets only one replica migrate the database at a time.
+const migrationsLockID = 7_360_115_042
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// normalizedNameSQL is the SQL equivalent of entities.NormalizeGroupName.
+const normalizedNameSQL = "lower(regexp_replace(btrim(%s), '\\s+', ' ', 'g'))"
 
 // NewGormDB opens a new connection to a postgresql database.
-func NewGormDB(dsn string) (*GormDB, error) {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// Errors of the database are translated into dberrors, so callers can tell a conflict from a lost connection.
+func NewGormDB(dsn string) (*gormstorage.GormDB, error) {
+	db, err := open(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
-	return &GormDB{db: db}, nil
+	return gormstorage.New(db, postgresDialect{}), nil
 }
 
-// Migrate migrates entities from package "entities" to a database.
-func (g *GormDB) Migrate() error {
-	err := g.db.AutoMigrate(entities.Song{})
+// open opens the database with a dialector which translates errors.
+func open(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector{postgres.Open(dsn).(*postgres.Dialector)}, &gorm.Config{TranslateError: true})
 	if err != nil {
-		return fmt.Errorf("failed to migrate migrations: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	return nil
+	return db, nil
 }
 
-// SaveSong saves a new song and returns its ID.
-func (g *GormDB) SaveSong(ctx context.Context, song entities.Song) (uint64, error) {
-	if err := g.db.WithContext(ctx).Create(&song).Error; err != nil {
-		return 0, err
-	}
-	return song.ID, nil
+// postgresDialect is the gormstorage.Dialect of Postgres.
+type postgresDialect struct{}
+
+func (postgresDialect) ILike(expr string) string {
+	return expr + " ILIKE ?"
 }
 
-// GetSongList returns list of songs.
-func (g *GormDB) GetSongList(ctx context.Context, filter entities.Song, offset int, limit int) ([]entities.Song, error) {
-	var songs []entities.Song
-	query := g.db.WithContext(ctx).Model(&entities.Song{})
+func (postgresDialect) HasAlias() string {
+	return "EXISTS (SELECT 1 FROM jsonb_array_elements_text(groups.aliases) AS alias WHERE " + fmt.Sprintf(normalizedNameSQL, "alias") + " = ?)"
+}
 
-	//filter
-	if filter.ID != 0 {
-		query = query.Where("id = ?", filter.ID)
-	}
-	if filter.Song != "" {
-		query = query.Where("song ILIKE ?", "%"+filter.Song+"%")
-	}
-	if filter.Group != "" {
-		query = query.Where("\"group\" ILIKE ?", "%"+filter.Group+"%")
-	}
-	if filter.ReleaseDate != "" {
-		query = query.Where("release_date = ?", filter.ReleaseDate)
-	}
+func (postgresDialect) Date(expr string) string {
+	return "CAST(" + expr + " AS date)"
+}
 
-	// get songs
-	err := query.Offset(offset).Limit(limit).Find(&songs).Error
-	if len(songs) == 0 {
-		return songs, dberrors.NewNotFoundErr()
+func (postgresDialect) LockRows(query *gorm.DB, table string, skipLocked bool) *gorm.DB {
+	locking := clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: table}}
+	if skipLocked {
+		locking.Options = "SKIP LOCKED"
 	}
-	return songs, err
+	return query.Clauses(locking)
 }
 
-// GetSongLyrics returns song`s lyrics.
-func (g *GormDB) GetSongLyrics(ctx context.Context, id uint64) (string, error) {
-	var song entities.Song
-	err := g.db.WithContext(ctx).Select("text").First(&song, id).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return "", dberrors.NewNotFoundErr()
-	} else if err != nil {
-		return "", err
-	}
-	return song.Text, nil
+// WithSimilarity sets the threshold of the % operator of pg_trgm for the transaction, so trigram indexes serve Similar.
+func (postgresDialect) WithSimilarity(db *gorm.DB, similarity float64, fn func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", fmt.Sprint(similarity)).Error
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
 }
 
-// RemoveSong removes the song.
-func (g *GormDB) RemoveSong(ctx context.Context, id uint64) error {
-	return g.db.WithContext(ctx).Delete(&entities.Song{}, id).Error
+func (postgresDialect) Similar(a, b string, _ float64) string {
+	return a + " % " + b
 }
 
-// UpdateSong updates the song.
-func (g *GormDB) UpdateSong(ctx context.Context, song entities.Song) error {
-	tx := g.db.WithContext(ctx).Model(&entities.Song{}).Where("id = ?", song.ID).Updates(&song)
+func (postgresDialect) Greatest(exprs ...string) string {
+	return "GREATEST(" + strings.Join(exprs, ", ") + ")"
+}
 
-	// Проверяем количество затронутых строк
-	if tx.RowsAffected == 0 {
-		return dberrors.NewNotFoundErr()
-	}
+func (postgresDialect) Migrations() (fs.FS, string) {
+	return migrationFiles, "migrations"
+}
+
+// WithMigrationsLock takes an advisory lock on a single connection, so concurrent replicas wait for each other.
+func (postgresDialect) WithMigrationsLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		//every query gets a clean statement on the same connection
+		conn = conn.Session(&gorm.Session{})
+
+		err := conn.Exec("SELECT pg_advisory_lock(?)", migrationsLockID).Error
+		if err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationsLockID)
 
-	return tx.Error
+		err = conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamptz NOT NULL
+		)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
 }
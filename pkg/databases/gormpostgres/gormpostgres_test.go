@@ -0,0 +1,79 @@
+package gormpostgres
+
+import (
+	"context"
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"io"
+	"musiclib/pkg/databases/gormstorage"
+	"musiclib/pkg/databases/storagetest"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGormDB runs the storage suite against the database of TEST_DB_CONNECTION_STRING, like the Postgres of
+// docker-compose.debug.yaml with a database of its own, or against an embedded Postgres started by the test
+// if it is not set. The database is migrated and all its tables are emptied before every test.
+// The database needs a UTF-8 locale, so case-insensitive filters know letters beyond ASCII.
+func TestGormDB(t *testing.T) {
+	dsn := os.Getenv("TEST_DB_CONNECTION_STRING")
+	if dsn == "" {
+		dsn = startEmbeddedPostgres(t)
+	}
+	db, err := open(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := gormstorage.New(db, postgresDialect{})
+	_, err = g.MigrateUp(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		err := db.Exec("TRUNCATE songs, groups, albums, enrichment_jobs, cached_extra_data, song_revisions RESTART IDENTITY CASCADE").Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	})
+}
+
+// startEmbeddedPostgres starts Postgres of the version of docker-compose.yaml on a free port, it is stopped
+// when the test ends. Binaries are downloaded once into the cache of the library.
+// The test is skipped if Postgres can not be started, unless it runs in CI.
+func startEmbeddedPostgres(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := uint32(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	dir := t.TempDir()
+	conf := embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V15).
+		Port(port).
+		Database("musiclib").
+		Locale("C.UTF-8").
+		Encoding("UTF8").
+		RuntimePath(filepath.Join(dir, "runtime")).
+		DataPath(filepath.Join(dir, "data")).
+		Logger(io.Discard)
+	postgres := embeddedpostgres.NewDatabase(conf)
+	err = postgres.Start()
+	if err != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("failed to start embedded postgres: %v", err)
+		}
+		t.Skipf("TEST_DB_CONNECTION_STRING is not set and embedded postgres can not be started: %v", err)
+	}
+	t.Cleanup(func() {
+		err := postgres.Stop()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	return conf.GetConnectionURL() + "?sslmode=disable"
+}
@@ -0,0 +1,9 @@
+-- The schema of the first release, songs keep the group name in a free-text column.
+CREATE TABLE IF NOT EXISTS songs (
+	id bigserial PRIMARY KEY,
+	song text,
+	"group" text,
+	release_date text,
+	text text,
+	link text
+);
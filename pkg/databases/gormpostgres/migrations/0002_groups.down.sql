@@ -0,0 +1,4 @@
+ALTER TABLE songs ADD COLUMN "group" text;
+UPDATE songs SET "group" = groups.name FROM groups WHERE groups.id = songs.group_id;
+ALTER TABLE songs DROP COLUMN group_id;
+DROP TABLE groups;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS groups (
+	id bigserial PRIMARY KEY,
+	name text NOT NULL,
+	normalized_name text NOT NULL,
+	aliases jsonb,
+	country text,
+	formed_year bigint
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_normalized_name ON groups (normalized_name);
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_id bigint;
+CREATE INDEX IF NOT EXISTS idx_songs_group_id ON songs (group_id);
+
+-- Every distinct free-text group name becomes a group, the most used spelling is its name.
+-- The normalized name is the SQL equivalent of entities.NormalizeGroupName.
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'songs' AND column_name = 'group') THEN
+		INSERT INTO groups (name, normalized_name)
+		SELECT DISTINCT ON (normalized_name) name, normalized_name FROM (
+			SELECT regexp_replace(btrim("group"), '\s+', ' ', 'g') AS name,
+				lower(regexp_replace(btrim("group"), '\s+', ' ', 'g')) AS normalized_name, count(*) AS uses
+			FROM songs WHERE btrim("group") <> '' GROUP BY 1, 2
+		) AS legacy
+		ORDER BY normalized_name, uses DESC
+		ON CONFLICT (normalized_name) DO NOTHING;
+
+		UPDATE songs SET group_id = groups.id FROM groups
+		WHERE songs.group_id IS NULL AND groups.normalized_name = lower(regexp_replace(btrim(songs."group"), '\s+', ' ', 'g'));
+
+		ALTER TABLE songs DROP COLUMN "group";
+	END IF;
+END $$;
+
+ALTER TABLE songs DROP CONSTRAINT IF EXISTS fk_songs_group;
+ALTER TABLE songs ADD CONSTRAINT fk_songs_group FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE RESTRICT;
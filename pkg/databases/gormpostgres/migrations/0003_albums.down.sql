@@ -0,0 +1,4 @@
+ALTER TABLE songs DROP COLUMN album_id;
+ALTER TABLE songs DROP COLUMN disc_number;
+ALTER TABLE songs DROP COLUMN track_number;
+DROP TABLE albums;
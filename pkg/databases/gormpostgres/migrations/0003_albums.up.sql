@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS albums (
+	id bigserial PRIMARY KEY,
+	title text NOT NULL,
+	group_id bigint,
+	release_date text,
+	cover_link text
+);
+CREATE INDEX IF NOT EXISTS idx_albums_group_id ON albums (group_id);
+ALTER TABLE albums DROP CONSTRAINT IF EXISTS fk_albums_group;
+ALTER TABLE albums ADD CONSTRAINT fk_albums_group FOREIGN KEY (group_id) REFERENCES groups (id) ON DELETE RESTRICT;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS album_id bigint;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS disc_number bigint;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS track_number bigint;
+CREATE INDEX IF NOT EXISTS idx_songs_album_id ON songs (album_id);
+ALTER TABLE songs DROP CONSTRAINT IF EXISTS fk_songs_album;
+ALTER TABLE songs ADD CONSTRAINT fk_songs_album FOREIGN KEY (album_id) REFERENCES albums (id) ON DELETE SET NULL;
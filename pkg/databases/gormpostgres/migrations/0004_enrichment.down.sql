@@ -0,0 +1,3 @@
+DROP TABLE enrichment_jobs;
+ALTER TABLE songs DROP COLUMN enrichment_status;
+ALTER TABLE songs DROP COLUMN sources;
@@ -0,0 +1,14 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS enrichment_status text;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS sources jsonb;
+CREATE INDEX IF NOT EXISTS idx_songs_enrichment_status ON songs (enrichment_status);
+
+CREATE TABLE IF NOT EXISTS enrichment_jobs (
+	song_id bigint PRIMARY KEY,
+	attempts bigint NOT NULL DEFAULT 0,
+	next_attempt_at timestamptz NOT NULL,
+	last_error text,
+	created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_enrichment_jobs_next_attempt_at ON enrichment_jobs (next_attempt_at);
+ALTER TABLE enrichment_jobs DROP CONSTRAINT IF EXISTS fk_enrichment_jobs_song;
+ALTER TABLE enrichment_jobs ADD CONSTRAINT fk_enrichment_jobs_song FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
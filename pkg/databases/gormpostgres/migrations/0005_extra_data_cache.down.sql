@@ -0,0 +1 @@
+DROP TABLE cached_extra_data;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS cached_extra_data (
+	key text PRIMARY KEY,
+	release_date text,
+	text text,
+	link text,
+	sources jsonb,
+	not_found boolean,
+	expires_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_cached_extra_data_expires_at ON cached_extra_data (expires_at);
@@ -0,0 +1,9 @@
+UPDATE songs SET release_date_raw = CASE release_date_precision
+		WHEN 'year' THEN to_char(release_date, 'YYYY')
+		WHEN 'month' THEN to_char(release_date, 'MM.YYYY')
+		ELSE to_char(release_date, 'DD.MM.YYYY')
+	END
+WHERE release_date IS NOT NULL;
+ALTER TABLE songs DROP COLUMN release_date;
+ALTER TABLE songs DROP COLUMN release_date_precision;
+ALTER TABLE songs RENAME COLUMN release_date_raw TO release_date;
@@ -0,0 +1,44 @@
+-- Release dates were free-form strings. They are kept in release_date_raw and parsed into a date with a precision,
+-- values which can not be parsed stay in release_date_raw. Formats are the ones of entities.ParseReleaseDate.
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'songs' AND column_name = 'release_date' AND data_type <> 'date') THEN
+		ALTER TABLE songs RENAME COLUMN release_date TO release_date_raw;
+	END IF;
+END $$;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS release_date_raw text;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS release_date date;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS release_date_precision text;
+CREATE INDEX IF NOT EXISTS idx_songs_release_date ON songs (release_date);
+
+DO $$
+DECLARE
+	legacy record;
+	layout text;
+	date_precision text;
+BEGIN
+	FOR legacy IN SELECT id, btrim(release_date_raw) AS raw FROM songs
+		WHERE release_date IS NULL AND COALESCE(release_date_raw, '') <> '' LOOP
+		layout := NULL;
+		CASE
+			WHEN legacy.raw ~ '^\d{2}\.\d{2}\.\d{4}$' THEN layout := 'DD.MM.YYYY'; date_precision := 'day';
+			WHEN legacy.raw ~ '^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}.*)?$' THEN layout := 'YYYY-MM-DD'; date_precision := 'day';
+			WHEN legacy.raw ~ '^\d{2}\.\d{4}$' THEN layout := 'MM.YYYY'; date_precision := 'month';
+			WHEN legacy.raw ~ '^\d{4}-\d{2}$' THEN layout := 'YYYY-MM'; date_precision := 'month';
+			WHEN legacy.raw ~ '^\d{4}$' THEN layout := 'YYYY'; date_precision := 'year';
+			ELSE NULL;
+		END CASE;
+		CONTINUE WHEN layout IS NULL;
+
+		BEGIN
+			UPDATE songs SET release_date = to_date(left(legacy.raw, length(layout)), layout),
+				release_date_precision = date_precision, release_date_raw = ''
+			WHERE id = legacy.id;
+		EXCEPTION WHEN data_exception THEN
+			-- a date like 31.02.2009, it stays in release_date_raw
+			NULL;
+		END;
+	END LOOP;
+END $$;
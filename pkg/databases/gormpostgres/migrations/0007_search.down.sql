@@ -0,0 +1,3 @@
+ALTER TABLE songs DROP COLUMN search_simple;
+ALTER TABLE songs DROP COLUMN search_english;
+ALTER TABLE songs DROP COLUMN search_russian;
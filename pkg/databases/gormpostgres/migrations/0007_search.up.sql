@@ -0,0 +1,14 @@
+-- A tsvector column with a GIN index for every language of entities.SearchLanguages.
+-- Song names weigh more than lyrics, so a song named by the query ranks higher.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_simple tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', COALESCE(song, '')), 'A') || setweight(to_tsvector('simple', COALESCE(text, '')), 'B')
+) STORED;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_english tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('english', COALESCE(song, '')), 'A') || setweight(to_tsvector('english', COALESCE(text, '')), 'B')
+) STORED;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_russian tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('russian', COALESCE(song, '')), 'A') || setweight(to_tsvector('russian', COALESCE(text, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_songs_search_simple ON songs USING GIN (search_simple);
+CREATE INDEX IF NOT EXISTS idx_songs_search_english ON songs USING GIN (search_english);
+CREATE INDEX IF NOT EXISTS idx_songs_search_russian ON songs USING GIN (search_russian);
@@ -0,0 +1,2 @@
+DROP INDEX idx_songs_song_trgm;
+DROP INDEX idx_groups_name_trgm;
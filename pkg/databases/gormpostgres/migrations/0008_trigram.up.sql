@@ -0,0 +1,4 @@
+-- Trigram indexes let the % operator of pg_trgm find similar names without reading whole tables.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_songs_song_trgm ON songs USING GIN (song gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_groups_name_trgm ON groups USING GIN (name gin_trgm_ops);
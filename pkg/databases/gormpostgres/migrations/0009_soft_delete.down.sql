@@ -0,0 +1,3 @@
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+ALTER TABLE songs DROP COLUMN IF EXISTS deleted_at;
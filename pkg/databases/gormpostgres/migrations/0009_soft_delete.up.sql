@@ -0,0 +1,2 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS song_revisions (
+	id bigserial PRIMARY KEY,
+	song_id bigint NOT NULL,
+	revision bigint NOT NULL,
+	action text NOT NULL,
+	actor text,
+	request_id text,
+	changes jsonb,
+	song jsonb,
+	created_at timestamptz
+);
+-- Revisions outlive purged songs, so there is no foreign key to songs.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_revisions_song_revision ON song_revisions (song_id, revision);
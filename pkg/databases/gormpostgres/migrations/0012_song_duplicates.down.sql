@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_songs_group_normalized_name;
+ALTER TABLE songs DROP COLUMN IF EXISTS legacy_duplicate;
+DROP FUNCTION IF EXISTS normalize_song_name(text);
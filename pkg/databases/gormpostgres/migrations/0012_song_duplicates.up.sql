@@ -0,0 +1,17 @@
+-- The SQL equivalent of entities.NormalizeSongName: case, spaces and diacritics do not make songs different.
+CREATE OR REPLACE FUNCTION normalize_song_name(name text) RETURNS text
+	LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+	AS $$ SELECT normalize(regexp_replace(normalize(lower(btrim(regexp_replace(name, '\s+', ' ', 'g'))), NFD), '[\u0300-\u036f]', '', 'g'), NFC) $$;
+
+-- Duplicates created before the constraint are flagged, so it can be added without losing data.
+-- The oldest song of every set stays unique, the others wait for a merge.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS legacy_duplicate boolean NOT NULL DEFAULT false;
+UPDATE songs SET legacy_duplicate = true WHERE id IN (
+	SELECT id FROM (
+		SELECT id, row_number() OVER (PARTITION BY group_id, normalize_song_name(song) ORDER BY id) AS n
+		FROM songs WHERE deleted_at IS NULL
+	) AS ranked WHERE n > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_group_normalized_name ON songs (group_id, normalize_song_name(song))
+	WHERE deleted_at IS NULL AND NOT legacy_duplicate;
@@ -0,0 +1,3 @@
+-- The % operator finds likely duplicates through this index instead of comparing every pair of songs of a group.
+CREATE INDEX IF NOT EXISTS idx_songs_normalized_name_trgm ON songs USING GIN (normalize_song_name(song) gin_trgm_ops)
+	WHERE deleted_at IS NULL;
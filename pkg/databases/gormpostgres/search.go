@@ -0,0 +1,57 @@
+package gormpostgres
+
+import (
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/gormstorage"
+)
+
+// Options of ts_headline: a couplet is short enough to show it whole, lyrics are cut around the match.
+const (
+	coupletHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	lyricsHeadlineOptions  = "StartSel=<mark>, StopSel=</mark>, MinWords=10, MaxWords=30"
+)
+
+// escapedTextSQL is the lyrics of a found song with HTML escaped like entities.EscapeHeadline does,
+// headlines are made of it, so the marks of ts_headline are the only tags.
+const escapedTextSQL = `replace(replace(replace(replace(COALESCE(found.text, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;')`
+
+// searchColumn is the name of the tsvector column of songs for the language, see migration 0007_search.
+func searchColumn(language string) string {
+	return "search_" + language
+}
+
+// SearchSongs matches a web search query with the tsvector column of the language, the rank is ts_rank_cd.
+// The headline is the best matching couplet, or a part of the lyrics if the match spans couplets.
+func (postgresDialect) SearchSongs(db *gorm.DB, query entities.SearchQuery) ([]gormstorage.SearchRow, error) {
+	language := query.SearchLanguage()
+	column := "songs." + searchColumn(language)
+	toQuery := "websearch_to_tsquery"
+	if query.Phrase {
+		toQuery = "phraseto_tsquery"
+	}
+
+	//find a page of songs
+	found := gormstorage.WithGroup(db).
+		Select(`songs.*, groups.name AS "group", search.query AS search_query, ts_rank_cd(`+column+`, search.query) AS rank`).
+		Joins("CROSS JOIN "+toQuery+"(CAST(? AS regconfig), ?) AS search(query)", language, query.Text).
+		Where(column + " @@ search.query").
+		Order("rank DESC, songs.id").
+		Offset(query.Offset).Limit(query.PageLimit())
+
+	//headlines of the page
+	var rows []gormstorage.SearchRow
+	err := db.Table("(?) AS found", found).
+		Select(`found.*, COALESCE(couplet.headline, ts_headline(CAST(? AS regconfig), `+escapedTextSQL+`, found.search_query, ?)) AS headline`,
+			language, lyricsHeadlineOptions).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT ts_headline(CAST(? AS regconfig), part, found.search_query, ?) AS headline
+			FROM regexp_split_to_table(`+escapedTextSQL+`, '\n\n') WITH ORDINALITY AS couplets(part, num)
+			WHERE to_tsvector(CAST(? AS regconfig), part) @@ found.search_query
+			ORDER BY ts_rank_cd(to_tsvector(CAST(? AS regconfig), part), found.search_query) DESC, num
+			LIMIT 1
+		) AS couplet ON true`, language, coupletHeadlineOptions, language, language).
+		Order("found.rank DESC, found.id").
+		Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,48 @@
+package gormsqlite
+
+import (
+	"errors"
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"musiclib/pkg/databases/dberrors"
+)
+
+// sqliteErrorKinds are kinds of SQLite result codes, see https://www.sqlite.org/rescode.html.
+// Extended codes are looked up first and then their primary codes, the lowest byte.
+var sqliteErrorKinds = map[int]dberrors.Kind{
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     dberrors.KindConflict,
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: dberrors.KindConflict,
+	sqlite3.SQLITE_CONSTRAINT:            dberrors.KindInvalid,
+	sqlite3.SQLITE_BUSY:                  dberrors.KindUnavailable,
+	sqlite3.SQLITE_LOCKED:                dberrors.KindUnavailable,
+	sqlite3.SQLITE_IOERR:                 dberrors.KindUnavailable,
+	sqlite3.SQLITE_FULL:                  dberrors.KindUnavailable,
+	sqlite3.SQLITE_CANTOPEN:              dberrors.KindUnavailable,
+	sqlite3.SQLITE_INTERRUPT:             dberrors.KindTimeout,
+}
+
+// dialector translates errors of SQLite into dberrors, gorm calls it for every failed statement.
+type dialector struct {
+	*sqlite.Dialector
+}
+
+func (d dialector) Translate(err error) error {
+	return translateError(err)
+}
+
+// translateError returns err as a dberrors error of its kind, errors of an unknown kind are returned as is.
+func translateError(err error) error {
+	var sqliteErr *gosqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	kind, ok := sqliteErrorKinds[sqliteErr.Code()]
+	if !ok {
+		kind, ok = sqliteErrorKinds[sqliteErr.Code()&0xff]
+	}
+	if ok {
+		return dberrors.Wrap(kind, err)
+	}
+	return err
+}
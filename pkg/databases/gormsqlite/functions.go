@@ -0,0 +1,71 @@
+package gormsqlite
+
+import (
+	"database/sql/driver"
+	"github.com/glebarez/go-sqlite"
+	"musiclib/internal/app/entities"
+	"strings"
+	"unicode"
+)
+
+// SQL functions which SQLite lacks, they are registered for every connection of the driver:
+//   - unicode_lower(text) lowers all letters, the built-in lower knows only ASCII;
+//   - normalize_name(text) is entities.NormalizeGroupName;
+//   - normalize_song_name(text) is entities.NormalizeSongName;
+//   - similarity(a, b) is the trigram similarity of pg_trgm.
+//
+// NULL arguments give NULL, but similarity treats NULL as an empty text.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, textFunction(strings.ToLower))
+	sqlite.MustRegisterDeterministicScalarFunction("normalize_name", 1, textFunction(entities.NormalizeGroupName))
+	sqlite.MustRegisterDeterministicScalarFunction("normalize_song_name", 1, textFunction(entities.NormalizeSongName))
+	sqlite.MustRegisterDeterministicScalarFunction("similarity", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		a, _ := args[0].(string)
+		b, _ := args[1].(string)
+		return similarity(a, b), nil
+	})
+}
+
+// textFunction makes an SQL function of a text function.
+func textFunction(fn func(string) string) func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+	return func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		text, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		return fn(text), nil
+	}
+}
+
+// trigrams returns trigrams of the text like pg_trgm does: every lowercase word of letters and digits is padded
+// with two spaces in front and one behind, so beginnings of words weigh more.
+func trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		runes := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// similarity is the share of common trigrams of the texts from 0 to 1.
+// It is computed in float32 like pg_trgm, so ranks equal the ones of gormpostgres.
+func similarity(a, b string) float64 {
+	x, y := trigrams(a), trigrams(b)
+	common := 0
+	for t := range x {
+		if y[t] {
+			common++
+		}
+	}
+	all := len(x) + len(y) - common
+	if all == 0 {
+		return 0
+	}
+	return float64(float32(common) / float32(all))
+}
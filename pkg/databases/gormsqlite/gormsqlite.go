@@ -0,0 +1,121 @@
+// Package gormsqlite is a storage in a SQLite database file for small deployments and offline demos.
+// Queries are the ones of gormstorage, this package opens the database and gives them the SQL of SQLite.
+// It keeps the schema of gormpostgres. Times are stored in UTC as text, so they compare as strings.
+package gormsqlite
+
+import (
+	"embed"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"io/fs"
+	"musiclib/pkg/databases/gormstorage"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheme starts a connection string of a SQLite database, like sqlite://musiclib.db or sqlite:///var/lib/musiclib.db.
+const Scheme = "sqlite://"
+
+// connectionParams are set on every connection. Foreign keys are off in SQLite by default.
+// Transactions take the write lock at once, so concurrent writers wait for each other for the busy timeout
+// instead of failing on a lock upgrade, and WAL lets readers go on meanwhile.
+var connectionParams = url.Values{
+	"_pragma": {"foreign_keys(1)", "busy_timeout(10000)", "journal_mode(WAL)"},
+	"_txlock": {"immediate"},
+}
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// NewGormDB opens a SQLite database file, creating it if it does not exist.
+// The dsn is a path with or without Scheme, it may have params of the driver after "?".
+// Errors of the database are translated into dberrors, so callers can tell a conflict from a busy database.
+func NewGormDB(dsn string) (*gormstorage.GormDB, error) {
+	db, err := open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return gormstorage.New(db, sqliteDialect{}), nil
+}
+
+// open opens the database with connectionParams and a dialector which translates errors.
+func open(dsn string) (*gorm.DB, error) {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, Scheme), "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid params of database connection string: %w", err)
+	}
+	for name, values := range connectionParams {
+		params[name] = append(params[name], values...)
+	}
+
+	db, err := gorm.Open(dialector{sqlite.Open(path + "?" + params.Encode()).(*sqlite.Dialector)}, &gorm.Config{
+		NowFunc:        func() time.Time { return time.Now().UTC() },
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+// sqliteDialect is the gormstorage.Dialect of SQLite. Functions it lacks are registered in functions.go.
+type sqliteDialect struct{}
+
+// ILike lowers both sides by unicode_lower, LIKE and lower of SQLite know only ASCII letters.
+func (sqliteDialect) ILike(expr string) string {
+	return "unicode_lower(" + expr + ") LIKE unicode_lower(?) ESCAPE '\\'"
+}
+
+func (sqliteDialect) HasAlias() string {
+	return "EXISTS (SELECT 1 FROM json_each(groups.aliases) WHERE normalize_name(json_each.value) = ?)"
+}
+
+// Date takes the date of a stored time, which also has a time of day and a time zone.
+func (sqliteDialect) Date(expr string) string {
+	return "date(" + expr + ")"
+}
+
+// LockRows leaves the query as is. Write transactions take the lock of the whole database on begin
+// (see connectionParams), so no other transaction can change the rows meanwhile.
+func (sqliteDialect) LockRows(query *gorm.DB, _ string, _ bool) *gorm.DB {
+	return query
+}
+
+// WithSimilarity runs fn as is, Similar has the similarity in its condition.
+func (sqliteDialect) WithSimilarity(db *gorm.DB, _ float64, fn func(tx *gorm.DB) error) error {
+	return fn(db)
+}
+
+// Similar compares every row, SQLite has no index for trigrams.
+func (sqliteDialect) Similar(a, b string, similarity float64) string {
+	return "similarity(" + a + ", " + b + ") >= " + strconv.FormatFloat(similarity, 'g', -1, 64)
+}
+
+// Greatest uses the scalar max of SQLite.
+func (sqliteDialect) Greatest(exprs ...string) string {
+	return "max(" + strings.Join(exprs, ", ") + ")"
+}
+
+func (sqliteDialect) Migrations() (fs.FS, string) {
+	return migrationFiles, "migrations"
+}
+
+// WithMigrationsLock runs fn in a transaction, which holds the write lock of the database.
+// Every migration is applied in a nested transaction.
+func (sqliteDialect) WithMigrationsLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Transaction(func(conn *gorm.DB) error {
+		err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer PRIMARY KEY,
+			name text NOT NULL,
+			applied_at datetime NOT NULL
+		)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
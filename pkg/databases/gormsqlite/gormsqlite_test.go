@@ -0,0 +1,31 @@
+package gormsqlite
+
+import (
+	"context"
+	"musiclib/pkg/databases/gormstorage"
+	"musiclib/pkg/databases/storagetest"
+	"path/filepath"
+	"testing"
+)
+
+// TestGormDB runs the storage suite on a new migrated database file for every test.
+func TestGormDB(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		db, err := open(Scheme + filepath.Join(t.TempDir(), "musiclib.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			conn, err := db.DB()
+			if err == nil {
+				conn.Close()
+			}
+		})
+		g := gormstorage.New(db, sqliteDialect{})
+		_, err = g.MigrateUp(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	})
+}
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS songs_search_update;
+DROP TRIGGER IF EXISTS songs_search_delete;
+DROP TRIGGER IF EXISTS songs_search_insert;
+DROP TABLE IF EXISTS songs_search_english;
+DROP TABLE IF EXISTS songs_search_simple;
+DROP TABLE IF EXISTS song_revisions;
+DROP TABLE IF EXISTS cached_extra_data;
+DROP TABLE IF EXISTS enrichment_jobs;
+DROP TABLE IF EXISTS songs;
+DROP TABLE IF EXISTS albums;
+DROP TABLE IF EXISTS groups;
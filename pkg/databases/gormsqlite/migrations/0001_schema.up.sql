@@ -0,0 +1,98 @@
+-- The schema of gormpostgres up to its migration 0011_song_versions in one step.
+-- Times are text in UTC, see the package comment. Columns of JSON values are text.
+CREATE TABLE groups (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name text NOT NULL,
+	normalized_name text NOT NULL,
+	aliases text,
+	country text,
+	formed_year integer
+);
+CREATE UNIQUE INDEX idx_groups_normalized_name ON groups (normalized_name);
+
+CREATE TABLE albums (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	title text NOT NULL,
+	group_id integer REFERENCES groups (id) ON DELETE RESTRICT,
+	release_date text,
+	cover_link text
+);
+CREATE INDEX idx_albums_group_id ON albums (group_id);
+
+CREATE TABLE songs (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	song text,
+	group_id integer REFERENCES groups (id) ON DELETE RESTRICT,
+	album_id integer REFERENCES albums (id) ON DELETE SET NULL,
+	disc_number integer,
+	track_number integer,
+	release_date date,
+	release_date_precision text,
+	release_date_raw text,
+	text text,
+	link text,
+	enrichment_status text,
+	sources text,
+	version integer NOT NULL DEFAULT 1,
+	deleted_at datetime
+);
+CREATE INDEX idx_songs_group_id ON songs (group_id);
+CREATE INDEX idx_songs_album_id ON songs (album_id);
+CREATE INDEX idx_songs_release_date ON songs (release_date);
+CREATE INDEX idx_songs_enrichment_status ON songs (enrichment_status);
+CREATE INDEX idx_songs_deleted_at ON songs (deleted_at);
+
+CREATE TABLE enrichment_jobs (
+	song_id integer PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at datetime NOT NULL,
+	last_error text,
+	created_at datetime
+);
+CREATE INDEX idx_enrichment_jobs_next_attempt_at ON enrichment_jobs (next_attempt_at);
+
+CREATE TABLE cached_extra_data (
+	key text PRIMARY KEY,
+	release_date text,
+	text text,
+	link text,
+	sources text,
+	not_found boolean,
+	expires_at datetime NOT NULL
+);
+CREATE INDEX idx_cached_extra_data_expires_at ON cached_extra_data (expires_at);
+
+-- Revisions outlive purged songs, so there is no foreign key to songs.
+CREATE TABLE song_revisions (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	song_id integer NOT NULL,
+	revision integer NOT NULL,
+	action text NOT NULL,
+	actor text,
+	request_id text,
+	changes text,
+	song text,
+	created_at datetime
+);
+CREATE UNIQUE INDEX idx_song_revisions_song_revision ON song_revisions (song_id, revision);
+
+-- A full-text index of song names and lyrics for every tokenizer of searchTable.
+-- SQLite has no stemmer but the english one, so other languages are searched without stemming.
+-- The indexes keep no copy of the text, triggers keep them in sync with songs.
+CREATE VIRTUAL TABLE songs_search_simple USING fts5(song, text, content='songs', content_rowid='id', tokenize='unicode61');
+CREATE VIRTUAL TABLE songs_search_english USING fts5(song, text, content='songs', content_rowid='id', tokenize='porter unicode61');
+
+CREATE TRIGGER songs_search_insert AFTER INSERT ON songs BEGIN
+	INSERT INTO songs_search_simple (rowid, song, text) VALUES (new.id, new.song, new.text);
+	INSERT INTO songs_search_english (rowid, song, text) VALUES (new.id, new.song, new.text);
+END;
+CREATE TRIGGER songs_search_delete AFTER DELETE ON songs BEGIN
+	INSERT INTO songs_search_simple (songs_search_simple, rowid, song, text) VALUES ('delete', old.id, old.song, old.text);
+	INSERT INTO songs_search_english (songs_search_english, rowid, song, text) VALUES ('delete', old.id, old.song, old.text);
+END;
+CREATE TRIGGER songs_search_update AFTER UPDATE OF song, text ON songs BEGIN
+	INSERT INTO songs_search_simple (songs_search_simple, rowid, song, text) VALUES ('delete', old.id, old.song, old.text);
+	INSERT INTO songs_search_english (songs_search_english, rowid, song, text) VALUES ('delete', old.id, old.song, old.text);
+	INSERT INTO songs_search_simple (rowid, song, text) VALUES (new.id, new.song, new.text);
+	INSERT INTO songs_search_english (rowid, song, text) VALUES (new.id, new.song, new.text);
+END;
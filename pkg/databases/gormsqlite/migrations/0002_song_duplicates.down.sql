@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_songs_group_normalized_name;
+ALTER TABLE songs DROP COLUMN legacy_duplicate;
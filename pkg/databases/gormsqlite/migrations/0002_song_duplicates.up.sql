@@ -0,0 +1,13 @@
+-- normalize_song_name is entities.NormalizeSongName, see functions.go.
+-- Duplicates created before the constraint are flagged, so it can be added without losing data.
+-- The oldest song of every set stays unique, the others wait for a merge.
+ALTER TABLE songs ADD COLUMN legacy_duplicate boolean NOT NULL DEFAULT 0;
+UPDATE songs SET legacy_duplicate = 1 WHERE id IN (
+	SELECT id FROM (
+		SELECT id, row_number() OVER (PARTITION BY group_id, normalize_song_name(song) ORDER BY id) AS n
+		FROM songs WHERE deleted_at IS NULL
+	) WHERE n > 1
+);
+
+CREATE UNIQUE INDEX idx_songs_group_normalized_name ON songs (group_id, normalize_song_name(song))
+	WHERE deleted_at IS NULL AND NOT legacy_duplicate;
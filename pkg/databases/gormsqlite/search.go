@@ -0,0 +1,206 @@
+package gormsqlite
+
+import (
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/gormstorage"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// lyricsHeadlineWords is the length of a headline cut from lyrics when no couplet matches, like MaxWords of ts_headline.
+const lyricsHeadlineWords = 30
+
+// Marks of FTS5 highlights are control characters, so they can not be told from HTML of the lyrics.
+const (
+	markStart = "\x02"
+	markEnd   = "\x03"
+)
+
+// markedText is a text highlighted by FTS5, which wraps a whole matched phrase in one mark.
+var markedText = regexp.MustCompile(`(?s)\x02(.*?)\x03`)
+
+// searchTable is the name of the FTS5 index of songs for the language, see migration 0001_schema.
+// Only english has a stemmer in SQLite, other languages use the simple index.
+func searchTable(language string) string {
+	if language == "english" {
+		return "songs_search_english"
+	}
+	return "songs_search_simple"
+}
+
+// SearchSongs matches a web search query like the one of Postgres (see matchQuery) with the FTS5 index of the language.
+// The rank is the BM25 score, names weigh more than lyrics with the weights of ts_rank.
+// The headline is the couplet with the most matches, or a part of the lyrics if the match is only in the name.
+func (sqliteDialect) SearchSongs(db *gorm.DB, query entities.SearchQuery) ([]gormstorage.SearchRow, error) {
+	match := matchQuery(query.Text, query.Phrase)
+	if match == "" {
+		return nil, nil
+	}
+
+	table := searchTable(query.SearchLanguage())
+	var rows []gormstorage.SearchRow
+	err := gormstorage.WithGroup(db).
+		Select(`songs.*, groups.name AS "group", -bm25(`+table+`, 1.0, 0.4) AS rank,
+			COALESCE(highlight(`+table+`, 1, char(2), char(3)), '') AS headline`).
+		Joins("JOIN "+table+" ON "+table+".rowid = songs.id").
+		Where(table+" MATCH ?", match).
+		Order("rank DESC, songs.id").
+		Offset(query.Offset).Limit(query.PageLimit()).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Headline = headline(rows[i].Headline)
+	}
+	return rows, nil
+}
+
+// matchQuery converts a web search query to an FTS5 query: words, "quoted phrases", `or` between alternatives
+// and `-` to exclude a word or a phrase. A phrase query is a single phrase of all its words.
+// Every term is quoted, so FTS5 operators in the text are plain words. An alternative of only excluded terms
+// matches nothing like in websearch_to_tsquery and is dropped. An empty string is returned if nothing can match.
+func matchQuery(text string, phrase bool) string {
+	if phrase {
+		if !hasWords(text) {
+			return ""
+		}
+		return quoteTerm(text)
+	}
+
+	var alternatives []string
+	var include, exclude []string
+	addAlternative := func() {
+		if len(include) > 0 {
+			alternative := "(" + strings.Join(include, " AND ") + ")"
+			for _, term := range exclude {
+				alternative += " NOT " + term
+			}
+			alternatives = append(alternatives, "("+alternative+")")
+		}
+		include, exclude = nil, nil
+	}
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			break
+		}
+		excluded := strings.HasPrefix(text, "-")
+		text = strings.TrimPrefix(text, "-")
+
+		var term string
+		if strings.HasPrefix(text, `"`) {
+			term, text = text[1:], ""
+			if end := strings.Index(term, `"`); end >= 0 {
+				term, text = term[:end], term[end+1:]
+			}
+		} else {
+			end := strings.IndexFunc(text, unicode.IsSpace)
+			if end < 0 {
+				end = len(text)
+			}
+			term, text = text[:end], text[end:]
+			if !excluded && strings.EqualFold(term, "or") {
+				addAlternative()
+				continue
+			}
+		}
+		if !hasWords(term) {
+			continue
+		}
+		if excluded {
+			exclude = append(exclude, quoteTerm(term))
+		} else {
+			include = append(include, quoteTerm(term))
+		}
+	}
+	addAlternative()
+	return strings.Join(alternatives, " OR ")
+}
+
+// hasWords tells whether the text has a letter or a digit, other characters are not indexed.
+func hasWords(text string) bool {
+	return strings.IndexFunc(text, isWordRune) >= 0
+}
+
+// isWordRune tells whether the rune is a part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// quoteTerm makes an FTS5 string of the term, the tokenizer splits it into a phrase of words.
+func quoteTerm(term string) string {
+	return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+}
+
+// headline takes the headline from the lyrics highlighted by FTS5. It is the couplet with the most marks,
+// the first one of equal couplets, or the beginning of the lyrics if no couplet has a mark.
+// Every word of a matched phrase is marked separately like ts_headline does, HTML of the lyrics is escaped.
+func headline(marked string) string {
+	best, bestCount := "", 0
+	for _, couplet := range strings.Split(marked, "\n\n") {
+		if count := strings.Count(couplet, markStart); count > bestCount {
+			best, bestCount = couplet, count
+		}
+	}
+	if bestCount == 0 {
+		best = firstWords(marked, lyricsHeadlineWords)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range markedText.FindAllStringSubmatchIndex(best, -1) {
+		b.WriteString(escapeText(best[last:match[0]]))
+		b.WriteString(markWords(best[match[2]:match[3]]))
+		last = match[1]
+	}
+	b.WriteString(escapeText(best[last:]))
+	return b.String()
+}
+
+// escapeText escapes HTML of the text and drops marks left by a phrase which spans couplets.
+func escapeText(text string) string {
+	text = strings.NewReplacer(markStart, "", markEnd, "").Replace(text)
+	return entities.EscapeHeadline(text)
+}
+
+// markWords wraps every word of the text in <mark></mark>, HTML between words is escaped.
+func markWords(text string) string {
+	var b strings.Builder
+	inWord := false
+	for _, r := range text {
+		if isWordRune(r) != inWord {
+			inWord = !inWord
+			if inWord {
+				b.WriteString("<mark>")
+			} else {
+				b.WriteString("</mark>")
+			}
+		}
+		b.WriteString(escapeText(string(r)))
+	}
+	if inWord {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
+
+// firstWords returns the text up to the end of its nth word.
+func firstWords(text string, n int) string {
+	words, inWord := 0, false
+	for i, r := range text {
+		if isWordRune(r) == inWord {
+			continue
+		}
+		inWord = !inWord
+		if !inWord {
+			words++
+			if words == n {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
@@ -0,0 +1,127 @@
+package gormstorage
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+)
+
+// albumsWithGroup returns an albums query which also selects the name of the album`s group.
+func (g *GormDB) albumsWithGroup(ctx context.Context) *gorm.DB {
+	return g.db.WithContext(ctx).Model(&entities.Album{}).
+		Select("albums.*, groups.name AS \"group\"").
+		Joins("LEFT JOIN groups ON groups.id = albums.group_id")
+}
+
+// SaveAlbum saves a new album and returns its ID.
+// If the album has no GroupID, the group is resolved by its name and created when it does not exist yet.
+func (g *GormDB) SaveAlbum(ctx context.Context, album entities.Album) (uint64, error) {
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if album.GroupID == 0 {
+			group, err := g.resolveGroup(tx, album.Group)
+			if err != nil {
+				return err
+			}
+			album.GroupID = group.ID
+		}
+		return tx.Create(&album).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return album.ID, nil
+}
+
+// GetAlbum returns the album by its ID.
+func (g *GormDB) GetAlbum(ctx context.Context, id uint64) (entities.Album, error) {
+	var album entities.Album
+	err := g.albumsWithGroup(ctx).Where("albums.id = ?", id).First(&album).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Album{}, dberrors.NewNotFoundErr()
+	}
+	return album, err
+}
+
+// GetAlbumList returns list of albums.
+func (g *GormDB) GetAlbumList(ctx context.Context, filter entities.Album, offset int, limit int) ([]entities.Album, error) {
+	var albums []entities.Album
+	query := g.albumsWithGroup(ctx)
+
+	//filter
+	if filter.Title != "" {
+		query = query.Where(g.dialect.ILike("albums.title"), "%"+filter.Title+"%")
+	}
+	if filter.GroupID != 0 {
+		query = query.Where("albums.group_id = ?", filter.GroupID)
+	}
+	if filter.Group != "" {
+		query = query.Where(g.dialect.ILike("groups.name"), "%"+filter.Group+"%")
+	}
+	if filter.ReleaseDate != "" {
+		query = query.Where("albums.release_date = ?", filter.ReleaseDate)
+	}
+
+	// get albums
+	err := query.Order("albums.id").Offset(offset).Limit(limit).Find(&albums).Error
+	if len(albums) == 0 {
+		return albums, dberrors.NewNotFoundErr()
+	}
+	return albums, err
+}
+
+// GetAlbumTracks returns songs of the album ordered by disc and track numbers.
+// An existing album without songs gives an empty list.
+func (g *GormDB) GetAlbumTracks(ctx context.Context, id uint64) ([]entities.Song, error) {
+	var exists int64
+	err := g.db.WithContext(ctx).Model(&entities.Album{}).Where("id = ?", id).Count(&exists).Error
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	songs := []entities.Song{}
+	err = g.songsWithGroup(ctx).
+		Where("songs.album_id = ?", id).
+		Order("songs.disc_number, songs.track_number, songs.id").
+		Find(&songs).Error
+	return songs, err
+}
+
+// RemoveAlbum removes the album. Songs of the album are kept and lose their album.
+func (g *GormDB) RemoveAlbum(ctx context.Context, id uint64) error {
+	tx := g.db.WithContext(ctx).Delete(&entities.Album{}, id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return dberrors.NewNotFoundErr()
+	}
+	return nil
+}
+
+// UpdateAlbum updates the album.
+// A non-empty group name without GroupID moves the album to that group, creating it if needed.
+func (g *GormDB) UpdateAlbum(ctx context.Context, album entities.Album) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if album.GroupID == 0 && album.Group != "" {
+			group, err := g.resolveGroup(tx, album.Group)
+			if err != nil {
+				return err
+			}
+			album.GroupID = group.ID
+		}
+
+		tx = tx.Model(&entities.Album{}).Where("id = ?", album.ID).Updates(&album)
+		if tx.Error != nil {
+			return tx.Error
+		}
+		if tx.RowsAffected == 0 {
+			return dberrors.NewNotFoundErr()
+		}
+		return nil
+	})
+}
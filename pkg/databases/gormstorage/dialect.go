@@ -0,0 +1,45 @@
+package gormstorage
+
+import (
+	"gorm.io/gorm"
+	"io/fs"
+	"musiclib/internal/app/entities"
+)
+
+// Dialect is what differs between databases of the same schema. Everything else is plain SQL both of them know.
+type Dialect interface {
+	// ILike returns a case-insensitive LIKE condition on the expression, its param is a pattern escaped by `\`.
+	ILike(expr string) string
+	// HasAlias returns a condition that a group has an alias whose normalized name is its param.
+	HasAlias() string
+	// Date returns the expression, a date or a text like 2006-01-02, as a date comparable with other ones.
+	Date(expr string) string
+
+	// LockRows locks rows of the table the query selects until the end of its transaction.
+	// Rows locked by other transactions are skipped if skipLocked is set, otherwise the query waits for them.
+	LockRows(query *gorm.DB, table string, skipLocked bool) *gorm.DB
+
+	// SearchSongs returns a page of songs matching a full-text query with their ranks and headlines, the best go first.
+	SearchSongs(db *gorm.DB, query entities.SearchQuery) ([]SearchRow, error)
+
+	// WithSimilarity runs fn in a transaction in which Similar conditions use the similarity.
+	WithSimilarity(db *gorm.DB, similarity float64, fn func(tx *gorm.DB) error) error
+	// Similar returns a condition that the trigram similarity of the expressions is at least the similarity
+	// given to WithSimilarity. An expression may be a param.
+	Similar(a, b string, similarity float64) string
+	// Greatest returns the largest value of the expressions, which must not be NULL.
+	Greatest(exprs ...string) string
+
+	// Migrations returns the file system of versioned migration scripts, see dbmigrations.Load.
+	Migrations() (fsys fs.FS, dir string)
+	// WithMigrationsLock runs fn on a connection which holds the migrations lock, so concurrent instances wait for each other.
+	// schema_migrations exists when fn is called.
+	WithMigrationsLock(db *gorm.DB, fn func(conn *gorm.DB) error) error
+}
+
+// SearchRow is a song with its search rank and headline.
+type SearchRow struct {
+	entities.Song `gorm:"embedded"`
+	Rank          float64
+	Headline      string
+}
@@ -0,0 +1,161 @@
+package gormstorage
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+)
+
+// duplicatePairsSQL selects the pairs of a page of sets of likely duplicates. A pair is two songs of a group which are
+// not in the trash and whose normalized names meet the Similar condition put in place of %s. A set is named
+// by the smallest ID reachable through pairs, sets are paged by these roots, so only pairs of the page are returned.
+// normalize_song_name and similarity are functions of every database.
+const duplicatePairsSQL = `WITH RECURSIVE pairs AS (
+	SELECT a.id AS first_id, b.id AS second_id,
+		similarity(normalize_song_name(a.song), normalize_song_name(b.song)) AS similarity
+	FROM songs a JOIN songs b ON b.group_id = a.group_id AND b.id > a.id
+	WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL AND %s
+), edges AS (
+	SELECT first_id AS id, second_id AS other FROM pairs
+	UNION SELECT second_id, first_id FROM pairs
+), reach (id, root) AS (
+	SELECT id, id FROM edges
+	UNION SELECT reach.id, edges.other FROM reach JOIN edges ON edges.id = reach.root
+), roots AS (
+	SELECT id, MIN(root) AS root FROM reach GROUP BY id
+), page AS (
+	SELECT root FROM (
+		SELECT root, ROW_NUMBER() OVER (ORDER BY root) AS n FROM (SELECT DISTINCT root FROM roots) AS sets
+	) AS numbered
+	WHERE n > @offset AND (@limit < 0 OR n <= @end)
+)
+SELECT pairs.first_id, pairs.second_id, pairs.similarity FROM pairs
+JOIN roots ON roots.id = pairs.first_id
+WHERE roots.root IN (SELECT root FROM page)`
+
+// checkDuplicate returns a conflict if another song of the group which is not in the trash has the same name
+// under entities.NormalizeSongName, the error tells the smallest ID of such songs.
+// A unique index of the schema backs it, but its error would not tell the song.
+func checkDuplicate(tx *gorm.DB, groupID uint64, name string, exceptID uint64) error {
+	var ids []uint64
+	err := tx.Model(&entities.Song{}).
+		Where("group_id = ? AND normalize_song_name(song) = normalize_song_name(?) AND id <> ?", groupID, name, exceptID).
+		Order("id").Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		return dberrors.Wrap(dberrors.KindConflict, entities.DuplicateSongError{ID: ids[0]})
+	}
+	return nil
+}
+
+// GetDuplicateSongs returns a page of sets of likely duplicates: songs of a group whose normalized names
+// have at least the given trigram similarity. Sets go in the order of their smallest IDs.
+func (g *GormDB) GetDuplicateSongs(ctx context.Context, similarity float64, offset int, limit int) ([]entities.DuplicateSongs, error) {
+	var pairs []entities.DuplicatePair
+	err := g.dialect.WithSimilarity(g.db.WithContext(ctx), similarity, func(tx *gorm.DB) error {
+		similar := g.dialect.Similar("normalize_song_name(b.song)", "normalize_song_name(a.song)", similarity)
+		offset = max(offset, 0)
+		params := map[string]any{"offset": offset, "limit": limit, "end": offset + limit}
+		return tx.Raw(fmt.Sprintf(duplicatePairsSQL, similar), params).Scan(&pairs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	sets := entities.ClusterDuplicates(pairs)
+	if len(sets) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	var ids []uint64
+	for _, set := range sets {
+		ids = append(ids, set.IDs...)
+	}
+	var songs []entities.Song
+	err = g.songsWithGroup(ctx).Where("songs.id IN ?", ids).Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint64]entities.Song, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+	}
+
+	duplicates := make([]entities.DuplicateSongs, 0, len(sets))
+	for _, set := range sets {
+		setSongs := make([]entities.Song, 0, len(set.IDs))
+		for _, id := range set.IDs {
+			if song, ok := byID[id]; ok {
+				setSongs = append(setSongs, song)
+			}
+		}
+		duplicates = append(duplicates, entities.NewDuplicateSongs(setSongs, set.Similarity))
+	}
+	return duplicates, nil
+}
+
+// MergeSongs merges metadata of the duplicates into the song (see entities.Song.MergeDuplicates)
+// and moves the duplicates to the trash in one transaction. They must be other songs of its group which are not in the trash.
+// A song flagged as a legacy duplicate by a migration which has become unique is covered by the unique index again.
+// The merged song is returned.
+func (g *GormDB) MergeSongs(ctx context.Context, id uint64, duplicateIDs []uint64) (entities.Song, error) {
+	duplicateIDs = slices.Compact(slices.Sorted(slices.Values(duplicateIDs)))
+	var merged entities.Song
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := g.lockSong(tx, id)
+		if err != nil {
+			return err
+		}
+		duplicates := make([]entities.Song, 0, len(duplicateIDs))
+		for _, duplicateID := range duplicateIDs {
+			if duplicateID == id {
+				return dberrors.NewInvalidErr(fmt.Errorf("song %d can not be merged into itself", id))
+			}
+			duplicate, err := g.lockSong(tx, duplicateID)
+			if err != nil {
+				return err
+			}
+			if duplicate.GroupID != before.GroupID {
+				return dberrors.NewInvalidErr(fmt.Errorf("song %d is of another group", duplicateID))
+			}
+			duplicates = append(duplicates, duplicate)
+		}
+
+		err = replaceSongFields(tx, id, before.MergeDuplicates(duplicates))
+		if err != nil {
+			return err
+		}
+		merged, err = recordRevision(tx, entities.RevisionMerge, id, &before)
+		if err != nil {
+			return err
+		}
+		for _, duplicate := range duplicates {
+			err = tx.Delete(&entities.Song{}, duplicate.ID).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Delete(&entities.EnrichmentJob{}, duplicate.ID).Error
+			if err != nil {
+				return err
+			}
+			_, err = recordRevision(tx, entities.RevisionDelete, duplicate.ID, &duplicate)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Exec(`UPDATE songs SET legacy_duplicate = false WHERE id = ? AND legacy_duplicate AND NOT EXISTS (
+			SELECT 1 FROM songs other WHERE other.id <> songs.id AND other.group_id = songs.group_id
+				AND normalize_song_name(other.song) = normalize_song_name(songs.song)
+				AND other.deleted_at IS NULL AND NOT other.legacy_duplicate
+		)`, id).Error
+	})
+	if err != nil {
+		return entities.Song{}, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,128 @@
+package gormstorage
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"time"
+)
+
+// EnqueueEnrichment marks the song as pending and (re)schedules its enrichment job for now.
+// A changed status is a revision of the song.
+func (g *GormDB) EnqueueEnrichment(ctx context.Context, songID uint64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := g.setEnrichmentStatus(tx, songID, entities.EnrichmentPending)
+		if err != nil {
+			return err
+		}
+
+		job := entities.EnrichmentJob{SongID: songID, NextAttemptAt: tx.NowFunc()}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "song_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"attempts": 0, "next_attempt_at": job.NextAttemptAt, "last_error": ""}),
+		}).Create(&job).Error
+	})
+}
+
+// ClaimEnrichmentJob takes the most overdue job and moves its next attempt to the end of the lease.
+// Jobs locked by other workers are skipped, so several instances can share the table.
+// If a worker dies, its job becomes due again when the lease ends.
+func (g *GormDB) ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (entities.EnrichmentJob, error) {
+	var job entities.EnrichmentJob
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//workers poll the table all the time, so an empty one is not logged as an error like First does
+		now := tx.NowFunc()
+		var jobs []entities.EnrichmentJob
+		err := g.dialect.LockRows(tx.Where("next_attempt_at <= ?", now), "enrichment_jobs", true).
+			Order("next_attempt_at, song_id").Limit(1).Find(&jobs).Error
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return dberrors.NewNotFoundErr()
+		}
+
+		job = jobs[0]
+		job.Attempts++
+		job.NextAttemptAt = now.Add(lease)
+		return tx.Model(&job).Updates(map[string]interface{}{"attempts": job.Attempts, "next_attempt_at": job.NextAttemptAt}).Error
+	})
+	if err != nil {
+		return entities.EnrichmentJob{}, err
+	}
+	return job, nil
+}
+
+// CompleteEnrichmentJob fills empty extra data fields of the song and removes its job.
+// Fields which were filled by a user while the job was waiting are kept.
+func (g *GormDB) CompleteEnrichmentJob(ctx context.Context, songID uint64, data entities.ExtraSongData) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		song, err := g.lockSong(tx, songID)
+		if err != nil {
+			return err
+		}
+		before := song
+
+		song.ApplyExtraData(data)
+		err = tx.Model(&song).Select("release_date", "release_date_precision", "release_date_raw", "text", "link", "sources", "enrichment_status").Updates(entities.Song{
+			ReleaseDate:      song.ReleaseDate,
+			ReleaseDateRaw:   song.ReleaseDateRaw,
+			Text:             song.Text,
+			Link:             song.Link,
+			Sources:          song.Sources,
+			EnrichmentStatus: entities.EnrichmentEnriched,
+		}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&entities.EnrichmentJob{}, songID).Error
+		if err != nil {
+			return err
+		}
+		_, err = recordRevision(tx, entities.RevisionUpdate, songID, &before)
+		return err
+	})
+}
+
+// RescheduleEnrichmentJob sets the time of the next attempt of the job.
+func (g *GormDB) RescheduleEnrichmentJob(ctx context.Context, songID uint64, nextAttemptAt time.Time, lastError string) error {
+	tx := g.db.WithContext(ctx).Model(&entities.EnrichmentJob{}).Where("song_id = ?", songID).
+		Updates(map[string]interface{}{"next_attempt_at": nextAttemptAt.UTC(), "last_error": lastError})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return dberrors.NewNotFoundErr()
+	}
+	return nil
+}
+
+// FailEnrichmentJob marks the song as failed and removes its job. A changed status is a revision of the song.
+// The job of a song which has been removed meanwhile is removed too.
+func (g *GormDB) FailEnrichmentJob(ctx context.Context, songID uint64, lastError string) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := g.setEnrichmentStatus(tx, songID, entities.EnrichmentFailed)
+		if err != nil && !errors.Is(err, dberrors.NewNotFoundErr()) {
+			return err
+		}
+		return tx.Delete(&entities.EnrichmentJob{}, songID).Error
+	})
+}
+
+// setEnrichmentStatus changes the enrichment status of the song and records the revision,
+// so the version and the ETag of the song change with it.
+func (g *GormDB) setEnrichmentStatus(tx *gorm.DB, songID uint64, status entities.EnrichmentStatus) error {
+	before, err := g.lockSong(tx, songID)
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&entities.Song{}).Where("id = ?", songID).Update("enrichment_status", status).Error
+	if err != nil {
+		return err
+	}
+	_, err = recordRevision(tx, entities.RevisionUpdate, songID, &before)
+	return err
+}
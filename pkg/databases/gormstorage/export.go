@@ -0,0 +1,29 @@
+package gormstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+)
+
+// ExportSongs calls fn for every song matching the filter in the order of IDs, an error of fn stops the export.
+// Songs are scanned from a database cursor one by one, so the library is never loaded into memory at once.
+func (g *GormDB) ExportSongs(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error {
+	rows, err := g.filterSongs(g.songsWithGroup(ctx), filter).Order("songs.id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var song entities.Song
+		err = g.db.ScanRows(rows, &song)
+		if err != nil {
+			return err
+		}
+		err = fn(song)
+		if err != nil {
+			return err
+		}
+	}
+	return g.translateError(rows.Err())
+}
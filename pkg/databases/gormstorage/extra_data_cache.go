@@ -0,0 +1,34 @@
+package gormstorage
+
+import (
+	"context"
+	"gorm.io/gorm/clause"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"time"
+)
+
+// GetCachedExtraData returns a not expired cache entry.
+func (g *GormDB) GetCachedExtraData(ctx context.Context, key string) (entities.CachedExtraData, error) {
+	var entry entities.CachedExtraData
+	result := g.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, time.Now().UTC()).Limit(1).Find(&entry)
+	if result.Error != nil {
+		return entities.CachedExtraData{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.CachedExtraData{}, dberrors.NewNotFoundErr()
+	}
+	return entry, nil
+}
+
+// SaveCachedExtraData saves the cache entry, replacing an old one with the same key.
+func (g *GormDB) SaveCachedExtraData(ctx context.Context, entry entities.CachedExtraData) error {
+	entry.ExpiresAt = entry.ExpiresAt.UTC()
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&entry).Error
+}
+
+// PurgeCachedExtraData removes entries which expired before the time and returns their number.
+func (g *GormDB) PurgeCachedExtraData(ctx context.Context, before time.Time) (int64, error) {
+	result := g.db.WithContext(ctx).Where("expires_at < ?", before.UTC()).Delete(&entities.CachedExtraData{})
+	return result.RowsAffected, result.Error
+}
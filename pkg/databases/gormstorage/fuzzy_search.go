@@ -0,0 +1,53 @@
+package gormstorage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+)
+
+// fuzzySearchSongs finds songs whose name or group name is similar to the query, the most similar go first.
+func (g *GormDB) fuzzySearchSongs(ctx context.Context, query entities.SearchQuery) ([]entities.SearchResult, error) {
+	var rows []SearchRow
+	similarity := query.MinSimilarity()
+	err := g.dialect.WithSimilarity(g.db.WithContext(ctx), similarity, func(tx *gorm.DB) error {
+		return WithGroup(tx).
+			Select(`songs.*, groups.name AS "group", `+g.dialect.Greatest("similarity(songs.song, ?)", "COALESCE(similarity(groups.name, ?), 0)")+` AS rank`, query.Text, query.Text).
+			Where(g.dialect.Similar("songs.song", "?", similarity)+" OR "+g.dialect.Similar("groups.name", "?", similarity), query.Text, query.Text).
+			Order("rank DESC, songs.id").
+			Offset(query.Offset).Limit(query.PageLimit()).
+			Find(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	results := make([]entities.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, entities.SearchResult{Song: row.Song, Rank: row.Rank})
+	}
+	return results, nil
+}
+
+// SuggestNames returns song and group names similar to the text, the most similar go first.
+func (g *GormDB) SuggestNames(ctx context.Context, text string, similarity float64, limit int) ([]string, error) {
+	var names []string
+	err := g.dialect.WithSimilarity(g.db.WithContext(ctx), similarity, func(tx *gorm.DB) error {
+		return tx.Raw(`SELECT name FROM (
+				SELECT name, similarity(name, @text) AS score FROM groups WHERE `+g.dialect.Similar("name", "@text", similarity)+`
+				UNION
+				SELECT song, similarity(song, @text) FROM songs WHERE `+g.dialect.Similar("song", "@text", similarity)+` AND deleted_at IS NULL
+			) AS names
+			ORDER BY score DESC, name
+			LIMIT @limit`, map[string]any{"text": text, "limit": limit}).
+			Scan(&names).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
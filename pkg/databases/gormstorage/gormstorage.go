@@ -0,0 +1,305 @@
+// Package gormstorage is a storage of the library in an SQL database with gorm. The schema and the queries are shared
+// by the database backends, gormpostgres and gormsqlite, which give what differs between databases as a Dialect.
+// Time params are passed in UTC, so databases which store times as text compare them right.
+package gormstorage
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"maps"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+)
+
+type GormDB struct {
+	db      *gorm.DB
+	dialect Dialect
+}
+
+// New returns a storage in the database opened by a backend. The dialector of db should translate errors
+// of the database into dberrors, so callers can tell a conflict from a lost connection.
+func New(db *gorm.DB, dialect Dialect) *GormDB {
+	return &GormDB{db: db, dialect: dialect}
+}
+
+// SaveSong saves a new song and returns its ID.
+// If the song has no GroupID, the group is resolved by its name and created when it does not exist yet.
+// A song of the same group and normalized name as a song which is not in the trash is a conflict, see checkDuplicate.
+// A song with the pending enrichment status gets an enrichment job in the same transaction.
+func (g *GormDB) SaveSong(ctx context.Context, song entities.Song) (uint64, error) {
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if song.GroupID == 0 {
+			group, err := g.resolveGroup(tx, song.Group)
+			if err != nil {
+				return err
+			}
+			song.GroupID = group.ID
+		}
+		err := checkDuplicate(tx, song.GroupID, song.Song, 0)
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&song).Error
+		if err != nil {
+			return err
+		}
+		if song.EnrichmentStatus == entities.EnrichmentPending {
+			err = tx.Create(&entities.EnrichmentJob{SongID: song.ID, NextAttemptAt: tx.NowFunc()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		_, err = recordRevision(tx, entities.RevisionCreate, song.ID, nil)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return song.ID, nil
+}
+
+// GetSong returns the song by its ID.
+func (g *GormDB) GetSong(ctx context.Context, id uint64) (entities.Song, error) {
+	var song entities.Song
+	err := g.songsWithGroup(ctx).Where("songs.id = ?", id).First(&song).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Song{}, dberrors.NewNotFoundErr()
+	}
+	return song, err
+}
+
+// GetSongList returns a page of songs in the requested order.
+// Pages are taken by a cursor (keyset pagination), so songs inserted meanwhile do not shift them.
+func (g *GormDB) GetSongList(ctx context.Context, query entities.SongListQuery) (entities.SongPage, error) {
+	page := entities.SongPage{}
+	limit := query.PageLimit()
+	column := g.sortColumn(query.Sort.Field)
+
+	//count
+	if query.WithTotal {
+		var total int64
+		err := g.filterSongs(g.songsWithGroup(ctx), query.Filter).Count(&total).Error
+		if err != nil {
+			return entities.SongPage{}, err
+		}
+		page.Total = &total
+	}
+
+	// get songs, one more than the limit tells whether there is a next page
+	songsQuery := g.filterSongs(g.songsWithGroup(ctx), query.Filter).
+		Select(`songs.*, groups.name AS "group", CAST(` + column.sql + ` AS text) AS sort_key`)
+	backward := query.Cursor != nil && query.Cursor.Backward
+	desc := query.Sort.Desc != backward
+	if query.Cursor == nil {
+		songsQuery = songsQuery.Offset(query.Offset)
+	} else {
+		err := column.checkKey(query.Cursor.Key)
+		if err != nil {
+			return entities.SongPage{}, err
+		}
+		songsQuery = songsQuery.Where(column.after(desc), query.Cursor.Key, query.Cursor.Key, query.Cursor.ID)
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	songsQuery = songsQuery.Order(column.sql + " " + direction + ", songs.id " + direction)
+
+	var rows []songRow
+	err := songsQuery.Limit(limit + 1).Find(&rows).Error
+	if err != nil {
+		return entities.SongPage{}, err
+	}
+	if len(rows) == 0 {
+		return page, dberrors.NewNotFoundErr()
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+	page.Songs = make([]entities.Song, 0, len(rows))
+	for _, row := range rows {
+		page.Songs = append(page.Songs, row.Song)
+	}
+
+	//cursors
+	sort := query.Sort.String()
+	first, last := rows[0], rows[len(rows)-1]
+	if (backward && hasMore) || (!backward && (query.Cursor != nil || query.Offset > 0)) {
+		page.PrevCursor = &entities.Cursor{Sort: sort, Key: first.SortKey, ID: first.ID, Backward: true}
+	}
+	if (!backward && hasMore) || backward {
+		page.NextCursor = &entities.Cursor{Sort: sort, Key: last.SortKey, ID: last.ID}
+	}
+	return page, nil
+}
+
+// GetSongLyrics returns song`s lyrics.
+func (g *GormDB) GetSongLyrics(ctx context.Context, id uint64) (string, error) {
+	var song entities.Song
+	err := g.db.WithContext(ctx).Select("text").First(&song, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", dberrors.NewNotFoundErr()
+	} else if err != nil {
+		return "", err
+	}
+	return song.Text, nil
+}
+
+// RemoveSong moves the song to the trash. Its enrichment job is dropped and scheduled again on restore.
+// A non-zero version must be the current version of the song.
+func (g *GormDB) RemoveSong(ctx context.Context, id uint64, version int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := g.lockSong(tx, id)
+		if err != nil {
+			return err
+		}
+		if version != 0 && version != before.Version {
+			return dberrors.NewVersionMismatchErr()
+		}
+		err = tx.Delete(&entities.Song{}, id).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&entities.EnrichmentJob{}, id).Error
+		if err != nil {
+			return err
+		}
+		_, err = recordRevision(tx, entities.RevisionDelete, id, &before)
+		return err
+	})
+}
+
+// UpdateSong updates the song.
+// A non-empty group name without GroupID moves the song to that group, creating it if needed.
+// A non-zero Version must be the current version of the song.
+func (g *GormDB) UpdateSong(ctx context.Context, song entities.Song) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if song.GroupID == 0 && song.Group != "" {
+			group, err := g.resolveGroup(tx, song.Group)
+			if err != nil {
+				return err
+			}
+			song.GroupID = group.ID
+		}
+		before, err := g.lockSong(tx, song.ID)
+		if err != nil {
+			return err
+		}
+		if song.Version != 0 && song.Version != before.Version {
+			return dberrors.NewVersionMismatchErr()
+		}
+		song.Version = 0
+		groupID, name := before.GroupID, before.Song
+		if song.GroupID != 0 {
+			groupID = song.GroupID
+		}
+		if song.Song != "" {
+			name = song.Song
+		}
+		if !before.IsSameSong(groupID, name) {
+			err = checkDuplicate(tx, groupID, name, song.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		tx2 := tx.Model(&entities.Song{}).Where("id = ?", song.ID).Updates(&song)
+		if tx2.Error != nil {
+			return tx2.Error
+		}
+		// Проверяем количество затронутых строк
+		if tx2.RowsAffected == 0 {
+			return dberrors.NewNotFoundErr()
+		}
+
+		_, err = recordRevision(tx, entities.RevisionUpdate, song.ID, &before)
+		return err
+	})
+}
+
+// ReplaceSong replaces all fields of the song a client can change, empty fields are cleared.
+// The song is moved to the group of its name if it has no GroupID. Sources of changed extra data are forgotten.
+// A non-zero Version must be the current version of the song.
+func (g *GormDB) ReplaceSong(ctx context.Context, song entities.Song) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if song.GroupID == 0 {
+			group, err := g.resolveGroup(tx, song.Group)
+			if err != nil {
+				return err
+			}
+			song.GroupID = group.ID
+		}
+		before, err := g.lockSong(tx, song.ID)
+		if err != nil {
+			return err
+		}
+		if song.Version != 0 && song.Version != before.Version {
+			return dberrors.NewVersionMismatchErr()
+		}
+		if !before.IsSameSong(song.GroupID, song.Song) {
+			err = checkDuplicate(tx, song.GroupID, song.Song, song.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		song.Sources = maps.Clone(before.Sources)
+		song.ForgetChangedSources(before)
+		err = replaceSongFields(tx, song.ID, song)
+		if err != nil {
+			return err
+		}
+		_, err = recordRevision(tx, entities.RevisionUpdate, song.ID, &before)
+		return err
+	})
+}
+
+// editableSongColumns are columns of song fields a client can change.
+var editableSongColumns = []string{"song", "group_id", "album_id", "disc_number", "track_number", "release_date", "release_date_precision", "release_date_raw", "text", "link", "sources"}
+
+// replaceSongFields writes all editable fields of the song including empty ones.
+func replaceSongFields(tx *gorm.DB, id uint64, song entities.Song) error {
+	return tx.Model(&entities.Song{ID: id}).
+		Select(editableSongColumns).
+		Updates(entities.Song{
+			Song:           song.Song,
+			GroupID:        song.GroupID,
+			AlbumID:        song.AlbumID,
+			DiscNumber:     song.DiscNumber,
+			TrackNumber:    song.TrackNumber,
+			ReleaseDate:    song.ReleaseDate,
+			ReleaseDateRaw: song.ReleaseDateRaw,
+			Text:           song.Text,
+			Link:           song.Link,
+			Sources:        song.Sources,
+		}).Error
+}
+
+// songsWithGroup returns a songs query which also selects the name of the song`s group.
+func (g *GormDB) songsWithGroup(ctx context.Context) *gorm.DB {
+	return WithGroup(g.db.WithContext(ctx))
+}
+
+// WithGroup is songsWithGroup inside of a transaction, dialects use it for their queries of songs.
+func WithGroup(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&entities.Song{}).
+		Select("songs.*, groups.name AS \"group\"").
+		Joins("LEFT JOIN groups ON groups.id = songs.group_id")
+}
+
+// translateError translates an error of the database which was not returned by a statement, like the one of a rows cursor,
+// with the dialector of the backend.
+func (g *GormDB) translateError(err error) error {
+	translator, ok := g.db.Dialector.(gorm.ErrorTranslator)
+	if err == nil || !ok {
+		return err
+	}
+	return translator.Translate(err)
+}
@@ -0,0 +1,186 @@
+package gormstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"strings"
+)
+
+// resolveGroup finds a group by its name or alias and creates a new one if nothing was found.
+func (g *GormDB) resolveGroup(tx *gorm.DB, name string) (entities.Group, error) {
+	normalized := entities.NormalizeGroupName(name)
+	if normalized == "" {
+		return entities.Group{}, dberrors.NewInvalidErr(fmt.Errorf("group name is empty"))
+	}
+
+	group, err := g.findGroupByName(tx, normalized)
+	if !errors.Is(err, dberrors.NewNotFoundErr()) {
+		return group, err
+	}
+
+	group = entities.Group{
+		Name:           normalizeSpaces(name),
+		NormalizedName: normalized,
+	}
+	err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&group).Error
+	if err != nil {
+		return entities.Group{}, fmt.Errorf("failed to create group: %w", err)
+	}
+	if group.ID != 0 {
+		return group, nil
+	}
+
+	//someone else has just created the same group
+	return g.findGroupByName(tx, normalized)
+}
+
+// findGroupByName looks for a group whose normalized name or one of aliases equals to the normalized name.
+func (g *GormDB) findGroupByName(tx *gorm.DB, normalized string) (entities.Group, error) {
+	var group entities.Group
+	err := tx.Where("normalized_name = ?", normalized).
+		Or(g.dialect.HasAlias(), normalized).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "normalized_name = ? DESC, id", Vars: []interface{}{normalized}, WithoutParentheses: true}}).
+		First(&group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Group{}, dberrors.NewNotFoundErr()
+	}
+	return group, err
+}
+
+// normalizeSpaces trims a name and collapses repeated whitespaces.
+func normalizeSpaces(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// checkGroupNames returns a conflict if another group has the name or one of aliases of the group
+// as its name or alias, so every name leads to a single group.
+func (g *GormDB) checkGroupNames(tx *gorm.DB, group entities.Group) error {
+	var names []string
+	if group.NormalizedName != "" {
+		names = append(names, group.NormalizedName)
+	}
+	for _, alias := range group.Aliases {
+		if normalized := entities.NormalizeGroupName(alias); normalized != "" {
+			names = append(names, normalized)
+		}
+	}
+	for _, name := range names {
+		var ids []uint64
+		err := tx.Model(&entities.Group{}).
+			Where("(normalized_name = ? OR "+g.dialect.HasAlias()+") AND id <> ?", name, name, group.ID).
+			Limit(1).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			return dberrors.NewConflictErr()
+		}
+	}
+	return nil
+}
+
+// SaveGroup saves a new group and returns its ID.
+func (g *GormDB) SaveGroup(ctx context.Context, group entities.Group) (uint64, error) {
+	group.Name = normalizeSpaces(group.Name)
+	group.NormalizedName = entities.NormalizeGroupName(group.Name)
+
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := g.checkGroupNames(tx, group)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&group).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return group.ID, nil
+}
+
+// GetGroup returns the group by its ID.
+func (g *GormDB) GetGroup(ctx context.Context, id uint64) (entities.Group, error) {
+	var group entities.Group
+	err := g.db.WithContext(ctx).First(&group, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Group{}, dberrors.NewNotFoundErr()
+	}
+	return group, err
+}
+
+// GetGroupList returns list of groups.
+func (g *GormDB) GetGroupList(ctx context.Context, filter entities.Group, offset int, limit int) ([]entities.Group, error) {
+	var groups []entities.Group
+	query := g.db.WithContext(ctx).Model(&entities.Group{})
+
+	//filter
+	if filter.Name != "" {
+		query = query.Where(g.dialect.ILike("name"), "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.Country != "" {
+		query = query.Where(g.dialect.ILike("country"), escapeLike(filter.Country))
+	}
+	if filter.FormedYear != 0 {
+		query = query.Where("formed_year = ?", filter.FormedYear)
+	}
+
+	// get groups
+	err := query.Order("id").Offset(offset).Limit(limit).Find(&groups).Error
+	if len(groups) == 0 {
+		return groups, dberrors.NewNotFoundErr()
+	}
+	return groups, err
+}
+
+// RemoveGroup removes the group. Groups which still have songs or albums can not be removed,
+// songs in the trash count too, as they may be restored.
+func (g *GormDB) RemoveGroup(ctx context.Context, id uint64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&entities.Song{}, &entities.Album{}} {
+			var references int64
+			err := tx.Unscoped().Model(model).Where("group_id = ?", id).Count(&references).Error
+			if err != nil {
+				return err
+			}
+			if references > 0 {
+				return dberrors.NewConflictErr()
+			}
+		}
+
+		tx = tx.Delete(&entities.Group{}, id)
+		if tx.Error != nil {
+			return tx.Error
+		}
+		if tx.RowsAffected == 0 {
+			return dberrors.NewNotFoundErr()
+		}
+		return nil
+	})
+}
+
+// UpdateGroup updates the group.
+func (g *GormDB) UpdateGroup(ctx context.Context, group entities.Group) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if group.Name != "" {
+			group.Name = normalizeSpaces(group.Name)
+			group.NormalizedName = entities.NormalizeGroupName(group.Name)
+		}
+		err := g.checkGroupNames(tx, group)
+		if err != nil {
+			return err
+		}
+
+		tx = tx.Model(&entities.Group{}).Where("id = ?", group.ID).Updates(&group)
+		if tx.Error != nil {
+			return tx.Error
+		}
+		if tx.RowsAffected == 0 {
+			return dberrors.NewNotFoundErr()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package gormstorage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"slices"
+	"strconv"
+)
+
+// ImportSongs saves the songs in one transaction and returns their outcomes in the same order.
+// Groups are resolved by name like in SaveSong. A song of the same group and name as a song in the library
+// or earlier in the batch is not saved. Pending songs get enrichment jobs.
+func (g *GormDB) ImportSongs(ctx context.Context, songs []entities.Song) ([]entities.ImportedSong, error) {
+	songs = slices.Clone(songs)
+	imported := make([]entities.ImportedSong, len(songs))
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//resolve groups
+		groups := map[string]uint64{}
+		var groupIDs []uint64
+		for i := range songs {
+			if songs[i].GroupID == 0 {
+				normalized := entities.NormalizeGroupName(songs[i].Group)
+				id, ok := groups[normalized]
+				if !ok {
+					group, err := g.resolveGroup(tx, songs[i].Group)
+					if err != nil {
+						return err
+					}
+					id = group.ID
+					groups[normalized] = id
+				}
+				songs[i].GroupID = id
+			}
+			if !slices.Contains(groupIDs, songs[i].GroupID) {
+				groupIDs = append(groupIDs, songs[i].GroupID)
+			}
+		}
+
+		//songs already in the library
+		var existing []entities.Song
+		err := tx.Select("id, group_id, song").Where("group_id IN ?", groupIDs).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+		known := make(map[string]uint64, len(existing))
+		for _, song := range existing {
+			known[importKey(song)] = song.ID
+		}
+
+		//insert new songs, a repeated song of the batch gets the ID of its first occurrence once it is created
+		var created []int
+		batch := map[string]int{}
+		repeated := map[int]int{}
+		for i, song := range songs {
+			key := importKey(song)
+			if id, ok := known[key]; ok {
+				imported[i] = entities.ImportedSong{ID: id, Existed: true}
+				continue
+			}
+			if first, ok := batch[key]; ok {
+				repeated[i] = first
+				continue
+			}
+			batch[key] = i
+			created = append(created, i)
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		newSongs := make([]entities.Song, 0, len(created))
+		for _, i := range created {
+			newSongs = append(newSongs, songs[i])
+		}
+		err = tx.Create(&newSongs).Error
+		if err != nil {
+			return err
+		}
+
+		var jobs []entities.EnrichmentJob
+		for n, i := range created {
+			song := newSongs[n]
+			imported[i] = entities.ImportedSong{ID: song.ID}
+			if song.EnrichmentStatus == entities.EnrichmentPending {
+				jobs = append(jobs, entities.EnrichmentJob{SongID: song.ID, NextAttemptAt: tx.NowFunc()})
+			}
+			_, err = recordRevision(tx, entities.RevisionCreate, song.ID, nil)
+			if err != nil {
+				return err
+			}
+		}
+		for i, first := range repeated {
+			imported[i] = entities.ImportedSong{ID: imported[first].ID, Existed: true}
+		}
+		if len(jobs) > 0 {
+			err = tx.Create(&jobs).Error
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// importKey identifies a song by its group and normalized name.
+func importKey(song entities.Song) string {
+	return strconv.FormatUint(song.GroupID, 10) + "\n" + entities.NormalizeSongName(song.Song)
+}
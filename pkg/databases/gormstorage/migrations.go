@@ -0,0 +1,115 @@
+package gormstorage
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"musiclib/pkg/databases/dbmigrations"
+	"time"
+)
+
+// schemaMigration is a row of schema_migrations.
+type schemaMigration struct {
+	Version   int `gorm:"primary_key;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// MigrateUp applies all pending migrations and returns them.
+func (g *GormDB) MigrateUp(ctx context.Context) ([]dbmigrations.Status, error) {
+	var applied []dbmigrations.Status
+	err := g.withMigrations(ctx, func(conn *gorm.DB, migrations []dbmigrations.Migration, done map[int]schemaMigration) error {
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			row := schemaMigration{Version: m.Version, Name: m.Name}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				_, err := tx.Statement.ConnPool.ExecContext(ctx, m.Up)
+				if err != nil {
+					return err
+				}
+				row.AppliedAt = tx.NowFunc()
+				return tx.Create(&row).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d `%v`: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, dbmigrations.Status{Version: m.Version, Name: m.Name, AppliedAt: &row.AppliedAt})
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown rolls back the given number of the last applied migrations and returns them.
+func (g *GormDB) MigrateDown(ctx context.Context, steps int) ([]dbmigrations.Status, error) {
+	var rolledBack []dbmigrations.Status
+	err := g.withMigrations(ctx, func(conn *gorm.DB, migrations []dbmigrations.Migration, done map[int]schemaMigration) error {
+		for i := len(migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			m := migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				_, err := tx.Statement.ConnPool.ExecContext(ctx, m.Down)
+				if err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, m.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %d `%v`: %w", m.Version, m.Name, err)
+			}
+			rolledBack = append(rolledBack, dbmigrations.Status{Version: m.Version, Name: m.Name})
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// MigrationStatus returns all migrations known to the binary ordered by version.
+func (g *GormDB) MigrationStatus(ctx context.Context) ([]dbmigrations.Status, error) {
+	var statuses []dbmigrations.Status
+	err := g.withMigrations(ctx, func(_ *gorm.DB, migrations []dbmigrations.Migration, done map[int]schemaMigration) error {
+		for _, m := range migrations {
+			status := dbmigrations.Status{Version: m.Version, Name: m.Name}
+			if row, ok := done[m.Version]; ok {
+				status.AppliedAt = &row.AppliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withMigrations runs fn holding the migrations lock of the dialect, so concurrent instances wait for each other.
+// fn gets the known migrations and the applied ones by version.
+// A database migrated by a newer binary is refused, its schema is unknown to this one.
+func (g *GormDB) withMigrations(ctx context.Context, fn func(conn *gorm.DB, migrations []dbmigrations.Migration, done map[int]schemaMigration) error) error {
+	fsys, dir := g.dialect.Migrations()
+	migrations, err := dbmigrations.Load(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return g.dialect.WithMigrationsLock(g.db.WithContext(ctx), func(conn *gorm.DB) error {
+		var rows []schemaMigration
+		err := conn.Order("version").Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+		done := make(map[int]schemaMigration, len(rows))
+		for _, row := range rows {
+			done[row.Version] = row
+		}
+		if len(rows) > 0 {
+			err = dbmigrations.CheckVersion(migrations, rows[len(rows)-1].Version)
+			if err != nil {
+				return err
+			}
+		}
+		return fn(conn, migrations, done)
+	})
+}
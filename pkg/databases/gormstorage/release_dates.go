@@ -0,0 +1,22 @@
+package gormstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+)
+
+// UnparsedReleaseDates returns release dates which could not be parsed by song IDs.
+func (g *GormDB) UnparsedReleaseDates(ctx context.Context) (map[uint64]string, error) {
+	var songs []entities.Song
+	err := g.db.WithContext(ctx).Select("id", "release_date_raw").
+		Where("release_date IS NULL AND COALESCE(release_date_raw, '') <> ''").
+		Order("id").Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	dates := make(map[uint64]string, len(songs))
+	for _, song := range songs {
+		dates[song.ID] = song.ReleaseDateRaw
+	}
+	return dates, nil
+}
@@ -0,0 +1,139 @@
+package gormstorage
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+)
+
+// lockSong returns the song with its group name and locks it until the end of the transaction,
+// so revisions of the song are recorded one by one. Unscoped tx finds trashed songs too.
+func (g *GormDB) lockSong(tx *gorm.DB, id uint64) (entities.Song, error) {
+	var song entities.Song
+	err := g.dialect.LockRows(WithGroup(tx), "songs", false).
+		Where("songs.id = ?", id).
+		First(&song).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.Song{}, dberrors.NewNotFoundErr()
+	}
+	return song, err
+}
+
+// recordRevision saves the next revision of the song with changes made since before, nil for a new song.
+// An update which has changed nothing is not recorded. Otherwise the version of a changed song is incremented.
+// The state of the song after the change is returned.
+// The actor and the request ID are taken from the audit info of the transaction context.
+func recordRevision(tx *gorm.DB, action entities.RevisionAction, songID uint64, before *entities.Song) (entities.Song, error) {
+	var after entities.Song
+	err := WithGroup(tx.Unscoped()).Where("songs.id = ?", songID).First(&after).Error
+	if err != nil {
+		return entities.Song{}, err
+	}
+	changes := entities.DiffSongs(before, &after)
+	if action == entities.RevisionUpdate && len(changes) == 0 {
+		return after, nil
+	}
+	if before != nil {
+		err = tx.Unscoped().Model(&entities.Song{}).Where("id = ?", songID).UpdateColumn("version", gorm.Expr("version + 1")).Error
+		if err != nil {
+			return entities.Song{}, err
+		}
+		after.Version++
+	}
+
+	var last int
+	err = tx.Model(&entities.SongRevision{}).Select("COALESCE(MAX(revision), 0)").Where("song_id = ?", songID).Scan(&last).Error
+	if err != nil {
+		return entities.Song{}, err
+	}
+	info := entities.AuditInfoFromContext(tx.Statement.Context)
+	return after, tx.Create(&entities.SongRevision{
+		SongID:    songID,
+		Revision:  last + 1,
+		Action:    action,
+		Actor:     info.Actor,
+		RequestID: info.RequestID,
+		Changes:   changes,
+		Song:      &after,
+	}).Error
+}
+
+// GetSongHistory returns revisions of the song, the latest go first.
+// Songs in the trash have a history too, a song without revisions gives an empty list.
+func (g *GormDB) GetSongHistory(ctx context.Context, id uint64, offset int, limit int) ([]entities.SongRevision, error) {
+	var exists int64
+	err := g.db.WithContext(ctx).Unscoped().Model(&entities.Song{}).Where("id = ?", id).Count(&exists).Error
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	revisions := []entities.SongRevision{}
+	err = g.db.WithContext(ctx).
+		Where("song_id = ?", id).
+		Order("revision DESC").
+		Offset(offset).Limit(limit).
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// RevertSong brings the song back to its state after the revision and records it as a new revision.
+// A group or an album which was removed since is not restored: the group is found by its name again and the album is unset.
+func (g *GormDB) RevertSong(ctx context.Context, id uint64, revision int) (entities.Song, error) {
+	var reverted entities.Song
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := g.lockSong(tx, id)
+		if err != nil {
+			return err
+		}
+		var rev entities.SongRevision
+		err = tx.Where("song_id = ? AND revision = ?", id, revision).First(&rev).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && rev.Song == nil) {
+			return dberrors.NewNotFoundErr()
+		} else if err != nil {
+			return err
+		}
+
+		state := *rev.Song
+		var groups int64
+		err = tx.Model(&entities.Group{}).Where("id = ?", state.GroupID).Count(&groups).Error
+		if err != nil {
+			return err
+		}
+		if groups == 0 {
+			group, err := g.resolveGroup(tx, state.Group)
+			if err != nil {
+				return err
+			}
+			state.GroupID = group.ID
+		}
+		if state.AlbumID != nil {
+			var albums int64
+			err = tx.Model(&entities.Album{}).Where("id = ?", *state.AlbumID).Count(&albums).Error
+			if err != nil {
+				return err
+			}
+			if albums == 0 {
+				state.AlbumID = nil
+			}
+		}
+		if !before.IsSameSong(state.GroupID, state.Song) {
+			err = checkDuplicate(tx, state.GroupID, state.Song, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = replaceSongFields(tx, id, state)
+		if err != nil {
+			return err
+		}
+		reverted, err = recordRevision(tx, entities.RevisionRevert, id, &before)
+		return err
+	})
+	return reverted, err
+}
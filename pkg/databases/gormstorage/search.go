@@ -0,0 +1,30 @@
+package gormstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+)
+
+// SearchSongs finds songs by their names and lyrics, the best matching songs go first.
+// The headline is the best matching couplet, or a part of the lyrics if the match spans couplets or is only in the name.
+// How a query is matched and ranked is up to the dialect. The fuzzy mode searches only song and group names, see fuzzySearchSongs.
+func (g *GormDB) SearchSongs(ctx context.Context, query entities.SearchQuery) ([]entities.SearchResult, error) {
+	if query.Mode == entities.SearchFuzzy {
+		return g.fuzzySearchSongs(ctx, query)
+	}
+
+	rows, err := g.dialect.SearchSongs(g.db.WithContext(ctx), query)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	results := make([]entities.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, entities.SearchResult{Song: row.Song, Rank: row.Rank, Headline: row.Headline})
+	}
+	return results, nil
+}
@@ -0,0 +1,132 @@
+package gormstorage
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortColumn is an SQL expression songs can be ordered by. It must not be NULL, so keyset conditions work.
+type sortColumn struct {
+	sql string
+	// key is the SQL of a cursor key param, it casts the key to the type of the column.
+	key string
+	// keyType is the type of keys, one of "bigint", "text" and "date".
+	keyType string
+}
+
+// sortColumn returns the column of an entities.SongSortFields field, songs are ordered by ID by default.
+func (g *GormDB) sortColumn(field string) sortColumn {
+	switch field {
+	case "song":
+		return sortColumn{sql: "COALESCE(songs.song, '')", key: "CAST(? AS text)", keyType: "text"}
+	case "group":
+		return sortColumn{sql: "COALESCE(groups.name, '')", key: "CAST(? AS text)", keyType: "text"}
+	case "release_date":
+		return sortColumn{sql: "COALESCE(" + g.dialect.Date("songs.release_date") + ", " + g.dialect.Date("'0001-01-01'") + ")", key: g.dialect.Date("?"), keyType: "date"}
+	case "disc_number":
+		return sortColumn{sql: "COALESCE(songs.disc_number, 0)", key: "CAST(? AS bigint)", keyType: "bigint"}
+	case "track_number":
+		return sortColumn{sql: "COALESCE(songs.track_number, 0)", key: "CAST(? AS bigint)", keyType: "bigint"}
+	default:
+		return sortColumn{sql: "songs.id", key: "CAST(? AS bigint)", keyType: "bigint"}
+	}
+}
+
+// checkKey returns an error for a cursor key which is not a value of the column.
+// Some databases cast any text to a number or keep it as is, so a broken key would silently give a wrong page.
+func (c sortColumn) checkKey(key string) error {
+	var err error
+	switch c.keyType {
+	case "bigint":
+		_, err = strconv.ParseInt(key, 10, 64)
+	case "date":
+		_, err = time.Parse(time.DateOnly, key)
+	}
+	if err != nil {
+		return dberrors.NewInvalidErr(fmt.Errorf("invalid cursor key `%v`", key))
+	}
+	return nil
+}
+
+// after returns a condition of songs which follow a cursor (key, id) in the order.
+// Its params are the key, the key again and the id.
+func (c sortColumn) after(desc bool) string {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return "(" + c.sql + " " + op + " " + c.key + " OR (" + c.sql + " = " + c.key + " AND songs.id " + op + " ?))"
+}
+
+// songRow is a song with the value of its sort column.
+type songRow struct {
+	entities.Song `gorm:"embedded"`
+	SortKey       string
+}
+
+// filterSongs adds conditions of the filter to a songsWithGroup query. All values are passed as params.
+func (g *GormDB) filterSongs(query *gorm.DB, filter entities.SongFilter) *gorm.DB {
+	if len(filter.IDs) > 0 {
+		query = query.Where("songs.id IN ?", filter.IDs)
+	}
+	if filter.Song != "" {
+		query = g.matchText(query, "songs.song", filter.Song, filter.SongMatch)
+	}
+	if filter.Group != "" {
+		query = g.matchText(query, "groups.name", filter.Group, filter.GroupMatch)
+	}
+	if len(filter.GroupIDs) > 0 {
+		query = query.Where("songs.group_id IN ?", filter.GroupIDs)
+	}
+	if len(filter.Groups) > 0 {
+		names := make([]string, 0, len(filter.Groups))
+		for _, name := range filter.Groups {
+			names = append(names, entities.NormalizeGroupName(name))
+		}
+		query = query.Where("groups.normalized_name IN ?", names)
+	}
+	if filter.ReleaseDate != nil {
+		query = query.Where(g.dialect.Date("songs.release_date")+" BETWEEN "+g.dialect.Date("?")+" AND "+g.dialect.Date("?"),
+			sqlDate(filter.ReleaseDate.Date), sqlDate(filter.ReleaseDate.Last()))
+	}
+	if filter.ReleaseDateFrom != nil {
+		query = query.Where(g.dialect.Date("songs.release_date")+" >= "+g.dialect.Date("?"), sqlDate(filter.ReleaseDateFrom.Date))
+	}
+	if filter.ReleaseDateTo != nil {
+		query = query.Where(g.dialect.Date("songs.release_date")+" <= "+g.dialect.Date("?"), sqlDate(filter.ReleaseDateTo.Last()))
+	}
+	if filter.HasLyrics != nil {
+		query = query.Where("(COALESCE(songs.text, '') <> '') = ?", *filter.HasLyrics)
+	}
+	if filter.HasLink != nil {
+		query = query.Where("(COALESCE(songs.link, '') <> '') = ?", *filter.HasLink)
+	}
+	return query
+}
+
+// matchText adds a case-insensitive condition on the column, MatchContains is used by default.
+func (g *GormDB) matchText(query *gorm.DB, column, value string, mode entities.MatchMode) *gorm.DB {
+	switch mode {
+	case entities.MatchExact:
+		return query.Where(g.dialect.ILike(column), escapeLike(value))
+	case entities.MatchPrefix:
+		return query.Where(g.dialect.ILike(column), escapeLike(value)+"%")
+	default:
+		return query.Where(g.dialect.ILike(column), "%"+escapeLike(value)+"%")
+	}
+}
+
+// escapeLike escapes wildcards of a LIKE pattern, so they match themselves.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// sqlDate formats a date param, so it is not shifted by a time zone of the connection.
+func sqlDate(date time.Time) string {
+	return date.Format(time.DateOnly)
+}
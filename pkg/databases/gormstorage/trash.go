@@ -0,0 +1,70 @@
+package gormstorage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"time"
+)
+
+// GetTrash returns songs in the trash, the most recently removed go first.
+func (g *GormDB) GetTrash(ctx context.Context, offset int, limit int) ([]entities.TrashedSong, error) {
+	var songs []entities.Song
+	err := g.songsWithGroup(ctx).Unscoped().
+		Where("songs.deleted_at IS NOT NULL").
+		Order("songs.deleted_at DESC, songs.id DESC").
+		Offset(offset).Limit(limit).
+		Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(songs) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	trash := make([]entities.TrashedSong, 0, len(songs))
+	for _, song := range songs {
+		trash = append(trash, entities.TrashedSong{Song: song, DeletedAt: song.DeletedAt.Time})
+	}
+	return trash, nil
+}
+
+// RestoreSong takes the song out of the trash unless the group has got a song of the same name meanwhile.
+// A pending song gets its enrichment job back.
+func (g *GormDB) RestoreSong(ctx context.Context, id uint64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := g.lockSong(tx.Unscoped(), id)
+		if err != nil {
+			return err
+		}
+		if !before.DeletedAt.Valid {
+			return dberrors.NewNotFoundErr()
+		}
+		err = checkDuplicate(tx, before.GroupID, before.Song, id)
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&entities.Song{}).Where("id = ?", id).Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		if before.EnrichmentStatus == entities.EnrichmentPending {
+			err = tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&entities.EnrichmentJob{SongID: id, NextAttemptAt: tx.NowFunc()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		_, err = recordRevision(tx, entities.RevisionRestore, id, &before)
+		return err
+	})
+}
+
+// PurgeTrash removes songs which were moved to the trash before the time for good and returns their number.
+func (g *GormDB) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	tx := g.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before.UTC()).Delete(&entities.Song{})
+	return tx.RowsAffected, tx.Error
+}
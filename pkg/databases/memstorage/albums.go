@@ -0,0 +1,172 @@
+package memstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+)
+
+// albumWithGroup returns the album with the name of its group.
+func (m *MemStorage) albumWithGroup(album entities.Album) entities.Album {
+	album.Group = m.groups[album.GroupID].Name
+	return album
+}
+
+// SaveAlbum saves a new album and returns its ID.
+// If the album has no GroupID, the group is resolved by its name and created when it does not exist yet.
+func (m *MemStorage) SaveAlbum(ctx context.Context, album entities.Album) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.checkReferences(album.GroupID, nil)
+	if err != nil {
+		return 0, err
+	}
+	if album.GroupID == 0 {
+		group, err := m.resolveGroup(album.Group)
+		if err != nil {
+			return 0, err
+		}
+		album.GroupID = group.ID
+	}
+
+	m.lastAlbumID++
+	album.ID, album.Group = m.lastAlbumID, ""
+	m.albums[album.ID] = album
+	return album.ID, nil
+}
+
+// GetAlbum returns the album by its ID.
+func (m *MemStorage) GetAlbum(ctx context.Context, id uint64) (entities.Album, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Album{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	album, ok := m.albums[id]
+	if !ok {
+		return entities.Album{}, dberrors.NewNotFoundErr()
+	}
+	return m.albumWithGroup(album), nil
+}
+
+// GetAlbumList returns list of albums.
+func (m *MemStorage) GetAlbumList(ctx context.Context, filter entities.Album, offset int, limit int) ([]entities.Album, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	albums := []entities.Album{}
+	for _, album := range m.albums {
+		album = m.albumWithGroup(album)
+		if filter.Title != "" && !matchText(album.Title, filter.Title, entities.MatchContains) {
+			continue
+		}
+		if filter.GroupID != 0 && album.GroupID != filter.GroupID {
+			continue
+		}
+		if filter.Group != "" && !matchText(album.Group, filter.Group, entities.MatchContains) {
+			continue
+		}
+		if filter.ReleaseDate != "" && album.ReleaseDate != filter.ReleaseDate {
+			continue
+		}
+		albums = append(albums, album)
+	}
+	slices.SortFunc(albums, func(a, b entities.Album) int {
+		return compareIDs(a.ID, b.ID)
+	})
+
+	start, end := pageBounds(len(albums), offset, limit)
+	albums = albums[start:end]
+	if len(albums) == 0 {
+		return albums, dberrors.NewNotFoundErr()
+	}
+	return albums, nil
+}
+
+// GetAlbumTracks returns songs of the album ordered by disc and track numbers.
+// An existing album without songs gives an empty list.
+func (m *MemStorage) GetAlbumTracks(ctx context.Context, id uint64) ([]entities.Song, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.albums[id]; !ok {
+		return nil, dberrors.NewNotFoundErr()
+	}
+	songs := slices.DeleteFunc(m.liveSongs(), func(song entities.Song) bool {
+		return song.AlbumID == nil || *song.AlbumID != id
+	})
+	slices.SortStableFunc(songs, func(a, b entities.Song) int {
+		if a.DiscNumber != b.DiscNumber {
+			return a.DiscNumber - b.DiscNumber
+		}
+		return a.TrackNumber - b.TrackNumber
+	})
+	return songs, nil
+}
+
+// RemoveAlbum removes the album. Songs of the album are kept and lose their album, songs in the trash too.
+func (m *MemStorage) RemoveAlbum(ctx context.Context, id uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.albums[id]; !ok {
+		return dberrors.NewNotFoundErr()
+	}
+	delete(m.albums, id)
+	for songID, song := range m.songs {
+		if song.AlbumID != nil && *song.AlbumID == id {
+			song.AlbumID = nil
+			m.songs[songID] = song
+		}
+	}
+	return nil
+}
+
+// UpdateAlbum updates non-empty fields of the album.
+// A non-empty group name without GroupID moves the album to that group, creating it if needed.
+func (m *MemStorage) UpdateAlbum(ctx context.Context, album entities.Album) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updated, ok := m.albums[album.ID]
+	if !ok {
+		return dberrors.NewNotFoundErr()
+	}
+	err := m.checkReferences(album.GroupID, nil)
+	if err != nil {
+		return err
+	}
+	if album.GroupID == 0 && album.Group != "" {
+		group, err := m.resolveGroup(album.Group)
+		if err != nil {
+			return err
+		}
+		album.GroupID = group.ID
+	}
+	if album.GroupID != 0 {
+		updated.GroupID = album.GroupID
+	}
+	updateString(&updated.Title, album.Title)
+	updateString(&updated.ReleaseDate, album.ReleaseDate)
+	updateString(&updated.CoverLink, album.CoverLink)
+	m.albums[album.ID] = updated
+	return nil
+}
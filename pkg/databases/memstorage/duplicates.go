@@ -0,0 +1,109 @@
+package memstorage
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+	"time"
+)
+
+// checkDuplicate returns a conflict if another song of the group which is not in the trash has the same name
+// under entities.NormalizeSongName, the error tells the smallest ID of such songs.
+func (m *MemStorage) checkDuplicate(groupID uint64, name string, exceptID uint64) error {
+	normalized := entities.NormalizeSongName(name)
+	var ids []uint64
+	for id, song := range m.songs {
+		if id != exceptID && !song.DeletedAt.Valid && song.GroupID == groupID && entities.NormalizeSongName(song.Song) == normalized {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return dberrors.Wrap(dberrors.KindConflict, entities.DuplicateSongError{ID: slices.Min(ids)})
+}
+
+// GetDuplicateSongs returns a page of sets of likely duplicates: songs of a group whose normalized names
+// have at least the given trigram similarity. Sets go in the order of their smallest IDs.
+func (m *MemStorage) GetDuplicateSongs(ctx context.Context, threshold float64, offset int, limit int) ([]entities.DuplicateSongs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	songs := m.liveSongs()
+	var pairs []entities.DuplicatePair
+	for i, a := range songs {
+		for _, b := range songs[i+1:] {
+			if a.GroupID != b.GroupID {
+				continue
+			}
+			s := similarity(entities.NormalizeSongName(a.Song), entities.NormalizeSongName(b.Song))
+			if s >= threshold {
+				pairs = append(pairs, entities.DuplicatePair{FirstID: a.ID, SecondID: b.ID, Similarity: s})
+			}
+		}
+	}
+	sets := entities.ClusterDuplicates(pairs)
+	start, end := pageBounds(len(sets), offset, limit)
+	sets = sets[start:end]
+	if len(sets) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	duplicates := make([]entities.DuplicateSongs, 0, len(sets))
+	for _, set := range sets {
+		setSongs := make([]entities.Song, 0, len(set.IDs))
+		for _, id := range set.IDs {
+			setSongs = append(setSongs, m.withGroup(m.songs[id]))
+		}
+		duplicates = append(duplicates, entities.NewDuplicateSongs(setSongs, set.Similarity))
+	}
+	return duplicates, nil
+}
+
+// MergeSongs merges metadata of the duplicates into the song (see entities.Song.MergeDuplicates)
+// and moves the duplicates to the trash. They must be other songs of its group which are not in the trash.
+// The merged song is returned.
+func (m *MemStorage) MergeSongs(ctx context.Context, id uint64, duplicateIDs []uint64) (entities.Song, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Song{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(id, false)
+	if err != nil {
+		return entities.Song{}, err
+	}
+	duplicateIDs = slices.Compact(slices.Sorted(slices.Values(duplicateIDs)))
+	duplicates := make([]entities.Song, 0, len(duplicateIDs))
+	for _, duplicateID := range duplicateIDs {
+		if duplicateID == id {
+			return entities.Song{}, dberrors.NewInvalidErr(fmt.Errorf("song %d can not be merged into itself", id))
+		}
+		duplicate, err := m.song(duplicateID, false)
+		if err != nil {
+			return entities.Song{}, err
+		}
+		if duplicate.GroupID != before.GroupID {
+			return entities.Song{}, dberrors.NewInvalidErr(fmt.Errorf("song %d is of another group", duplicateID))
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	m.replaceSongFields(id, before.MergeDuplicates(duplicates))
+	merged := m.recordRevision(ctx, entities.RevisionMerge, id, &before)
+	for _, duplicate := range duplicates {
+		song := m.songs[duplicate.ID]
+		song.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		m.songs[duplicate.ID] = song
+		delete(m.jobs, duplicate.ID)
+		m.recordRevision(ctx, entities.RevisionDelete, duplicate.ID, &duplicate)
+	}
+	return merged, nil
+}
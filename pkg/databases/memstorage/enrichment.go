@@ -0,0 +1,127 @@
+package memstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"time"
+)
+
+// EnqueueEnrichment marks the song as pending and (re)schedules its enrichment job for now.
+// A changed status is a revision of the song.
+func (m *MemStorage) EnqueueEnrichment(ctx context.Context, songID uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.setEnrichmentStatus(ctx, songID, entities.EnrichmentPending)
+	if err != nil {
+		return err
+	}
+
+	job, ok := m.jobs[songID]
+	if !ok {
+		job = entities.EnrichmentJob{SongID: songID, CreatedAt: time.Now()}
+	}
+	job.Attempts, job.NextAttemptAt, job.LastError = 0, time.Now(), ""
+	m.jobs[songID] = job
+	return nil
+}
+
+// ClaimEnrichmentJob takes the most overdue job and moves its next attempt to the end of the lease.
+// If a worker dies, its job becomes due again when the lease ends.
+func (m *MemStorage) ClaimEnrichmentJob(ctx context.Context, lease time.Duration) (entities.EnrichmentJob, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.EnrichmentJob{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var claimed *entities.EnrichmentJob
+	for _, job := range m.jobs {
+		if job.NextAttemptAt.After(now) {
+			continue
+		}
+		if claimed == nil || job.NextAttemptAt.Before(claimed.NextAttemptAt) ||
+			(job.NextAttemptAt.Equal(claimed.NextAttemptAt) && job.SongID < claimed.SongID) {
+			claimed = &job
+		}
+	}
+	if claimed == nil {
+		return entities.EnrichmentJob{}, dberrors.NewNotFoundErr()
+	}
+	claimed.Attempts++
+	claimed.NextAttemptAt = now.Add(lease)
+	m.jobs[claimed.SongID] = *claimed
+	return *claimed, nil
+}
+
+// CompleteEnrichmentJob fills empty extra data fields of the song and removes its job.
+// Fields which were filled by a user while the job was waiting are kept.
+func (m *MemStorage) CompleteEnrichmentJob(ctx context.Context, songID uint64, data entities.ExtraSongData) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(songID, false)
+	if err != nil {
+		return err
+	}
+	song := copySong(m.songs[songID])
+	song.ApplyExtraData(data)
+	song.EnrichmentStatus = entities.EnrichmentEnriched
+	m.songs[songID] = song
+	delete(m.jobs, songID)
+	m.recordRevision(ctx, entities.RevisionUpdate, songID, &before)
+	return nil
+}
+
+// RescheduleEnrichmentJob sets the time of the next attempt of the job.
+func (m *MemStorage) RescheduleEnrichmentJob(ctx context.Context, songID uint64, nextAttemptAt time.Time, lastError string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[songID]
+	if !ok {
+		return dberrors.NewNotFoundErr()
+	}
+	job.NextAttemptAt, job.LastError = nextAttemptAt, lastError
+	m.jobs[songID] = job
+	return nil
+}
+
+// FailEnrichmentJob marks the song as failed and removes its job. A changed status is a revision of the song.
+// The job of a song which has been removed meanwhile is removed too.
+func (m *MemStorage) FailEnrichmentJob(ctx context.Context, songID uint64, lastError string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setEnrichmentStatus(ctx, songID, entities.EnrichmentFailed)
+	delete(m.jobs, songID)
+	return nil
+}
+
+// setEnrichmentStatus changes the enrichment status of the song and records the revision,
+// so the version and the ETag of the song change with it.
+func (m *MemStorage) setEnrichmentStatus(ctx context.Context, songID uint64, status entities.EnrichmentStatus) error {
+	before, err := m.song(songID, false)
+	if err != nil {
+		return err
+	}
+	song := m.songs[songID]
+	song.EnrichmentStatus = status
+	m.songs[songID] = song
+	m.recordRevision(ctx, entities.RevisionUpdate, songID, &before)
+	return nil
+}
@@ -0,0 +1,28 @@
+package memstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+)
+
+// ExportSongs calls fn for every song matching the filter in the order of IDs, an error of fn stops the export.
+// Matching songs are copied first, so fn runs without the lock and may use the storage.
+func (m *MemStorage) ExportSongs(ctx context.Context, filter entities.SongFilter, fn func(entities.Song) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.RLock()
+	songs := m.filterSongs(filter)
+	m.mu.RUnlock()
+
+	for _, song := range songs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := fn(song)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
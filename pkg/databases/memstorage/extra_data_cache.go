@@ -0,0 +1,56 @@
+package memstorage
+
+import (
+	"context"
+	"maps"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"time"
+)
+
+// GetCachedExtraData returns a not expired cache entry.
+func (m *MemStorage) GetCachedExtraData(ctx context.Context, key string) (entities.CachedExtraData, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.CachedExtraData{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.cache[key]
+	if !ok || !entry.ExpiresAt.After(time.Now()) {
+		return entities.CachedExtraData{}, dberrors.NewNotFoundErr()
+	}
+	entry.Sources = maps.Clone(entry.Sources)
+	return entry, nil
+}
+
+// SaveCachedExtraData saves the cache entry, replacing an old one with the same key.
+func (m *MemStorage) SaveCachedExtraData(ctx context.Context, entry entities.CachedExtraData) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry.Sources = maps.Clone(entry.Sources)
+	m.cache[entry.Key] = entry
+	return nil
+}
+
+// PurgeCachedExtraData removes entries which expired before the time and returns their number.
+func (m *MemStorage) PurgeCachedExtraData(ctx context.Context, before time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for key, entry := range m.cache {
+		if entry.ExpiresAt.Before(before) {
+			delete(m.cache, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
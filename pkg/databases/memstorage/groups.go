@@ -0,0 +1,221 @@
+package memstorage
+
+import (
+	"context"
+	"errors"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+	"strings"
+)
+
+// errEmptyGroupName is returned for a song or an album without GroupID and group name.
+var errEmptyGroupName = dberrors.NewInvalidErr(errors.New("group name is empty"))
+
+// resolveGroup finds a group by its name or alias and creates a new one if nothing was found.
+func (m *MemStorage) resolveGroup(name string) (entities.Group, error) {
+	normalized := entities.NormalizeGroupName(name)
+	if normalized == "" {
+		return entities.Group{}, errEmptyGroupName
+	}
+
+	group, ok := m.findGroupByName(normalized)
+	if ok {
+		return group, nil
+	}
+	return m.createGroup(entities.Group{Name: normalizeSpaces(name), NormalizedName: normalized}), nil
+}
+
+// findGroupByName looks for a group whose normalized name or one of aliases equals to the normalized name.
+// A group named so goes before groups with such an alias, then groups go in the order of IDs.
+func (m *MemStorage) findGroupByName(normalized string) (entities.Group, bool) {
+	var found *entities.Group
+	for _, group := range m.groups {
+		byName := group.NormalizedName == normalized
+		if !byName && !slices.ContainsFunc(group.Aliases, func(alias string) bool {
+			return entities.NormalizeGroupName(alias) == normalized
+		}) {
+			continue
+		}
+		if found == nil {
+			found = &group
+			continue
+		}
+		foundByName := found.NormalizedName == normalized
+		if (byName && !foundByName) || (byName == foundByName && group.ID < found.ID) {
+			found = &group
+		}
+	}
+	if found == nil {
+		return entities.Group{}, false
+	}
+	return copyGroup(*found), true
+}
+
+// checkGroupNames returns a conflict if another group has the name or one of aliases of the group
+// as its name or alias, so every name leads to a single group.
+func (m *MemStorage) checkGroupNames(group entities.Group) error {
+	var names []string
+	if group.NormalizedName != "" {
+		names = append(names, group.NormalizedName)
+	}
+	for _, alias := range group.Aliases {
+		if normalized := entities.NormalizeGroupName(alias); normalized != "" {
+			names = append(names, normalized)
+		}
+	}
+	for _, other := range m.groups {
+		if other.ID == group.ID {
+			continue
+		}
+		for _, name := range names {
+			if other.NormalizedName == name || slices.ContainsFunc(other.Aliases, func(alias string) bool {
+				return entities.NormalizeGroupName(alias) == name
+			}) {
+				return dberrors.NewConflictErr()
+			}
+		}
+	}
+	return nil
+}
+
+// createGroup inserts the group with a new ID.
+func (m *MemStorage) createGroup(group entities.Group) entities.Group {
+	m.lastGroupID++
+	group = copyGroup(group)
+	group.ID = m.lastGroupID
+	m.groups[group.ID] = group
+	return copyGroup(group)
+}
+
+// copyGroup returns a group which shares no slices with the given one.
+func copyGroup(group entities.Group) entities.Group {
+	group.Aliases = slices.Clone(group.Aliases)
+	return group
+}
+
+// normalizeSpaces trims a name and collapses repeated whitespaces.
+func normalizeSpaces(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// SaveGroup saves a new group and returns its ID.
+func (m *MemStorage) SaveGroup(ctx context.Context, group entities.Group) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	group.Name = normalizeSpaces(group.Name)
+	group.NormalizedName = entities.NormalizeGroupName(group.Name)
+	if err := m.checkGroupNames(group); err != nil {
+		return 0, err
+	}
+	return m.createGroup(group).ID, nil
+}
+
+// GetGroup returns the group by its ID.
+func (m *MemStorage) GetGroup(ctx context.Context, id uint64) (entities.Group, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Group{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	group, ok := m.groups[id]
+	if !ok {
+		return entities.Group{}, dberrors.NewNotFoundErr()
+	}
+	return copyGroup(group), nil
+}
+
+// GetGroupList returns list of groups.
+func (m *MemStorage) GetGroupList(ctx context.Context, filter entities.Group, offset int, limit int) ([]entities.Group, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	groups := []entities.Group{}
+	for _, group := range m.groups {
+		if filter.Name != "" && !matchText(group.Name, filter.Name, entities.MatchContains) {
+			continue
+		}
+		if filter.Country != "" && !matchText(group.Country, filter.Country, entities.MatchExact) {
+			continue
+		}
+		if filter.FormedYear != 0 && group.FormedYear != filter.FormedYear {
+			continue
+		}
+		groups = append(groups, copyGroup(group))
+	}
+	slices.SortFunc(groups, func(a, b entities.Group) int {
+		return compareIDs(a.ID, b.ID)
+	})
+
+	start, end := pageBounds(len(groups), offset, limit)
+	groups = groups[start:end]
+	if len(groups) == 0 {
+		return groups, dberrors.NewNotFoundErr()
+	}
+	return groups, nil
+}
+
+// RemoveGroup removes the group. Groups which still have songs or albums can not be removed,
+// songs in the trash count too, as they may be restored.
+func (m *MemStorage) RemoveGroup(ctx context.Context, id uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, song := range m.songs {
+		if song.GroupID == id {
+			return dberrors.NewConflictErr()
+		}
+	}
+	for _, album := range m.albums {
+		if album.GroupID == id {
+			return dberrors.NewConflictErr()
+		}
+	}
+	if _, ok := m.groups[id]; !ok {
+		return dberrors.NewNotFoundErr()
+	}
+	delete(m.groups, id)
+	return nil
+}
+
+// UpdateGroup updates non-empty fields of the group.
+func (m *MemStorage) UpdateGroup(ctx context.Context, group entities.Group) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updated, ok := m.groups[group.ID]
+	if group.Name != "" {
+		group.Name = normalizeSpaces(group.Name)
+		group.NormalizedName = entities.NormalizeGroupName(group.Name)
+	}
+	if err := m.checkGroupNames(group); err != nil {
+		return err
+	}
+	if !ok {
+		return dberrors.NewNotFoundErr()
+	}
+
+	updateString(&updated.Name, group.Name)
+	updateString(&updated.NormalizedName, group.NormalizedName)
+	if group.Aliases != nil {
+		updated.Aliases = group.Aliases
+	}
+	updateString(&updated.Country, group.Country)
+	updateInt(&updated.FormedYear, group.FormedYear)
+	m.groups[group.ID] = copyGroup(updated)
+	return nil
+}
@@ -0,0 +1,69 @@
+package memstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+	"slices"
+	"strconv"
+)
+
+// ImportSongs saves the songs at once and returns their outcomes in the same order.
+// Groups are resolved by name like in SaveSong. A song of the same group and name as a song in the library
+// or earlier in the batch is not saved. Pending songs get enrichment jobs.
+func (m *MemStorage) ImportSongs(ctx context.Context, songs []entities.Song) ([]entities.ImportedSong, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	//check songs first, nothing is rolled back
+	for _, song := range songs {
+		err := m.checkReferences(song.GroupID, song.AlbumID)
+		if err != nil {
+			return nil, err
+		}
+		if song.GroupID == 0 && entities.NormalizeGroupName(song.Group) == "" {
+			return nil, errEmptyGroupName
+		}
+	}
+
+	//resolve groups
+	songs = slices.Clone(songs)
+	for i := range songs {
+		if songs[i].GroupID == 0 {
+			group, err := m.resolveGroup(songs[i].Group)
+			if err != nil {
+				return nil, err
+			}
+			songs[i].GroupID = group.ID
+		}
+	}
+
+	//songs already in the library, the trash is not looked at
+	known := map[string]uint64{}
+	for _, song := range m.songs {
+		if !song.DeletedAt.Valid {
+			known[importKey(song)] = song.ID
+		}
+	}
+
+	imported := make([]entities.ImportedSong, len(songs))
+	for i, song := range songs {
+		key := importKey(song)
+		if id, ok := known[key]; ok {
+			imported[i] = entities.ImportedSong{ID: id, Existed: true}
+			continue
+		}
+		id := m.createSong(song)
+		known[key] = id
+		imported[i] = entities.ImportedSong{ID: id}
+		m.recordRevision(ctx, entities.RevisionCreate, id, nil)
+	}
+	return imported, nil
+}
+
+// importKey identifies a song by its group and normalized name.
+func importKey(song entities.Song) string {
+	return strconv.FormatUint(song.GroupID, 10) + "\n" + entities.NormalizeSongName(song.Song)
+}
@@ -0,0 +1,439 @@
+package memstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"maps"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemStorage keeps the library in memory with the same semantics as gormpostgres, it suits tests and demos.
+// Every method holds a lock for its whole run, so it behaves like a transaction.
+// Strings are ordered by bytes like the C collation. The full-text search does not stem words, see SearchSongs.
+type MemStorage struct {
+	mu sync.RWMutex
+	// songs are kept without group names, they are taken from groups on reading.
+	songs  map[uint64]entities.Song
+	groups map[uint64]entities.Group
+	albums map[uint64]entities.Album
+	jobs   map[uint64]entities.EnrichmentJob
+	cache  map[string]entities.CachedExtraData
+	// revisions of a song go in the order of their numbers.
+	revisions map[uint64][]entities.SongRevision
+
+	lastSongID     uint64
+	lastGroupID    uint64
+	lastAlbumID    uint64
+	lastRevisionID uint64
+}
+
+// NewMemStorage returns an empty storage.
+func NewMemStorage() *MemStorage {
+	return &MemStorage{
+		songs:     map[uint64]entities.Song{},
+		groups:    map[uint64]entities.Group{},
+		albums:    map[uint64]entities.Album{},
+		jobs:      map[uint64]entities.EnrichmentJob{},
+		cache:     map[string]entities.CachedExtraData{},
+		revisions: map[uint64][]entities.SongRevision{},
+	}
+}
+
+// copySong returns a song which shares no pointers and maps with the given one.
+func copySong(song entities.Song) entities.Song {
+	if song.AlbumID != nil {
+		albumID := *song.AlbumID
+		song.AlbumID = &albumID
+	}
+	if song.ReleaseDate != nil {
+		date := *song.ReleaseDate
+		song.ReleaseDate = &date
+	}
+	song.Sources = maps.Clone(song.Sources)
+	return song
+}
+
+// SaveSong saves a new song and returns its ID.
+// If the song has no GroupID, the group is resolved by its name and created when it does not exist yet.
+// A song of the same group and normalized name as a song which is not in the trash is a conflict, see checkDuplicate.
+// A song with the pending enrichment status gets an enrichment job.
+func (m *MemStorage) SaveSong(ctx context.Context, song entities.Song) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.checkReferences(song.GroupID, song.AlbumID)
+	if err != nil {
+		return 0, err
+	}
+	if song.GroupID == 0 {
+		group, err := m.resolveGroup(song.Group)
+		if err != nil {
+			return 0, err
+		}
+		song.GroupID = group.ID
+	}
+	err = m.checkDuplicate(song.GroupID, song.Song, 0)
+	if err != nil {
+		return 0, err
+	}
+	id := m.createSong(song)
+	m.recordRevision(ctx, entities.RevisionCreate, id, nil)
+	return id, nil
+}
+
+// createSong inserts the song with a new ID and a job if it is pending. Its group must be resolved and references checked.
+func (m *MemStorage) createSong(song entities.Song) uint64 {
+	m.lastSongID++
+	song = copySong(song)
+	song.ID, song.Group, song.Version, song.DeletedAt = m.lastSongID, "", 1, gorm.DeletedAt{}
+	m.songs[song.ID] = song
+	if song.EnrichmentStatus == entities.EnrichmentPending {
+		m.jobs[song.ID] = entities.EnrichmentJob{SongID: song.ID, NextAttemptAt: time.Now(), CreatedAt: time.Now()}
+	}
+	return song.ID
+}
+
+// checkReferences fails where the database would refuse a missing group or album by a foreign key.
+// A zero groupID is not checked, the group is resolved by its name then.
+// Nothing is rolled back here, so references are checked before anything is changed.
+func (m *MemStorage) checkReferences(groupID uint64, albumID *uint64) error {
+	if _, ok := m.groups[groupID]; groupID != 0 && !ok {
+		return dberrors.NewInvalidErr(fmt.Errorf("group %d does not exist", groupID))
+	}
+	if albumID != nil {
+		if _, ok := m.albums[*albumID]; !ok {
+			return dberrors.NewInvalidErr(fmt.Errorf("album %d does not exist", *albumID))
+		}
+	}
+	return nil
+}
+
+// song returns the song with its group name. Songs in the trash are found only if unscoped.
+func (m *MemStorage) song(id uint64, unscoped bool) (entities.Song, error) {
+	song, ok := m.songs[id]
+	if !ok || (!unscoped && song.DeletedAt.Valid) {
+		return entities.Song{}, dberrors.NewNotFoundErr()
+	}
+	return m.withGroup(song), nil
+}
+
+// withGroup returns a copy of the song with the name of its group.
+func (m *MemStorage) withGroup(song entities.Song) entities.Song {
+	song = copySong(song)
+	song.Group = m.groups[song.GroupID].Name
+	return song
+}
+
+// liveSongs returns songs which are not in the trash with their group names in the order of IDs.
+func (m *MemStorage) liveSongs() []entities.Song {
+	songs := make([]entities.Song, 0, len(m.songs))
+	for _, song := range m.songs {
+		if !song.DeletedAt.Valid {
+			songs = append(songs, m.withGroup(song))
+		}
+	}
+	slices.SortFunc(songs, func(a, b entities.Song) int {
+		return compareIDs(a.ID, b.ID)
+	})
+	return songs
+}
+
+// GetSong returns the song by its ID.
+func (m *MemStorage) GetSong(ctx context.Context, id uint64) (entities.Song, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Song{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.song(id, false)
+}
+
+// GetSongList returns a page of songs in the requested order.
+// Pages are taken by a cursor (keyset pagination), so songs inserted meanwhile do not shift them.
+func (m *MemStorage) GetSongList(ctx context.Context, query entities.SongListQuery) (entities.SongPage, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.SongPage{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	page := entities.SongPage{}
+	limit := query.PageLimit()
+	column, ok := songSortColumns[query.Sort.Field]
+	if !ok {
+		column = songSortColumns["id"]
+	}
+
+	songs := m.filterSongs(query.Filter)
+	if query.WithTotal {
+		total := int64(len(songs))
+		page.Total = &total
+	}
+
+	backward := query.Cursor != nil && query.Cursor.Backward
+	desc := query.Sort.Desc != backward
+	compare := func(a, b entities.Song) int {
+		c := column.compare(column.key(a), column.key(b))
+		if c == 0 {
+			c = compareIDs(a.ID, b.ID)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	}
+	slices.SortFunc(songs, compare)
+	if query.Cursor == nil {
+		songs = songs[min(max(query.Offset, 0), len(songs)):]
+	} else {
+		if !column.validKey(query.Cursor.Key) {
+			return entities.SongPage{}, dberrors.NewInvalidErr(fmt.Errorf("invalid cursor key `%v`", query.Cursor.Key))
+		}
+		after := slices.IndexFunc(songs, func(song entities.Song) bool {
+			c := column.compare(column.key(song), query.Cursor.Key)
+			if c == 0 {
+				c = compareIDs(song.ID, query.Cursor.ID)
+			}
+			if desc {
+				return c < 0
+			}
+			return c > 0
+		})
+		if after < 0 {
+			after = len(songs)
+		}
+		songs = songs[after:]
+	}
+	if len(songs) == 0 {
+		return page, dberrors.NewNotFoundErr()
+	}
+
+	hasMore := len(songs) > limit
+	if hasMore {
+		songs = songs[:limit]
+	}
+	if backward {
+		slices.Reverse(songs)
+	}
+	page.Songs = songs
+
+	//cursors
+	sort := query.Sort.String()
+	first, last := songs[0], songs[len(songs)-1]
+	if (backward && hasMore) || (!backward && (query.Cursor != nil || query.Offset > 0)) {
+		page.PrevCursor = &entities.Cursor{Sort: sort, Key: column.key(first), ID: first.ID, Backward: true}
+	}
+	if (!backward && hasMore) || backward {
+		page.NextCursor = &entities.Cursor{Sort: sort, Key: column.key(last), ID: last.ID}
+	}
+	return page, nil
+}
+
+// GetSongLyrics returns song`s lyrics.
+func (m *MemStorage) GetSongLyrics(ctx context.Context, id uint64) (string, error) {
+	song, err := m.GetSong(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return song.Text, nil
+}
+
+// RemoveSong moves the song to the trash. Its enrichment job is dropped and scheduled again on restore.
+// A non-zero version must be the current version of the song.
+func (m *MemStorage) RemoveSong(ctx context.Context, id uint64, version int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(id, false)
+	if err != nil {
+		return err
+	}
+	if version != 0 && version != before.Version {
+		return dberrors.NewVersionMismatchErr()
+	}
+	song := m.songs[id]
+	song.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	m.songs[id] = song
+	delete(m.jobs, id)
+	m.recordRevision(ctx, entities.RevisionDelete, id, &before)
+	return nil
+}
+
+// UpdateSong updates non-empty fields of the song.
+// A non-empty group name without GroupID moves the song to that group, creating it if needed.
+// A non-zero Version must be the current version of the song.
+func (m *MemStorage) UpdateSong(ctx context.Context, song entities.Song) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(song.ID, false)
+	if err != nil {
+		return err
+	}
+	if song.Version != 0 && song.Version != before.Version {
+		return dberrors.NewVersionMismatchErr()
+	}
+	err = m.checkReferences(song.GroupID, song.AlbumID)
+	if err != nil {
+		return err
+	}
+	if song.GroupID == 0 && song.Group != "" {
+		group, err := m.resolveGroup(song.Group)
+		if err != nil {
+			return err
+		}
+		song.GroupID = group.ID
+	}
+
+	updated := m.songs[song.ID]
+	if song.GroupID != 0 {
+		updated.GroupID = song.GroupID
+	}
+	updateString(&updated.Song, song.Song)
+	if !before.IsSameSong(updated.GroupID, updated.Song) {
+		err = m.checkDuplicate(updated.GroupID, updated.Song, song.ID)
+		if err != nil {
+			return err
+		}
+	}
+	if song.AlbumID != nil {
+		updated.AlbumID = song.AlbumID
+	}
+	updateInt(&updated.DiscNumber, song.DiscNumber)
+	updateInt(&updated.TrackNumber, song.TrackNumber)
+	if song.ReleaseDate != nil {
+		updated.ReleaseDate = song.ReleaseDate
+	}
+	updateString(&updated.ReleaseDateRaw, song.ReleaseDateRaw)
+	updateString(&updated.Text, song.Text)
+	updateString(&updated.Link, song.Link)
+	if song.EnrichmentStatus != "" {
+		updated.EnrichmentStatus = song.EnrichmentStatus
+	}
+	if song.Sources != nil {
+		updated.Sources = song.Sources
+	}
+	m.songs[song.ID] = copySong(updated)
+	m.recordRevision(ctx, entities.RevisionUpdate, song.ID, &before)
+	return nil
+}
+
+// updateString and updateInt set a field to a non-empty value, like an update of non-zero struct fields.
+func updateString(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+func updateInt(field *int, value int) {
+	if value != 0 {
+		*field = value
+	}
+}
+
+// ReplaceSong replaces all fields of the song a client can change, empty fields are cleared.
+// The song is moved to the group of its name if it has no GroupID. Sources of changed extra data are forgotten.
+// A non-zero Version must be the current version of the song.
+func (m *MemStorage) ReplaceSong(ctx context.Context, song entities.Song) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(song.ID, false)
+	if err != nil {
+		return err
+	}
+	if song.Version != 0 && song.Version != before.Version {
+		return dberrors.NewVersionMismatchErr()
+	}
+	err = m.checkReferences(song.GroupID, song.AlbumID)
+	if err != nil {
+		return err
+	}
+	if song.GroupID == 0 {
+		group, err := m.resolveGroup(song.Group)
+		if err != nil {
+			return err
+		}
+		song.GroupID = group.ID
+	}
+	if !before.IsSameSong(song.GroupID, song.Song) {
+		err = m.checkDuplicate(song.GroupID, song.Song, song.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	song.Sources = maps.Clone(before.Sources)
+	song.ForgetChangedSources(before)
+	m.replaceSongFields(song.ID, song)
+	m.recordRevision(ctx, entities.RevisionUpdate, song.ID, &before)
+	return nil
+}
+
+// replaceSongFields writes all editable fields of the song including empty ones. References must be checked.
+func (m *MemStorage) replaceSongFields(id uint64, song entities.Song) {
+	replaced := m.songs[id]
+	replaced.Song = song.Song
+	replaced.GroupID = song.GroupID
+	replaced.AlbumID = song.AlbumID
+	replaced.DiscNumber = song.DiscNumber
+	replaced.TrackNumber = song.TrackNumber
+	replaced.ReleaseDate = song.ReleaseDate
+	replaced.ReleaseDateRaw = song.ReleaseDateRaw
+	replaced.Text = song.Text
+	replaced.Link = song.Link
+	replaced.Sources = song.Sources
+	m.songs[id] = copySong(replaced)
+}
+
+// compareIDs orders IDs ascending.
+func compareIDs(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// pageBounds returns the bounds of a page of n items like OFFSET and LIMIT do, a negative limit does not limit.
+func pageBounds(n, offset, limit int) (int, int) {
+	start := min(max(offset, 0), n)
+	if limit < 0 {
+		return start, n
+	}
+	return start, min(start+limit, n)
+}
+
+// jsonCopy returns the song as it is read back from a JSON column: fields hidden from JSON are lost.
+func jsonCopy(song entities.Song) *entities.Song {
+	data, err := json.Marshal(song)
+	if err != nil {
+		return nil
+	}
+	snapshot := &entities.Song{}
+	err = json.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil
+	}
+	return snapshot
+}
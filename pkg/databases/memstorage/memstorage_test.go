@@ -0,0 +1,12 @@
+package memstorage
+
+import (
+	"musiclib/pkg/databases/storagetest"
+	"testing"
+)
+
+func TestMemStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return NewMemStorage()
+	})
+}
@@ -0,0 +1,115 @@
+package memstorage
+
+import (
+	"context"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+	"time"
+)
+
+// recordRevision saves the next revision of the song with changes made since before, nil for a new song.
+// An update which has changed nothing is not recorded. Otherwise the version of a changed song is incremented.
+// The state of the song after the change is returned.
+// The actor and the request ID are taken from the audit info of the context.
+func (m *MemStorage) recordRevision(ctx context.Context, action entities.RevisionAction, songID uint64, before *entities.Song) entities.Song {
+	after, _ := m.song(songID, true)
+	changes := entities.DiffSongs(before, &after)
+	if action == entities.RevisionUpdate && len(changes) == 0 {
+		return after
+	}
+	if before != nil {
+		song := m.songs[songID]
+		song.Version++
+		m.songs[songID] = song
+		after.Version++
+	}
+
+	info := entities.AuditInfoFromContext(ctx)
+	m.lastRevisionID++
+	m.revisions[songID] = append(m.revisions[songID], entities.SongRevision{
+		ID:        m.lastRevisionID,
+		SongID:    songID,
+		Revision:  len(m.revisions[songID]) + 1,
+		Action:    action,
+		Actor:     info.Actor,
+		RequestID: info.RequestID,
+		Changes:   changes,
+		Song:      jsonCopy(after),
+		CreatedAt: time.Now(),
+	})
+	return after
+}
+
+// copyRevision returns a revision which shares no song with the given one.
+func copyRevision(revision entities.SongRevision) entities.SongRevision {
+	if revision.Song != nil {
+		song := copySong(*revision.Song)
+		revision.Song = &song
+	}
+	revision.Changes = slices.Clone(revision.Changes)
+	return revision
+}
+
+// GetSongHistory returns revisions of the song, the latest go first.
+// Songs in the trash have a history too, a song without revisions gives an empty list.
+func (m *MemStorage) GetSongHistory(ctx context.Context, id uint64, offset int, limit int) ([]entities.SongRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.songs[id]; !ok {
+		return nil, dberrors.NewNotFoundErr()
+	}
+	revisions := make([]entities.SongRevision, 0, len(m.revisions[id]))
+	for _, revision := range m.revisions[id] {
+		revisions = append(revisions, copyRevision(revision))
+	}
+	slices.Reverse(revisions)
+	start, end := pageBounds(len(revisions), offset, limit)
+	return revisions[start:end], nil
+}
+
+// RevertSong brings the song back to its state after the revision and records it as a new revision.
+// A group or an album which was removed since is not restored: the group is found by its name again and the album is unset.
+func (m *MemStorage) RevertSong(ctx context.Context, id uint64, revision int) (entities.Song, error) {
+	if err := ctx.Err(); err != nil {
+		return entities.Song{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(id, false)
+	if err != nil {
+		return entities.Song{}, err
+	}
+	revisions := m.revisions[id]
+	if revision < 1 || revision > len(revisions) || revisions[revision-1].Song == nil {
+		return entities.Song{}, dberrors.NewNotFoundErr()
+	}
+
+	state := copySong(*revisions[revision-1].Song)
+	if _, ok := m.groups[state.GroupID]; !ok {
+		group, err := m.resolveGroup(state.Group)
+		if err != nil {
+			return entities.Song{}, err
+		}
+		state.GroupID = group.ID
+	}
+	if state.AlbumID != nil {
+		if _, ok := m.albums[*state.AlbumID]; !ok {
+			state.AlbumID = nil
+		}
+	}
+	if !before.IsSameSong(state.GroupID, state.Song) {
+		err = m.checkDuplicate(state.GroupID, state.Song, id)
+		if err != nil {
+			return entities.Song{}, err
+		}
+	}
+
+	m.replaceSongFields(id, state)
+	return m.recordRevision(ctx, entities.RevisionRevert, id, &before), nil
+}
@@ -0,0 +1,335 @@
+package memstorage
+
+import (
+	"cmp"
+	"context"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// lyricsHeadlineWords is the length of a headline cut from lyrics when no couplet matches, like MaxWords of ts_headline.
+const lyricsHeadlineWords = 30
+
+// word is a word of a text with its position in bytes.
+type word struct {
+	text       string
+	start, end int
+}
+
+// splitWords returns lowercase words of letters and digits, other characters separate them.
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			words = append(words, word{text: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+// wordTexts returns lowercase words of the text.
+func wordTexts(text string) []string {
+	words := splitWords(text)
+	texts := make([]string, 0, len(words))
+	for _, w := range words {
+		texts = append(texts, w.text)
+	}
+	return texts
+}
+
+// searchTerm is a word or a phrase of a search query which must, or with exclude must not, be in a song.
+type searchTerm struct {
+	words   []string
+	exclude bool
+}
+
+// searchAlternative is a list of terms which all must match, a query matches if any of its alternatives does.
+type searchAlternative []searchTerm
+
+// parseSearchQuery parses a web search query: words, "quoted phrases", `or` between alternatives and `-` to exclude a word.
+// A phrase query is a single phrase of all its words.
+func parseSearchQuery(text string, phrase bool) []searchAlternative {
+	if phrase {
+		words := wordTexts(text)
+		if len(words) == 0 {
+			return nil
+		}
+		return []searchAlternative{{{words: words}}}
+	}
+
+	var alternatives []searchAlternative
+	var current searchAlternative
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			break
+		}
+		exclude := strings.HasPrefix(text, "-")
+		text = strings.TrimPrefix(text, "-")
+
+		if strings.HasPrefix(text, `"`) {
+			quoted := text[1:]
+			text = ""
+			if end := strings.Index(quoted, `"`); end >= 0 {
+				quoted, text = quoted[:end], quoted[end+1:]
+			}
+			if words := wordTexts(quoted); len(words) > 0 {
+				current = append(current, searchTerm{words: words, exclude: exclude})
+			}
+			continue
+		}
+
+		end := strings.IndexFunc(text, unicode.IsSpace)
+		if end < 0 {
+			end = len(text)
+		}
+		chunk := text[:end]
+		text = text[end:]
+		if !exclude && strings.EqualFold(chunk, "or") {
+			if len(current) > 0 {
+				alternatives = append(alternatives, current)
+				current = nil
+			}
+			continue
+		}
+		for _, w := range wordTexts(chunk) {
+			current = append(current, searchTerm{words: []string{w}, exclude: exclude})
+		}
+	}
+	if len(current) > 0 {
+		alternatives = append(alternatives, current)
+	}
+	return alternatives
+}
+
+// countPhrase counts occurrences of the phrase in the words.
+func countPhrase(words, phrase []string) int {
+	count := 0
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			count++
+		}
+	}
+	return count
+}
+
+// matchQuery tells whether any alternative matches the documents, words of each document are matched separately.
+// It returns the number of occurrences of terms of the best matching alternative.
+func matchQuery(alternatives []searchAlternative, documents ...[]string) (int, bool) {
+	best, matched := 0, false
+	for _, alternative := range alternatives {
+		occurrences, ok := 0, true
+		for _, term := range alternative {
+			count := 0
+			for _, document := range documents {
+				count += countPhrase(document, term.words)
+			}
+			if (count > 0) == term.exclude {
+				ok = false
+				break
+			}
+			occurrences += count
+		}
+		if ok && (!matched || occurrences > best) {
+			best, matched = occurrences, true
+		}
+	}
+	return best, matched
+}
+
+// highlighted returns words of terms which are not excluded, they are wrapped in <mark></mark> in headlines.
+func highlighted(alternatives []searchAlternative) map[string]bool {
+	words := map[string]bool{}
+	for _, alternative := range alternatives {
+		for _, term := range alternative {
+			if term.exclude {
+				continue
+			}
+			for _, w := range term.words {
+				words[w] = true
+			}
+		}
+	}
+	return words
+}
+
+// highlight wraps the words of the text which are in the set in <mark></mark>, HTML of the text is escaped.
+func highlight(text string, set map[string]bool) string {
+	var b strings.Builder
+	last := 0
+	for _, w := range splitWords(text) {
+		if !set[w.text] {
+			continue
+		}
+		b.WriteString(entities.EscapeHeadline(text[last:w.start]))
+		b.WriteString("<mark>" + text[w.start:w.end] + "</mark>")
+		last = w.end
+	}
+	b.WriteString(entities.EscapeHeadline(text[last:]))
+	return b.String()
+}
+
+// headline returns the best matching couplet of the lyrics, or a part of the lyrics around the first match
+// if the match spans couplets or is found only in the song name.
+func headline(text string, alternatives []searchAlternative) string {
+	set := highlighted(alternatives)
+	best, bestCount := "", -1
+	for _, couplet := range strings.Split(text, "\n\n") {
+		count, ok := matchQuery(alternatives, wordTexts(couplet))
+		if ok && count > bestCount {
+			best, bestCount = couplet, count
+		}
+	}
+	if bestCount >= 0 {
+		return highlight(best, set)
+	}
+
+	words := splitWords(text)
+	if len(words) == 0 {
+		return ""
+	}
+	first := slices.IndexFunc(words, func(w word) bool { return set[w.text] })
+	first = max(first, 0)
+	last := min(first+lyricsHeadlineWords, len(words)) - 1
+	return highlight(text[words[first].start:words[last].end], set)
+}
+
+// SearchSongs finds songs by their names and lyrics, the best matching songs go first.
+// Words are matched as they are, without stemming and stop words, like the "simple" configuration does for any language.
+// The rank is a tenth for every occurrence of a query word or phrase.
+// The headline is the best matching couplet, or a part of the lyrics if the match spans couplets.
+// The fuzzy mode searches only song and group names, see fuzzySearchSongs.
+func (m *MemStorage) SearchSongs(ctx context.Context, query entities.SearchQuery) ([]entities.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if query.Mode == entities.SearchFuzzy {
+		return m.fuzzySearchSongs(query)
+	}
+
+	alternatives := parseSearchQuery(query.Text, query.Phrase)
+	var results []entities.SearchResult
+	for _, song := range m.liveSongs() {
+		count, ok := matchQuery(alternatives, wordTexts(song.Song), wordTexts(song.Text))
+		if ok {
+			results = append(results, entities.SearchResult{Song: song, Rank: float64(float32(count) / 10)})
+		}
+	}
+	results = pageResults(results, query)
+	if len(results) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+	for i := range results {
+		results[i].Headline = headline(results[i].Song.Text, alternatives)
+	}
+	return results, nil
+}
+
+// pageResults orders the results by rank and ID and returns the page of the query.
+func pageResults(results []entities.SearchResult, query entities.SearchQuery) []entities.SearchResult {
+	slices.SortFunc(results, func(a, b entities.SearchResult) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		return compareIDs(a.Song.ID, b.Song.ID)
+	})
+	start, end := pageBounds(len(results), query.Offset, query.PageLimit())
+	return results[start:end]
+}
+
+// trigrams returns trigrams of the text like pg_trgm does: every lowercase word is padded
+// with two spaces in front and one behind, so beginnings of words weigh more.
+func trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range wordTexts(text) {
+		runes := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// similarity is the trigram similarity of pg_trgm: the share of common trigrams from 0 to 1.
+func similarity(a, b string) float64 {
+	x, y := trigrams(a), trigrams(b)
+	common := 0
+	for t := range x {
+		if y[t] {
+			common++
+		}
+	}
+	all := len(x) + len(y) - common
+	if all == 0 {
+		return 0
+	}
+	return float64(float32(common) / float32(all))
+}
+
+// fuzzySearchSongs finds songs whose name or group name is similar to the query, the most similar go first.
+func (m *MemStorage) fuzzySearchSongs(query entities.SearchQuery) ([]entities.SearchResult, error) {
+	threshold := query.MinSimilarity()
+	var results []entities.SearchResult
+	for _, song := range m.liveSongs() {
+		songSimilarity, groupSimilarity := similarity(song.Song, query.Text), similarity(song.Group, query.Text)
+		if songSimilarity >= threshold || groupSimilarity >= threshold {
+			results = append(results, entities.SearchResult{Song: song, Rank: max(songSimilarity, groupSimilarity)})
+		}
+	}
+	results = pageResults(results, query)
+	if len(results) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+	return results, nil
+}
+
+// SuggestNames returns song and group names similar to the text, the most similar go first.
+func (m *MemStorage) SuggestNames(ctx context.Context, text string, similarityThreshold float64, limit int) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := map[string]float64{}
+	consider := func(name string) {
+		if score := similarity(name, text); score >= similarityThreshold {
+			scores[name] = score
+		}
+	}
+	for _, group := range m.groups {
+		consider(group.Name)
+	}
+	for _, song := range m.songs {
+		if !song.DeletedAt.Valid {
+			consider(song.Song)
+		}
+	}
+
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(scores[b], scores[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	start, end := pageBounds(len(names), 0, limit)
+	return names[start:end], nil
+}
@@ -0,0 +1,125 @@
+package memstorage
+
+import (
+	"cmp"
+	"musiclib/internal/app/entities"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// noReleaseDate is the sort key of songs without a release date, they go first like in gormpostgres.
+const noReleaseDate = "0001-01-01"
+
+// sortColumn is a field songs can be ordered by. Its key is the text a cursor keeps, as the database casts the column to text.
+type sortColumn struct {
+	key func(song entities.Song) string
+	// numeric keys are compared as numbers, other keys as strings.
+	numeric bool
+	// date keys are ISO 8601 dates, they are ordered as strings.
+	date bool
+}
+
+// songSortColumns maps entities.SongSortFields to sort columns.
+var songSortColumns = map[string]sortColumn{
+	"id":    {key: func(song entities.Song) string { return strconv.FormatUint(song.ID, 10) }, numeric: true},
+	"song":  {key: func(song entities.Song) string { return song.Song }},
+	"group": {key: func(song entities.Song) string { return song.Group }},
+	"release_date": {key: func(song entities.Song) string {
+		if song.ReleaseDate == nil {
+			return noReleaseDate
+		}
+		return song.ReleaseDate.Date.Format(time.DateOnly)
+	}, date: true},
+	"disc_number":  {key: func(song entities.Song) string { return strconv.Itoa(song.DiscNumber) }, numeric: true},
+	"track_number": {key: func(song entities.Song) string { return strconv.Itoa(song.TrackNumber) }, numeric: true},
+}
+
+// compare orders keys of the column, keys must be valid.
+func (c sortColumn) compare(a, b string) int {
+	if !c.numeric {
+		return strings.Compare(a, b)
+	}
+	x, _ := strconv.ParseInt(a, 10, 64)
+	y, _ := strconv.ParseInt(b, 10, 64)
+	return cmp.Compare(x, y)
+}
+
+// validKey tells whether a cursor key can be compared with keys of the column, the database would fail to cast it otherwise.
+func (c sortColumn) validKey(key string) bool {
+	if c.numeric {
+		_, err := strconv.ParseInt(key, 10, 64)
+		return err == nil
+	}
+	if c.date {
+		_, err := time.Parse(time.DateOnly, key)
+		return err == nil
+	}
+	return true
+}
+
+// filterSongs returns songs which are not in the trash and match the filter, in the order of IDs.
+func (m *MemStorage) filterSongs(filter entities.SongFilter) []entities.Song {
+	songs := m.liveSongs()
+	return slices.DeleteFunc(songs, func(song entities.Song) bool {
+		return !m.matchSong(song, filter)
+	})
+}
+
+// matchSong tells whether the song with its group name matches the filter.
+func (m *MemStorage) matchSong(song entities.Song, filter entities.SongFilter) bool {
+	if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, song.ID) {
+		return false
+	}
+	if filter.Song != "" && !matchText(song.Song, filter.Song, filter.SongMatch) {
+		return false
+	}
+	if filter.Group != "" && !matchText(song.Group, filter.Group, filter.GroupMatch) {
+		return false
+	}
+	if len(filter.GroupIDs) > 0 && !slices.Contains(filter.GroupIDs, song.GroupID) {
+		return false
+	}
+	if len(filter.Groups) > 0 && !slices.ContainsFunc(filter.Groups, func(name string) bool {
+		return entities.NormalizeGroupName(name) == m.groups[song.GroupID].NormalizedName
+	}) {
+		return false
+	}
+
+	//release dates are compared as ISO 8601 strings, so time zones do not shift them
+	date := ""
+	if song.ReleaseDate != nil {
+		date = song.ReleaseDate.Date.Format(time.DateOnly)
+	}
+	if filter.ReleaseDate != nil && (date == "" || date < filter.ReleaseDate.Date.Format(time.DateOnly) || date > filter.ReleaseDate.Last().Format(time.DateOnly)) {
+		return false
+	}
+	if filter.ReleaseDateFrom != nil && (date == "" || date < filter.ReleaseDateFrom.Date.Format(time.DateOnly)) {
+		return false
+	}
+	if filter.ReleaseDateTo != nil && (date == "" || date > filter.ReleaseDateTo.Last().Format(time.DateOnly)) {
+		return false
+	}
+
+	if filter.HasLyrics != nil && (song.Text != "") != *filter.HasLyrics {
+		return false
+	}
+	if filter.HasLink != nil && (song.Link != "") != *filter.HasLink {
+		return false
+	}
+	return true
+}
+
+// matchText compares the value with the text ignoring case, MatchContains is used by default.
+func matchText(value, text string, mode entities.MatchMode) bool {
+	value, text = strings.ToLower(value), strings.ToLower(text)
+	switch mode {
+	case entities.MatchExact:
+		return value == text
+	case entities.MatchPrefix:
+		return strings.HasPrefix(value, text)
+	default:
+		return strings.Contains(value, text)
+	}
+}
@@ -0,0 +1,94 @@
+package memstorage
+
+import (
+	"cmp"
+	"context"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"slices"
+	"time"
+)
+
+// GetTrash returns songs in the trash, the most recently removed go first.
+func (m *MemStorage) GetTrash(ctx context.Context, offset int, limit int) ([]entities.TrashedSong, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var songs []entities.Song
+	for _, song := range m.songs {
+		if song.DeletedAt.Valid {
+			songs = append(songs, m.withGroup(song))
+		}
+	}
+	slices.SortFunc(songs, func(a, b entities.Song) int {
+		if c := b.DeletedAt.Time.Compare(a.DeletedAt.Time); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	start, end := pageBounds(len(songs), offset, limit)
+	songs = songs[start:end]
+	if len(songs) == 0 {
+		return nil, dberrors.NewNotFoundErr()
+	}
+
+	trash := make([]entities.TrashedSong, 0, len(songs))
+	for _, song := range songs {
+		trash = append(trash, entities.TrashedSong{Song: song, DeletedAt: song.DeletedAt.Time})
+	}
+	return trash, nil
+}
+
+// RestoreSong takes the song out of the trash unless the group has got a song of the same name meanwhile.
+// A pending song gets its enrichment job back.
+func (m *MemStorage) RestoreSong(ctx context.Context, id uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, err := m.song(id, true)
+	if err != nil {
+		return err
+	}
+	if !before.DeletedAt.Valid {
+		return dberrors.NewNotFoundErr()
+	}
+	err = m.checkDuplicate(before.GroupID, before.Song, id)
+	if err != nil {
+		return err
+	}
+	song := m.songs[id]
+	song.DeletedAt.Time, song.DeletedAt.Valid = time.Time{}, false
+	m.songs[id] = song
+
+	if _, ok := m.jobs[id]; !ok && before.EnrichmentStatus == entities.EnrichmentPending {
+		m.jobs[id] = entities.EnrichmentJob{SongID: id, NextAttemptAt: time.Now(), CreatedAt: time.Now()}
+	}
+	m.recordRevision(ctx, entities.RevisionRestore, id, &before)
+	return nil
+}
+
+// PurgeTrash removes songs which were moved to the trash before the time for good and returns their number.
+// Their history is kept.
+func (m *MemStorage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, song := range m.songs {
+		if song.DeletedAt.Valid && song.DeletedAt.Time.Before(before) {
+			delete(m.songs, id)
+			delete(m.jobs, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,135 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var concurrencyTests = []test{
+	{name: "Concurrent SaveSong of a new group", run: testConcurrentSaveSong},
+	{name: "Concurrent UpdateSong", run: testConcurrentUpdateSong},
+	{name: "Concurrent UpdateSong of one version", run: testConcurrentUpdateSongVersion},
+	{name: "Concurrent ClaimEnrichmentJob", run: testConcurrentClaimEnrichmentJob},
+}
+
+// writers is the number of goroutines writing at once.
+const writers = 8
+
+// concurrently runs fn in writers goroutines and returns their errors by goroutine number.
+func concurrently(fn func(i int) error) []error {
+	errs := make([]error, writers)
+	wg := sync.WaitGroup{}
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func testConcurrentSaveSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	ids := make([]uint64, writers)
+	errs := concurrently(func(i int) error {
+		var err error
+		ids[i], err = s.SaveSong(ctx, entities.Song{Song: fmt.Sprintf("Song %d", i), Group: "Muse"})
+		return err
+	})
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	//the group is created once
+	groups, err := s.GetGroupList(ctx, entities.Group{}, 0, 10)
+	assert.NoError(t, err)
+	if !assert.Len(t, groups, 1) {
+		return
+	}
+	for _, id := range ids {
+		assert.Equal(t, groups[0].ID, getSong(t, s, id).GroupID)
+	}
+}
+
+func testConcurrentUpdateSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+
+	errs := concurrently(func(i int) error {
+		return s.UpdateSong(ctx, entities.Song{ID: id, Text: fmt.Sprintf("Couplet %d", i)})
+	})
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	//every update is recorded with its own version and revision
+	assert.Equal(t, int64(writers+1), getSong(t, s, id).Version)
+	history, err := s.GetSongHistory(ctx, id, 0, writers+10)
+	assert.NoError(t, err)
+	if !assert.Len(t, history, writers+1) {
+		return
+	}
+	for i, revision := range history {
+		assert.Equal(t, writers+1-i, revision.Revision)
+		if assert.NotNil(t, revision.Song) {
+			assert.Equal(t, int64(writers+1-i), revision.Song.Version)
+		}
+	}
+}
+
+func testConcurrentUpdateSongVersion(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+
+	//only one of updates of the same version wins
+	errs := concurrently(func(i int) error {
+		return s.UpdateSong(ctx, entities.Song{ID: id, Text: fmt.Sprintf("Couplet %d", i), Version: 1})
+	})
+	updated := 0
+	for _, err := range errs {
+		if err == nil {
+			updated++
+		} else {
+			assert.ErrorIs(t, err, dberrors.NewVersionMismatchErr())
+		}
+	}
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, int64(2), getSong(t, s, id).Version)
+}
+
+func testConcurrentClaimEnrichmentJob(t *testing.T, s Storage) {
+	ctx := context.Background()
+	var ids []uint64
+	for i := range 2 * writers {
+		ids = append(ids, saveSong(t, s, entities.Song{Song: fmt.Sprintf("Song %d", i), Group: "Muse", EnrichmentStatus: entities.EnrichmentPending}))
+	}
+
+	//every job is claimed by one worker
+	claimed := make([][]uint64, writers)
+	errs := concurrently(func(i int) error {
+		for {
+			job, err := s.ClaimEnrichmentJob(ctx, time.Minute)
+			if errors.Is(err, dberrors.NewNotFoundErr()) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			claimed[i] = append(claimed[i], job.SongID)
+		}
+	})
+	var all []uint64
+	for i, err := range errs {
+		assert.NoError(t, err)
+		all = append(all, claimed[i]...)
+	}
+	assert.ElementsMatch(t, ids, all)
+}
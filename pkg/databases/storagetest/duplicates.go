@@ -0,0 +1,147 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"testing"
+)
+
+var duplicateTests = []test{
+	{name: "Duplicate songs are refused", run: testDuplicateSongs},
+	{name: "GetDuplicateSongs", run: testGetDuplicateSongs},
+	{name: "MergeSongs", run: testMergeSongs},
+}
+
+// assertDuplicate checks that the storage refused a duplicate of the song.
+func assertDuplicate(t *testing.T, err error, id uint64) {
+	t.Helper()
+	assert.Equal(t, dberrors.KindConflict, dberrors.KindOf(err), "error: %v", err)
+	assert.ErrorIs(t, err, entities.DuplicateSongError{ID: id})
+}
+
+func testDuplicateSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+
+	//case, spaces and diacritics do not make another song, another group does
+	_, err := s.SaveSong(ctx, entities.Song{Song: "  HYSTÉRIA ", Group: "muse"})
+	assertDuplicate(t, err, id)
+	saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Queen"})
+
+	//a song can not be renamed into another one, but it can be respelled
+	uprisingID := saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	err = s.UpdateSong(ctx, entities.Song{ID: uprisingID, Song: "hysteria"})
+	assertDuplicate(t, err, id)
+	err = s.ReplaceSong(ctx, entities.Song{ID: uprisingID, Song: "Hysteria", Group: "Muse"})
+	assertDuplicate(t, err, id)
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Song: "HYSTERIA"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Uprising", getSong(t, s, uprisingID).Song)
+
+	//a song in the trash does not count, but it can not be restored then
+	err = s.RemoveSong(ctx, id, 0)
+	assert.NoError(t, err)
+	newID := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+	err = s.RestoreSong(ctx, id)
+	assertDuplicate(t, err, newID)
+
+	//a revert which makes a duplicate is refused too
+	err = s.UpdateSong(ctx, entities.Song{ID: newID, Song: "Hysteria (Live)"})
+	assert.NoError(t, err)
+	err = s.UpdateSong(ctx, entities.Song{ID: uprisingID, Song: "Hysteria"})
+	assert.NoError(t, err)
+	_, err = s.RevertSong(ctx, newID, 1)
+	assertDuplicate(t, err, uprisingID)
+
+	imported, err := s.ImportSongs(ctx, []entities.Song{{Song: "Hystéria", Group: "Muse"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ImportedSong{{ID: uprisingID, Existed: true}}, imported)
+}
+
+func testGetDuplicateSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	starlightID := saveSong(t, s, entities.Song{Song: "Starlight", Group: "Muse"})
+	liveID := saveSong(t, s, entities.Song{Song: "Starlight (Live)", Group: "Muse", Text: "Far away"})
+	saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	saveSong(t, s, entities.Song{Song: "Starlight", Group: "Queen"})
+	rhapsodyID := saveSong(t, s, entities.Song{Song: "Bohemian Rhapsody", Group: "Queen"})
+	remasteredID := saveSong(t, s, entities.Song{Song: "Bohemian Rhapsody (Remastered)", Group: "Queen"})
+	trashedID := saveSong(t, s, entities.Song{Song: "Bohemian Rhapsody (Live)", Group: "Queen"})
+	err := s.RemoveSong(ctx, trashedID, 0)
+	assert.NoError(t, err)
+
+	duplicates, err := s.GetDuplicateSongs(ctx, entities.DuplicateSimilarity, 0, 10)
+	assert.NoError(t, err)
+	if !assert.Len(t, duplicates, 2) {
+		return
+	}
+	assert.Equal(t, []uint64{starlightID, liveID}, songIDs(duplicates[0].Songs))
+	assert.Equal(t, []string{"Muse", "Muse"}, []string{duplicates[0].Songs[0].Group, duplicates[0].Songs[1].Group})
+	assert.InDelta(t, 0.67, duplicates[0].Similarity, 0.01)
+	assert.Equal(t, liveID, duplicates[0].KeepID)
+	assert.Equal(t, []uint64{rhapsodyID, remasteredID}, songIDs(duplicates[1].Songs))
+	assert.InDelta(t, 0.64, duplicates[1].Similarity, 0.01)
+
+	page, err := s.GetDuplicateSongs(ctx, entities.DuplicateSimilarity, 1, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, duplicates[1], page[0])
+	}
+	_, err = s.GetDuplicateSongs(ctx, entities.DuplicateSimilarity, 2, 10)
+	assertNotFound(t, err)
+	_, err = s.GetDuplicateSongs(ctx, 0.9, 0, 10)
+	assertNotFound(t, err)
+}
+
+func testMergeSongs(t *testing.T, s Storage) {
+	ctx := entities.WithAuditInfo(context.Background(), entities.AuditInfo{Actor: "alice"})
+	id := saveSong(t, s, entities.Song{Song: "Starlight", Group: "Muse", ReleaseDate: entities.MustParseReleaseDate("2006")})
+	liveID := saveSong(t, s, entities.Song{Song: "Starlight (Live)", Group: "Muse", Text: "Far away", Link: "https://example.com/starlight",
+		ReleaseDate: entities.MustParseReleaseDate("2006-09-04")})
+	uprisingID := saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	queenID := saveSong(t, s, entities.Song{Song: "Starlight", Group: "Queen"})
+
+	//nothing is merged unless all duplicates can be
+	_, err := s.MergeSongs(ctx, id, []uint64{uprisingID, queenID})
+	assertInvalid(t, err)
+	_, err = s.MergeSongs(ctx, id, []uint64{id})
+	assertInvalid(t, err)
+	_, err = s.MergeSongs(ctx, id, []uint64{uprisingID, missingID})
+	assertNotFound(t, err)
+	_, err = s.MergeSongs(ctx, missingID, []uint64{uprisingID})
+	assertNotFound(t, err)
+	getSong(t, s, uprisingID)
+
+	merged, err := s.MergeSongs(ctx, id, []uint64{liveID, liveID})
+	assert.NoError(t, err)
+	assert.Equal(t, id, merged.ID)
+	assert.Equal(t, "Starlight", merged.Song)
+	assert.Equal(t, "Muse", merged.Group)
+	assert.Equal(t, "Far away", merged.Text)
+	assert.Equal(t, "https://example.com/starlight", merged.Link)
+	assert.Equal(t, "2006-09-04", merged.ReleaseDate.String())
+	assert.Equal(t, int64(2), merged.Version)
+	assert.Equal(t, merged, getSong(t, s, id))
+
+	//duplicates go to the trash, both songs remember the merge
+	_, err = s.GetSong(ctx, liveID)
+	assertNotFound(t, err)
+	trash, err := s.GetTrash(ctx, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, trash, 1) {
+		assert.Equal(t, liveID, trash[0].ID)
+	}
+	history, err := s.GetSongHistory(ctx, id, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, entities.RevisionMerge, history[0].Action)
+		assert.Equal(t, "alice", history[0].Actor)
+	}
+	history, err = s.GetSongHistory(ctx, liveID, 0, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, entities.RevisionDelete, history[0].Action)
+	}
+}
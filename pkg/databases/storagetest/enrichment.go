@@ -0,0 +1,178 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"testing"
+	"time"
+)
+
+var enrichmentTests = []test{
+	{name: "Enrichment jobs", run: testEnrichmentJobs},
+	{name: "CompleteEnrichmentJob", run: testCompleteEnrichmentJob},
+	{name: "FailEnrichmentJob", run: testFailEnrichmentJob},
+	{name: "Extra data cache", run: testExtraDataCache},
+}
+
+func testEnrichmentJobs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	_, err := s.ClaimEnrichmentJob(ctx, time.Minute)
+	assertNotFound(t, err)
+
+	//a pending song gets a job
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", EnrichmentStatus: entities.EnrichmentPending})
+	saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	job, err := s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, id, job.SongID)
+	assert.Equal(t, 1, job.Attempts)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), job.NextAttemptAt, 10*time.Second)
+
+	//a claimed job is hidden for the lease
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assertNotFound(t, err)
+
+	//a rescheduled job is due at its time
+	err = s.RescheduleEnrichmentJob(ctx, id, time.Now().Add(-time.Second), "provider is down")
+	assert.NoError(t, err)
+	job, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, "provider is down", job.LastError)
+	err = s.RescheduleEnrichmentJob(ctx, missingID, time.Now(), "")
+	assertNotFound(t, err)
+
+	//enqueueing starts the job over
+	err = s.EnqueueEnrichment(ctx, id)
+	assert.NoError(t, err)
+	job, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Empty(t, job.LastError)
+
+	//any song can be enqueued
+	otherID := saveSong(t, s, entities.Song{Song: "Innuendo", Group: "Queen", EnrichmentStatus: entities.EnrichmentEnriched})
+	err = s.EnqueueEnrichment(ctx, otherID)
+	assert.NoError(t, err)
+	other := getSong(t, s, otherID)
+	assert.Equal(t, entities.EnrichmentPending, other.EnrichmentStatus)
+	assert.Equal(t, int64(2), other.Version)
+	assertLastChange(t, s, otherID, entities.FieldChange{Field: "enrichment_status", Before: "enriched", After: "pending"})
+	job, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, otherID, job.SongID)
+
+	err = s.EnqueueEnrichment(ctx, missingID)
+	assertNotFound(t, err)
+}
+
+func testCompleteEnrichmentJob(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", Link: "https://example.com/hysteria", EnrichmentStatus: entities.EnrichmentPending})
+
+	//empty fields are filled, fields which have a value are kept
+	err := s.CompleteEnrichmentJob(ctx, id, entities.ExtraSongData{
+		ReleaseDate: "2003-12-01",
+		Text:        "It's bugging me",
+		Link:        "https://example.org/hysteria",
+		Sources:     map[string]string{entities.FieldReleaseDate: "musicbrainz", entities.FieldText: "lyrics", entities.FieldLink: "lyrics"},
+	})
+	assert.NoError(t, err)
+	song := getSong(t, s, id)
+	assert.Equal(t, "2003-12-01", song.ReleaseDateString())
+	assert.Equal(t, "It's bugging me", song.Text)
+	assert.Equal(t, "https://example.com/hysteria", song.Link)
+	assert.Equal(t, map[string]string{entities.FieldReleaseDate: "musicbrainz", entities.FieldText: "lyrics"}, song.Sources)
+	assert.Equal(t, entities.EnrichmentEnriched, song.EnrichmentStatus)
+	assert.Equal(t, int64(2), song.Version)
+
+	//the job is removed
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assertNotFound(t, err)
+
+	err = s.CompleteEnrichmentJob(ctx, missingID, entities.ExtraSongData{Text: "It's bugging me"})
+	assertNotFound(t, err)
+}
+
+func testFailEnrichmentJob(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", EnrichmentStatus: entities.EnrichmentPending})
+
+	err := s.FailEnrichmentJob(ctx, id, "song is unknown")
+	assert.NoError(t, err)
+	song := getSong(t, s, id)
+	assert.Equal(t, entities.EnrichmentFailed, song.EnrichmentStatus)
+	assert.Equal(t, int64(2), song.Version)
+	assertLastChange(t, s, id, entities.FieldChange{Field: "enrichment_status", Before: "pending", After: "failed"})
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assertNotFound(t, err)
+
+	//a purged song loses its job
+	otherID := saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse", EnrichmentStatus: entities.EnrichmentPending})
+	err = s.RemoveSong(ctx, otherID, 0)
+	assert.NoError(t, err)
+	_, err = s.PurgeTrash(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	_, err = s.ClaimEnrichmentJob(ctx, time.Minute)
+	assertNotFound(t, err)
+}
+
+func testExtraDataCache(t *testing.T, s Storage) {
+	ctx := context.Background()
+	_, err := s.GetCachedExtraData(ctx, "muse\nhysteria")
+	assertNotFound(t, err)
+
+	entry := entities.CachedExtraData{
+		Key:       "muse\nhysteria",
+		Text:      "It's bugging me",
+		Sources:   map[string]string{entities.FieldText: "lyrics"},
+		ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Millisecond),
+	}
+	err = s.SaveCachedExtraData(ctx, entry)
+	assert.NoError(t, err)
+	cached, err := s.GetCachedExtraData(ctx, entry.Key)
+	assert.NoError(t, err)
+	assert.Equal(t, entry.Text, cached.Text)
+	assert.Equal(t, entry.Sources, cached.Sources)
+	assert.False(t, cached.NotFound)
+	assert.True(t, entry.ExpiresAt.Equal(cached.ExpiresAt))
+
+	//an entry with the same key is replaced
+	err = s.SaveCachedExtraData(ctx, entities.CachedExtraData{Key: entry.Key, NotFound: true, ExpiresAt: entry.ExpiresAt})
+	assert.NoError(t, err)
+	cached, err = s.GetCachedExtraData(ctx, entry.Key)
+	assert.NoError(t, err)
+	assert.True(t, cached.NotFound)
+	assert.Empty(t, cached.Text)
+
+	//expired entries are not returned
+	err = s.SaveCachedExtraData(ctx, entities.CachedExtraData{Key: entry.Key, Text: "It's bugging me", ExpiresAt: time.Now().Add(-time.Second)})
+	assert.NoError(t, err)
+	_, err = s.GetCachedExtraData(ctx, entry.Key)
+	assertNotFound(t, err)
+
+	//expired entries are purged, others are kept
+	err = s.SaveCachedExtraData(ctx, entities.CachedExtraData{Key: "muse\nuprising", NotFound: true, ExpiresAt: entry.ExpiresAt})
+	assert.NoError(t, err)
+	purged, err := s.PurgeCachedExtraData(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	purged, err = s.PurgeCachedExtraData(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	cached, err = s.GetCachedExtraData(ctx, "muse\nuprising")
+	assert.NoError(t, err)
+	assert.True(t, cached.NotFound)
+}
+
+// assertLastChange checks that the latest revision of the song is an update of a single field.
+func assertLastChange(t *testing.T, s Storage, id uint64, change entities.FieldChange) {
+	t.Helper()
+	history, err := s.GetSongHistory(context.Background(), id, 0, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, entities.RevisionUpdate, history[0].Action)
+		assert.Equal(t, []entities.FieldChange{change}, history[0].Changes)
+	}
+}
@@ -0,0 +1,312 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"testing"
+)
+
+var groupTests = []test{
+	{name: "SaveGroup and GetGroup", run: testSaveGroup},
+	{name: "GetGroupList", run: testGroupList},
+	{name: "UpdateGroup", run: testUpdateGroup},
+	{name: "RemoveGroup", run: testRemoveGroup},
+}
+
+var albumTests = []test{
+	{name: "SaveAlbum and GetAlbum", run: testSaveAlbum},
+	{name: "GetAlbumList", run: testAlbumList},
+	{name: "GetAlbumTracks", run: testAlbumTracks},
+	{name: "UpdateAlbum", run: testUpdateAlbum},
+	{name: "RemoveAlbum", run: testRemoveAlbum},
+}
+
+func testSaveGroup(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveGroup(t, s, entities.Group{Name: "  The   Beatles ", Aliases: []string{"Fab Four"}, Country: "UK", FormedYear: 1960})
+
+	group, err := s.GetGroup(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, group.ID)
+	assert.Equal(t, "The Beatles", group.Name)
+	assert.Equal(t, []string{"Fab Four"}, group.Aliases)
+	assert.Equal(t, "UK", group.Country)
+	assert.Equal(t, 1960, group.FormedYear)
+
+	//names and aliases are unique regardless of case and spaces
+	_, err = s.SaveGroup(ctx, entities.Group{Name: "the beatles"})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	_, err = s.SaveGroup(ctx, entities.Group{Name: "FAB  FOUR"})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	_, err = s.SaveGroup(ctx, entities.Group{Name: "The Quarrymen", Aliases: []string{"THE BEATLES"}})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	_, err = s.SaveGroup(ctx, entities.Group{Name: "The Quarrymen", Aliases: []string{"fab four"}})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+
+	//songs find the group by its alias
+	songID := saveSong(t, s, entities.Song{Song: "Yesterday", Group: "fab four"})
+	song := getSong(t, s, songID)
+	assert.Equal(t, id, song.GroupID)
+	assert.Equal(t, "The Beatles", song.Group)
+
+	_, err = s.GetGroup(ctx, missingID)
+	assertNotFound(t, err)
+}
+
+func testGroupList(t *testing.T, s Storage) {
+	ctx := context.Background()
+	saveGroup(t, s, entities.Group{Name: "Muse", Country: "UK", FormedYear: 1994})
+	saveGroup(t, s, entities.Group{Name: "Queen", Country: "UK", FormedYear: 1970})
+	saveGroup(t, s, entities.Group{Name: "Queens of the Stone Age", Country: "US", FormedYear: 1996})
+	saveGroup(t, s, entities.Group{Name: "100% Queen", Country: "DE", FormedYear: 2010})
+
+	tests := []struct {
+		name     string
+		filter   entities.Group
+		offset   int
+		limit    int
+		expected []string
+	}{
+		{name: "All", limit: 10, expected: []string{"Muse", "Queen", "Queens of the Stone Age", "100% Queen"}},
+		{name: "Name contains", filter: entities.Group{Name: "QUEEN"}, limit: 10, expected: []string{"Queen", "Queens of the Stone Age", "100% Queen"}},
+		{name: "Name is matched literally", filter: entities.Group{Name: "0%"}, limit: 10, expected: []string{"100% Queen"}},
+		{name: "Underscore is not a wildcard", filter: entities.Group{Name: "Mu_e"}, limit: 10},
+		{name: "Country ignores case", filter: entities.Group{Country: "uk"}, limit: 10, expected: []string{"Muse", "Queen"}},
+		{name: "Country is matched whole", filter: entities.Group{Country: "U"}, limit: 10},
+		{name: "Formed year", filter: entities.Group{FormedYear: 1970}, limit: 10, expected: []string{"Queen"}},
+		{name: "Offset and limit", offset: 1, limit: 1, expected: []string{"Queen"}},
+		{name: "Offset after the end", offset: 4, limit: 10},
+		{name: "Nothing matches", filter: entities.Group{Name: "Beatles"}, limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := s.GetGroupList(ctx, tt.filter, tt.offset, tt.limit)
+
+			if tt.expected == nil {
+				assertNotFound(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			names := make([]string, 0, len(groups))
+			for _, group := range groups {
+				names = append(names, group.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func testUpdateGroup(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveGroup(t, s, entities.Group{Name: "Muse", Country: "UK"})
+	saveGroup(t, s, entities.Group{Name: "Queen", Aliases: []string{"The Queen"}})
+
+	//non-empty fields are updated
+	err := s.UpdateGroup(ctx, entities.Group{ID: id, FormedYear: 1994})
+	assert.NoError(t, err)
+	group, err := s.GetGroup(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.Group{ID: id, Name: "Muse", NormalizedName: group.NormalizedName, Country: "UK", FormedYear: 1994}, group)
+
+	//a group keeps its own name in other case
+	err = s.UpdateGroup(ctx, entities.Group{ID: id, Name: " MUSE "})
+	assert.NoError(t, err)
+	group, err = s.GetGroup(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "MUSE", group.Name)
+
+	//but can not take a name or an alias of another group
+	err = s.UpdateGroup(ctx, entities.Group{ID: id, Name: "queen"})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	err = s.UpdateGroup(ctx, entities.Group{ID: id, Name: "The Queen"})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	err = s.UpdateGroup(ctx, entities.Group{ID: id, Aliases: []string{"QUEEN"}})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	err = s.UpdateGroup(ctx, entities.Group{ID: id, Aliases: []string{"the  queen"}})
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+
+	//own name can be an alias as well
+	err = s.UpdateGroup(ctx, entities.Group{ID: id, Aliases: []string{"muse"}})
+	assert.NoError(t, err)
+
+	err = s.UpdateGroup(ctx, entities.Group{ID: missingID, Country: "UK"})
+	assertNotFound(t, err)
+}
+
+func testRemoveGroup(t *testing.T, s Storage) {
+	ctx := context.Background()
+	songID := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+	museID := getSong(t, s, songID).GroupID
+	albumID, err := s.SaveAlbum(ctx, entities.Album{Title: "A Night at the Opera", Group: "Queen"})
+	assert.NoError(t, err)
+	album, err := s.GetAlbum(ctx, albumID)
+	assert.NoError(t, err)
+	emptyID := saveGroup(t, s, entities.Group{Name: "Nobody"})
+
+	//groups with songs or albums are kept
+	err = s.RemoveGroup(ctx, museID)
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+	err = s.RemoveGroup(ctx, album.GroupID)
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+
+	//songs in the trash count too
+	err = s.RemoveSong(ctx, songID, 0)
+	assert.NoError(t, err)
+	err = s.RemoveGroup(ctx, museID)
+	assert.ErrorIs(t, err, dberrors.NewConflictErr())
+
+	err = s.RemoveGroup(ctx, emptyID)
+	assert.NoError(t, err)
+	_, err = s.GetGroup(ctx, emptyID)
+	assertNotFound(t, err)
+	err = s.RemoveGroup(ctx, emptyID)
+	assertNotFound(t, err)
+}
+
+func testSaveAlbum(t *testing.T, s Storage) {
+	ctx := context.Background()
+	museID := saveGroup(t, s, entities.Group{Name: "Muse"})
+
+	//the group is resolved by its name
+	id, err := s.SaveAlbum(ctx, entities.Album{Title: "Absolution", Group: "muse", ReleaseDate: "2003-09-15", CoverLink: "https://example.com/absolution.jpg"})
+	assert.NoError(t, err)
+	album, err := s.GetAlbum(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.Album{ID: id, Title: "Absolution", GroupID: museID, Group: "Muse", ReleaseDate: "2003-09-15", CoverLink: "https://example.com/absolution.jpg"}, album)
+
+	//or created
+	id, err = s.SaveAlbum(ctx, entities.Album{Title: "Innuendo", Group: "Queen"})
+	assert.NoError(t, err)
+	album, err = s.GetAlbum(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Queen", album.Group)
+	assert.NotEqual(t, museID, album.GroupID)
+
+	_, err = s.SaveAlbum(ctx, entities.Album{Title: "Absolution", GroupID: missingID})
+	assertInvalid(t, err)
+	_, err = s.GetAlbum(ctx, missingID)
+	assertNotFound(t, err)
+}
+
+func testAlbumList(t *testing.T, s Storage) {
+	ctx := context.Background()
+	for _, album := range []entities.Album{
+		{Title: "Absolution", Group: "Muse", ReleaseDate: "2003"},
+		{Title: "The Resistance", Group: "Muse", ReleaseDate: "2009"},
+		{Title: "Innuendo", Group: "Queen", ReleaseDate: "1991"},
+	} {
+		_, err := s.SaveAlbum(ctx, album)
+		assert.NoError(t, err)
+	}
+	queen, err := s.GetGroupList(ctx, entities.Group{Name: "Queen"}, 0, 1)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		filter   entities.Album
+		offset   int
+		limit    int
+		expected []string
+	}{
+		{name: "All", limit: 10, expected: []string{"Absolution", "The Resistance", "Innuendo"}},
+		{name: "Title contains", filter: entities.Album{Title: "RESIST"}, limit: 10, expected: []string{"The Resistance"}},
+		{name: "Group name contains", filter: entities.Album{Group: "us"}, limit: 10, expected: []string{"Absolution", "The Resistance"}},
+		{name: "Group ID", filter: entities.Album{GroupID: queen[0].ID}, limit: 10, expected: []string{"Innuendo"}},
+		{name: "Release date", filter: entities.Album{ReleaseDate: "2009"}, limit: 10, expected: []string{"The Resistance"}},
+		{name: "Offset and limit", offset: 1, limit: 1, expected: []string{"The Resistance"}},
+		{name: "Nothing matches", filter: entities.Album{Title: "Origin"}, limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			albums, err := s.GetAlbumList(ctx, tt.filter, tt.offset, tt.limit)
+
+			if tt.expected == nil {
+				assertNotFound(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			titles := make([]string, 0, len(albums))
+			for _, album := range albums {
+				titles = append(titles, album.Title)
+				assert.NotEmpty(t, album.Group)
+			}
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+}
+
+func testAlbumTracks(t *testing.T, s Storage) {
+	ctx := context.Background()
+	albumID, err := s.SaveAlbum(ctx, entities.Album{Title: "Absolution", Group: "Muse"})
+	assert.NoError(t, err)
+	emptyID, err := s.SaveAlbum(ctx, entities.Album{Title: "Origin of Symmetry", Group: "Muse"})
+	assert.NoError(t, err)
+
+	saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", AlbumID: &albumID, DiscNumber: 1, TrackNumber: 8})
+	saveSong(t, s, entities.Song{Song: "Stockholm Syndrome", Group: "Muse", AlbumID: &albumID, DiscNumber: 2, TrackNumber: 1})
+	saveSong(t, s, entities.Song{Song: "Apocalypse Please", Group: "Muse", AlbumID: &albumID, DiscNumber: 1, TrackNumber: 2})
+	trashedID := saveSong(t, s, entities.Song{Song: "Butterflies and Hurricanes", Group: "Muse", AlbumID: &albumID, DiscNumber: 1, TrackNumber: 10})
+	saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	err = s.RemoveSong(ctx, trashedID, 0)
+	assert.NoError(t, err)
+
+	tracks, err := s.GetAlbumTracks(ctx, albumID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apocalypse Please", "Hysteria", "Stockholm Syndrome"}, songNames(tracks))
+	for _, track := range tracks {
+		assert.Equal(t, "Muse", track.Group)
+	}
+
+	tracks, err = s.GetAlbumTracks(ctx, emptyID)
+	assert.NoError(t, err)
+	assert.Empty(t, tracks)
+
+	_, err = s.GetAlbumTracks(ctx, missingID)
+	assertNotFound(t, err)
+}
+
+func testUpdateAlbum(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id, err := s.SaveAlbum(ctx, entities.Album{Title: "Absolution", Group: "Muse", ReleaseDate: "2003"})
+	assert.NoError(t, err)
+
+	//non-empty fields are updated, a group name moves the album
+	err = s.UpdateAlbum(ctx, entities.Album{ID: id, CoverLink: "https://example.com/absolution.jpg", Group: "Queen"})
+	assert.NoError(t, err)
+	album, err := s.GetAlbum(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Absolution", album.Title)
+	assert.Equal(t, "2003", album.ReleaseDate)
+	assert.Equal(t, "https://example.com/absolution.jpg", album.CoverLink)
+	assert.Equal(t, "Queen", album.Group)
+
+	err = s.UpdateAlbum(ctx, entities.Album{ID: missingID, Title: "Origin"})
+	assertNotFound(t, err)
+}
+
+func testRemoveAlbum(t *testing.T, s Storage) {
+	ctx := context.Background()
+	albumID, err := s.SaveAlbum(ctx, entities.Album{Title: "Absolution", Group: "Muse"})
+	assert.NoError(t, err)
+	songID := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", AlbumID: &albumID, TrackNumber: 8})
+	trashedID := saveSong(t, s, entities.Song{Song: "Apocalypse Please", Group: "Muse", AlbumID: &albumID, TrackNumber: 2})
+	err = s.RemoveSong(ctx, trashedID, 0)
+	assert.NoError(t, err)
+
+	//songs lose their album, songs in the trash too
+	err = s.RemoveAlbum(ctx, albumID)
+	assert.NoError(t, err)
+	_, err = s.GetAlbum(ctx, albumID)
+	assertNotFound(t, err)
+	song := getSong(t, s, songID)
+	assert.Nil(t, song.AlbumID)
+	assert.Equal(t, 8, song.TrackNumber)
+	err = s.RestoreSong(ctx, trashedID)
+	assert.NoError(t, err)
+	assert.Nil(t, getSong(t, s, trashedID).AlbumID)
+
+	err = s.RemoveAlbum(ctx, albumID)
+	assertNotFound(t, err)
+}
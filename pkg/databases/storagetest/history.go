@@ -0,0 +1,99 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"testing"
+)
+
+var historyTests = []test{
+	{name: "GetSongHistory", run: testSongHistory},
+	{name: "RevertSong", run: testRevertSong},
+}
+
+func testSongHistory(t *testing.T, s Storage) {
+	ctx := entities.WithAuditInfo(context.Background(), entities.AuditInfo{Actor: "alice", RequestID: "req-1"})
+	id, err := s.SaveSong(ctx, entities.Song{Song: "Hysteria", Group: "Muse"})
+	assert.NoError(t, err)
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Text: "It's bugging me"})
+	assert.NoError(t, err)
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Text: "It's bugging me"})
+	assert.NoError(t, err)
+	err = s.RemoveSong(ctx, id, 0)
+	assert.NoError(t, err)
+
+	//songs in the trash have a history too, an update which changed nothing is not recorded
+	history, err := s.GetSongHistory(ctx, id, 0, 10)
+	assert.NoError(t, err)
+	if !assert.Len(t, history, 3) {
+		return
+	}
+	assert.Equal(t, 3, history[0].Revision)
+	assert.Equal(t, entities.RevisionDelete, history[0].Action)
+	assert.Equal(t, 2, history[1].Revision)
+	assert.Equal(t, entities.RevisionUpdate, history[1].Action)
+	assert.Equal(t, []entities.FieldChange{{Field: "text", Before: nil, After: "It's bugging me"}}, history[1].Changes)
+	assert.Equal(t, 1, history[2].Revision)
+	assert.Equal(t, entities.RevisionCreate, history[2].Action)
+	for _, revision := range history {
+		assert.Equal(t, id, revision.SongID)
+		assert.Equal(t, "alice", revision.Actor)
+		assert.Equal(t, "req-1", revision.RequestID)
+		assert.False(t, revision.CreatedAt.IsZero())
+	}
+	if assert.NotNil(t, history[1].Song) {
+		assert.Equal(t, "Muse", history[1].Song.Group)
+		assert.Equal(t, "It's bugging me", history[1].Song.Text)
+		assert.Equal(t, int64(2), history[1].Song.Version)
+	}
+
+	page, err := s.GetSongHistory(ctx, id, 1, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, 2, page[0].Revision)
+	}
+	page, err = s.GetSongHistory(ctx, id, 3, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, page)
+
+	_, err = s.GetSongHistory(ctx, missingID, 0, 10)
+	assertNotFound(t, err)
+}
+
+func testRevertSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", Link: "https://example.com/hysteria"})
+	museID := getSong(t, s, id).GroupID
+	err := s.UpdateSong(ctx, entities.Song{ID: id, Song: "Hysteria (Live)", Text: "It's bugging me", Group: "Queen"})
+	assert.NoError(t, err)
+
+	//a removed group is found by its name again
+	err = s.RemoveGroup(ctx, museID)
+	assert.NoError(t, err)
+	reverted, err := s.RevertSong(ctx, id, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hysteria", reverted.Song)
+	assert.Equal(t, "Muse", reverted.Group)
+	assert.NotEqual(t, museID, reverted.GroupID)
+	assert.Empty(t, reverted.Text)
+	assert.Equal(t, "https://example.com/hysteria", reverted.Link)
+	assert.Equal(t, int64(3), reverted.Version)
+	assert.Equal(t, reverted, getSong(t, s, id))
+
+	history, err := s.GetSongHistory(ctx, id, 0, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, entities.RevisionRevert, history[0].Action)
+		assert.Equal(t, 3, history[0].Revision)
+	}
+
+	_, err = s.RevertSong(ctx, id, 10)
+	assertNotFound(t, err)
+	_, err = s.RevertSong(ctx, missingID, 1)
+	assertNotFound(t, err)
+	err = s.RemoveSong(ctx, id, 0)
+	assert.NoError(t, err)
+	_, err = s.RevertSong(ctx, id, 1)
+	assertNotFound(t, err)
+}
@@ -0,0 +1,125 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"testing"
+	"time"
+)
+
+var importExportTests = []test{
+	{name: "ImportSongs", run: testImportSongs},
+	{name: "ImportSongs with missing references", run: testImportSongsMissingReferences},
+	{name: "ExportSongs", run: testExportSongs},
+}
+
+func testImportSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	existingID := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+	trashedID := saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	err := s.RemoveSong(ctx, trashedID, 0)
+	assert.NoError(t, err)
+
+	imported, err := s.ImportSongs(ctx, []entities.Song{
+		{Song: "HYSTERIA ", Group: "muse"},
+		{Song: "Innuendo", Group: "Queen", EnrichmentStatus: entities.EnrichmentPending},
+		{Song: "innuendo", Group: "QUEEN"},
+		{Song: "Uprising", Group: "Muse"},
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, imported, 4) {
+		return
+	}
+
+	//songs in the library and earlier in the batch are not saved, songs in the trash are not looked at
+	assert.Equal(t, entities.ImportedSong{ID: existingID, Existed: true}, imported[0])
+	assert.False(t, imported[1].Existed)
+	assert.Equal(t, entities.ImportedSong{ID: imported[1].ID, Existed: true}, imported[2])
+	assert.False(t, imported[3].Existed)
+	assert.NotEqual(t, trashedID, imported[3].ID)
+
+	song := getSong(t, s, imported[1].ID)
+	assert.Equal(t, "Innuendo", song.Song)
+	assert.Equal(t, "Queen", song.Group)
+	assert.Equal(t, int64(1), song.Version)
+	history, err := s.GetSongHistory(ctx, song.ID, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, entities.RevisionCreate, history[0].Action)
+	}
+
+	//pending songs get jobs
+	job, err := s.ClaimEnrichmentJob(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, song.ID, job.SongID)
+
+	imported, err = s.ImportSongs(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, imported)
+}
+
+func testImportSongsMissingReferences(t *testing.T, s Storage) {
+	ctx := context.Background()
+	albumID := uint64(missingID)
+
+	for _, songs := range [][]entities.Song{
+		{{Song: "Hysteria", Group: "Muse"}, {Song: "Innuendo", GroupID: missingID}},
+		{{Song: "Hysteria", Group: "Muse"}, {Song: "Innuendo", Group: "Queen", AlbumID: &albumID}},
+		{{Song: "Hysteria", Group: "Muse"}, {Song: "Innuendo", Group: " "}},
+	} {
+		_, err := s.ImportSongs(ctx, songs)
+		assertInvalid(t, err)
+	}
+
+	//nothing was saved
+	_, err := s.GetGroupList(ctx, entities.Group{}, 0, 10)
+	assertNotFound(t, err)
+	_, err = s.GetSongList(ctx, entities.SongListQuery{})
+	assertNotFound(t, err)
+}
+
+func testExportSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	ids := saveLibrary(t, s)
+	err := s.RemoveSong(ctx, ids[1], 0)
+	assert.NoError(t, err)
+
+	//songs go in the order of IDs, songs in the trash are not exported
+	var exported []entities.Song
+	err = s.ExportSongs(ctx, entities.SongFilter{}, func(song entities.Song) error {
+		exported = append(exported, song)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hysteria", "Bohemian Rhapsody", "Uprising", "Innuendo"}, songNames(exported))
+	if assert.Len(t, exported, 4) {
+		assert.Equal(t, getSong(t, s, ids[0]), exported[0])
+	}
+
+	exported = nil
+	err = s.ExportSongs(ctx, entities.SongFilter{Group: "queen", GroupMatch: entities.MatchExact}, func(song entities.Song) error {
+		exported = append(exported, song)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bohemian Rhapsody", "Innuendo"}, songNames(exported))
+
+	//nothing matches
+	err = s.ExportSongs(ctx, entities.SongFilter{Song: "Radio"}, func(song entities.Song) error {
+		t.Errorf("unexpected song %q", song.Song)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	//an error of fn stops the export
+	errStop := errors.New("stop")
+	calls := 0
+	err = s.ExportSongs(ctx, entities.SongFilter{}, func(song entities.Song) error {
+		calls++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,165 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"testing"
+)
+
+var searchTests = []test{
+	{name: "SearchSongs", run: testSearchSongs},
+	{name: "SearchSongs escapes HTML", run: testSearchSongsEscapesHTML},
+	{name: "SearchSongs fuzzy", run: testFuzzySearchSongs},
+	{name: "SuggestNames", run: testSuggestNames},
+}
+
+// searchSongs are songs with lyrics of several couplets. Words of the lyrics stem to themselves,
+// so backends with and without stemming find the same songs.
+var searchSongs = []entities.Song{
+	{Song: "Hysteria", Group: "Muse", Text: "It's bugging me\n\nGrating me\n\nAnd twisting me around"},
+	{Song: "Starlight", Group: "Muse", Text: "Far away\n\nThe ship is taking me far away"},
+	{Song: "Innuendo", Group: "Queen", Text: "While the sun hangs in the sky"},
+}
+
+func testSearchSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	for _, song := range searchSongs {
+		saveSong(t, s, song)
+	}
+
+	tests := []struct {
+		name     string
+		query    entities.SearchQuery
+		expected []string
+		headline string
+	}{
+		{name: "Word in lyrics", query: entities.SearchQuery{Text: "twisting"}, expected: []string{"Hysteria"}, headline: "And <mark>twisting</mark> me around"},
+		{name: "Song name", query: entities.SearchQuery{Text: "STARLIGHT"}, expected: []string{"Starlight"}},
+		// "me" is a stop word in english
+		{name: "More occurrences go first", query: entities.SearchQuery{Text: "me", Language: "simple"}, expected: []string{"Hysteria", "Starlight"}},
+		{name: "Offset and limit", query: entities.SearchQuery{Text: "me", Language: "simple", Offset: 1, Limit: 1}, expected: []string{"Starlight"}},
+		{name: "All words", query: entities.SearchQuery{Text: "ship away"}, expected: []string{"Starlight"}},
+		{name: "Quoted phrase", query: entities.SearchQuery{Text: `"far away"`}, expected: []string{"Starlight"}, headline: "<mark>Far</mark> <mark>away</mark>"},
+		{name: "Phrase", query: entities.SearchQuery{Text: "taking me", Language: "simple", Phrase: true}, expected: []string{"Starlight"}},
+		{name: "Phrase in another order", query: entities.SearchQuery{Text: "away far", Phrase: true}},
+		{name: "Excluded word", query: entities.SearchQuery{Text: "ship -starlight"}},
+		{name: "Nothing matches", query: entities.SearchQuery{Text: "moon"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := s.SearchSongs(ctx, tt.query)
+
+			if tt.expected == nil {
+				assertNotFound(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			names := make([]string, 0, len(results))
+			for i, result := range results {
+				names = append(names, result.Song.Song)
+				assert.NotEmpty(t, result.Song.Group)
+				assert.Greater(t, result.Rank, 0.0)
+				if i > 0 {
+					assert.GreaterOrEqual(t, results[i-1].Rank, result.Rank)
+				}
+			}
+			assert.Equal(t, tt.expected, names)
+			if tt.headline != "" && len(results) > 0 {
+				assert.Equal(t, tt.headline, results[0].Headline)
+			}
+		})
+	}
+
+	//either alternative matches
+	results, err := s.SearchSongs(ctx, entities.SearchQuery{Text: "grating or sun"})
+	assert.NoError(t, err)
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Song.Song)
+	}
+	assert.ElementsMatch(t, []string{"Hysteria", "Innuendo"}, names)
+}
+
+func testSearchSongsEscapesHTML(t *testing.T, s Storage) {
+	ctx := context.Background()
+	saveSong(t, s, entities.Song{Song: "Escape", Group: "Journey",
+		Text: "Hold the <script>alert(\"line\")</script> & <b>line</b>\n\nWe'll be fine"})
+
+	//only the marks are tags, whether the headline is a couplet or a part of the lyrics
+	for _, text := range []string{"hold", "escape"} {
+		results, err := s.SearchSongs(ctx, entities.SearchQuery{Text: text, Language: "simple"})
+		assert.NoError(t, err)
+		if assert.Len(t, results, 1) {
+			assert.Contains(t, results[0].Headline, "the &lt;script&gt;alert(&quot;line&quot;)")
+			assert.NotContains(t, results[0].Headline, "<script>")
+			assert.NotContains(t, results[0].Headline, "<b>")
+		}
+	}
+	results, err := s.SearchSongs(ctx, entities.SearchQuery{Text: "hold", Language: "simple"})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "<mark>Hold</mark> the &lt;script&gt;alert(&quot;line&quot;)&lt;/script&gt; &amp; &lt;b&gt;line&lt;/b&gt;", results[0].Headline)
+	}
+}
+
+func testFuzzySearchSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	var ids []uint64
+	for _, song := range searchSongs {
+		ids = append(ids, saveSong(t, s, song))
+	}
+
+	results, err := s.SearchSongs(ctx, entities.SearchQuery{Text: "Hysterya", Mode: entities.SearchFuzzy})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Hysteria", results[0].Song.Song)
+		assert.Greater(t, results[0].Rank, entities.DefaultSimilarity)
+		assert.Empty(t, results[0].Headline)
+	}
+
+	//group names are matched too
+	results, err = s.SearchSongs(ctx, entities.SearchQuery{Text: "Queeen", Mode: entities.SearchFuzzy})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Innuendo", results[0].Song.Song)
+	}
+
+	_, err = s.SearchSongs(ctx, entities.SearchQuery{Text: "Hysterya", Mode: entities.SearchFuzzy, Similarity: 0.9})
+	assertNotFound(t, err)
+
+	//removed songs are not found
+	err = s.RemoveSong(ctx, ids[0], 0)
+	assert.NoError(t, err)
+	_, err = s.SearchSongs(ctx, entities.SearchQuery{Text: "Hysterya", Mode: entities.SearchFuzzy})
+	assertNotFound(t, err)
+	_, err = s.SearchSongs(ctx, entities.SearchQuery{Text: "twisting"})
+	assertNotFound(t, err)
+}
+
+func testSuggestNames(t *testing.T, s Storage) {
+	ctx := context.Background()
+	var ids []uint64
+	for _, song := range searchSongs {
+		ids = append(ids, saveSong(t, s, song))
+	}
+
+	names, err := s.SuggestNames(ctx, "Hysterya", entities.DefaultSimilarity, entities.MaxSuggestions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hysteria"}, names)
+
+	names, err = s.SuggestNames(ctx, "Musee", entities.DefaultSimilarity, entities.MaxSuggestions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Muse"}, names)
+
+	names, err = s.SuggestNames(ctx, "Moon", entities.DefaultSimilarity, entities.MaxSuggestions)
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	//names of removed songs are not suggested
+	err = s.RemoveSong(ctx, ids[0], 0)
+	assert.NoError(t, err)
+	names, err = s.SuggestNames(ctx, "Hysterya", entities.DefaultSimilarity, entities.MaxSuggestions)
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
@@ -0,0 +1,237 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"testing"
+)
+
+var songListTests = []test{
+	{name: "GetSongList filters", run: testSongListFilters},
+	{name: "GetSongList sorts", run: testSongListSorts},
+	{name: "GetSongList pages", run: testSongListPages},
+	{name: "GetSongList pages of every sort", run: testSongListPagesOfEverySort},
+}
+
+// librarySongs are songs of several groups with and without optional fields, in the order of IDs.
+var librarySongs = []entities.Song{
+	{Song: "Hysteria", Group: "Muse", DiscNumber: 1, TrackNumber: 8, ReleaseDate: entities.MustParseReleaseDate("2003-12-01"), Text: "It's bugging me", Link: "https://example.com/hysteria"},
+	{Song: "Time Is Running Out", Group: "Muse", DiscNumber: 1, TrackNumber: 3, ReleaseDate: entities.MustParseReleaseDate("2003-09")},
+	{Song: "Bohemian Rhapsody", Group: "Queen", DiscNumber: 1, TrackNumber: 11, ReleaseDate: entities.MustParseReleaseDate("1975"), Text: "Is this the real life?"},
+	{Song: "Uprising", Group: "Muse", TrackNumber: 1, ReleaseDate: entities.MustParseReleaseDate("2009-09-07"), Link: "https://example.com/uprising"},
+	{Song: "Innuendo", Group: "Queen", TrackNumber: 1},
+}
+
+// saveLibrary saves librarySongs and returns their IDs.
+func saveLibrary(t *testing.T, s Storage) []uint64 {
+	ids := make([]uint64, 0, len(librarySongs))
+	for _, song := range librarySongs {
+		ids = append(ids, saveSong(t, s, song))
+	}
+	return ids
+}
+
+func testSongListFilters(t *testing.T, s Storage) {
+	ids := saveLibrary(t, s)
+	queenID := getSong(t, s, ids[2]).GroupID
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		filter   entities.SongFilter
+		expected []string
+	}{
+		{name: "No filter", expected: []string{"Hysteria", "Time Is Running Out", "Bohemian Rhapsody", "Uprising", "Innuendo"}},
+		{name: "IDs", filter: entities.SongFilter{IDs: []uint64{ids[2], ids[0], missingID}}, expected: []string{"Hysteria", "Bohemian Rhapsody"}},
+		{name: "Song contains", filter: entities.SongFilter{Song: "RUN"}, expected: []string{"Time Is Running Out"}},
+		{name: "Song prefix", filter: entities.SongFilter{Song: "hy", SongMatch: entities.MatchPrefix}, expected: []string{"Hysteria"}},
+		{name: "Song prefix in the middle", filter: entities.SongFilter{Song: "steria", SongMatch: entities.MatchPrefix}},
+		{name: "Song exact", filter: entities.SongFilter{Song: "HYSTERIA", SongMatch: entities.MatchExact}, expected: []string{"Hysteria"}},
+		{name: "Song exact part", filter: entities.SongFilter{Song: "Hyster", SongMatch: entities.MatchExact}},
+		{name: "Wildcards match themselves", filter: entities.SongFilter{Song: "%"}},
+		{name: "Group contains", filter: entities.SongFilter{Group: "uee"}, expected: []string{"Bohemian Rhapsody", "Innuendo"}},
+		{name: "Group exact", filter: entities.SongFilter{Group: "muse", GroupMatch: entities.MatchExact}, expected: []string{"Hysteria", "Time Is Running Out", "Uprising"}},
+		{name: "Group IDs", filter: entities.SongFilter{GroupIDs: []uint64{queenID}}, expected: []string{"Bohemian Rhapsody", "Innuendo"}},
+		{name: "Groups", filter: entities.SongFilter{Groups: []string{" QUEEN", "Nobody"}}, expected: []string{"Bohemian Rhapsody", "Innuendo"}},
+		{name: "Release year", filter: entities.SongFilter{ReleaseDate: entities.MustParseReleaseDate("2003")}, expected: []string{"Hysteria", "Time Is Running Out"}},
+		{name: "Release month", filter: entities.SongFilter{ReleaseDate: entities.MustParseReleaseDate("2003-09")}, expected: []string{"Time Is Running Out"}},
+		{name: "Release date from", filter: entities.SongFilter{ReleaseDateFrom: entities.MustParseReleaseDate("2003-10")}, expected: []string{"Hysteria", "Uprising"}},
+		{name: "Release date to", filter: entities.SongFilter{ReleaseDateTo: entities.MustParseReleaseDate("2003")}, expected: []string{"Hysteria", "Time Is Running Out", "Bohemian Rhapsody"}},
+		{name: "Release period", filter: entities.SongFilter{ReleaseDateFrom: entities.MustParseReleaseDate("1975-01-01"), ReleaseDateTo: entities.MustParseReleaseDate("2003-09-01")}, expected: []string{"Time Is Running Out", "Bohemian Rhapsody"}},
+		{name: "Has lyrics", filter: entities.SongFilter{HasLyrics: &yes}, expected: []string{"Hysteria", "Bohemian Rhapsody"}},
+		{name: "Has no lyrics", filter: entities.SongFilter{HasLyrics: &no}, expected: []string{"Time Is Running Out", "Uprising", "Innuendo"}},
+		{name: "Has link", filter: entities.SongFilter{HasLink: &yes}, expected: []string{"Hysteria", "Uprising"}},
+		{name: "All conditions match", filter: entities.SongFilter{Group: "muse", HasLyrics: &no, ReleaseDateFrom: entities.MustParseReleaseDate("2004")}, expected: []string{"Uprising"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.GetSongList(context.Background(), entities.SongListQuery{Filter: tt.filter, WithTotal: true})
+
+			if tt.expected == nil {
+				assertNotFound(t, err)
+				if assert.NotNil(t, page.Total) {
+					assert.Zero(t, *page.Total)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, songNames(page.Songs))
+			if assert.NotNil(t, page.Total) {
+				assert.Equal(t, int64(len(tt.expected)), *page.Total)
+			}
+		})
+	}
+}
+
+func testSongListSorts(t *testing.T, s Storage) {
+	saveLibrary(t, s)
+
+	tests := []struct {
+		sort     string
+		expected []string
+	}{
+		{sort: "id", expected: []string{"Hysteria", "Time Is Running Out", "Bohemian Rhapsody", "Uprising", "Innuendo"}},
+		{sort: "-id", expected: []string{"Innuendo", "Uprising", "Bohemian Rhapsody", "Time Is Running Out", "Hysteria"}},
+		{sort: "song", expected: []string{"Bohemian Rhapsody", "Hysteria", "Innuendo", "Time Is Running Out", "Uprising"}},
+		// songs of the same group go by ID in the same direction
+		{sort: "-group", expected: []string{"Innuendo", "Bohemian Rhapsody", "Uprising", "Time Is Running Out", "Hysteria"}},
+		// songs without a release date go first
+		{sort: "release_date", expected: []string{"Innuendo", "Bohemian Rhapsody", "Time Is Running Out", "Hysteria", "Uprising"}},
+		{sort: "-release_date", expected: []string{"Uprising", "Hysteria", "Time Is Running Out", "Bohemian Rhapsody", "Innuendo"}},
+		{sort: "track_number", expected: []string{"Uprising", "Innuendo", "Time Is Running Out", "Hysteria", "Bohemian Rhapsody"}},
+		// songs without a disc number go last
+		{sort: "-disc_number", expected: []string{"Bohemian Rhapsody", "Time Is Running Out", "Hysteria", "Innuendo", "Uprising"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			sort, err := entities.ParseSongSort(tt.sort)
+			assert.NoError(t, err)
+
+			page, err := s.GetSongList(context.Background(), entities.SongListQuery{Sort: sort})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, songNames(page.Songs))
+			assert.Nil(t, page.NextCursor)
+			assert.Nil(t, page.PrevCursor)
+		})
+	}
+}
+
+func testSongListPages(t *testing.T, s Storage) {
+	ctx := context.Background()
+	_, err := s.GetSongList(ctx, entities.SongListQuery{})
+	assertNotFound(t, err)
+	saveLibrary(t, s)
+	sort := entities.SongSort{Field: "release_date"}
+
+	//forward
+	first, err := s.GetSongList(ctx, entities.SongListQuery{Sort: sort, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Innuendo", "Bohemian Rhapsody"}, songNames(first.Songs))
+	assert.Nil(t, first.PrevCursor)
+	if !assert.NotNil(t, first.NextCursor) {
+		return
+	}
+	assert.Equal(t, "release_date", first.NextCursor.Sort)
+
+	second, err := s.GetSongList(ctx, entities.SongListQuery{Sort: sort, Limit: 2, Cursor: first.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Time Is Running Out", "Hysteria"}, songNames(second.Songs))
+	if !assert.NotNil(t, second.NextCursor) || !assert.NotNil(t, second.PrevCursor) {
+		return
+	}
+
+	//a song inserted before the cursor does not shift the next page
+	saveSong(t, s, entities.Song{Song: "Radio Ga Ga", Group: "Queen", ReleaseDate: entities.MustParseReleaseDate("1984")})
+	last, err := s.GetSongList(ctx, entities.SongListQuery{Sort: sort, Limit: 2, Cursor: second.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Uprising"}, songNames(last.Songs))
+	assert.Nil(t, last.NextCursor)
+	if !assert.NotNil(t, last.PrevCursor) {
+		return
+	}
+
+	//backward
+	previous, err := s.GetSongList(ctx, entities.SongListQuery{Sort: sort, Limit: 2, Cursor: last.PrevCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Time Is Running Out", "Hysteria"}, songNames(previous.Songs))
+	assert.NotNil(t, previous.NextCursor)
+	if !assert.NotNil(t, previous.PrevCursor) {
+		return
+	}
+	previous, err = s.GetSongList(ctx, entities.SongListQuery{Sort: sort, Limit: 2, Cursor: previous.PrevCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bohemian Rhapsody", "Radio Ga Ga"}, songNames(previous.Songs))
+	assert.NotNil(t, previous.PrevCursor)
+
+	//descending
+	desc, err := s.GetSongList(ctx, entities.SongListQuery{Sort: entities.SongSort{Field: "release_date", Desc: true}, Limit: 4})
+	assert.NoError(t, err)
+	if assert.NotNil(t, desc.NextCursor) {
+		desc, err = s.GetSongList(ctx, entities.SongListQuery{Sort: entities.SongSort{Field: "release_date", Desc: true}, Limit: 4, Cursor: desc.NextCursor})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Bohemian Rhapsody", "Innuendo"}, songNames(desc.Songs))
+	}
+
+	//offset
+	page, err := s.GetSongList(ctx, entities.SongListQuery{Offset: 5, Limit: 2, WithTotal: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Radio Ga Ga"}, songNames(page.Songs))
+	assert.NotNil(t, page.PrevCursor)
+	assert.Nil(t, page.NextCursor)
+	if assert.NotNil(t, page.Total) {
+		assert.Equal(t, int64(6), *page.Total)
+	}
+	_, err = s.GetSongList(ctx, entities.SongListQuery{Offset: 6})
+	assertNotFound(t, err)
+
+	//a broken cursor
+	_, err = s.GetSongList(ctx, entities.SongListQuery{Cursor: &entities.Cursor{Key: "not a number", ID: 1}})
+	assertInvalid(t, err)
+}
+
+func testSongListPagesOfEverySort(t *testing.T, s Storage) {
+	ctx := context.Background()
+	saveLibrary(t, s)
+	//songs with equal keys make pages break between them, a cover keeps the name out of the duplicate check
+	saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse Tribute", TrackNumber: 8, ReleaseDate: entities.MustParseReleaseDate("2003-12-01")})
+
+	for _, field := range entities.SongSortFields {
+		for _, desc := range []bool{false, true} {
+			sort := entities.SongSort{Field: field, Desc: desc}
+			t.Run(sort.String(), func(t *testing.T) {
+				all, err := s.GetSongList(ctx, entities.SongListQuery{Sort: sort})
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				//pages of two songs go through the same order there and back
+				var forward []uint64
+				var last entities.SongPage
+				for query := (entities.SongListQuery{Sort: sort, Limit: 2}); len(forward) <= len(all.Songs); {
+					last, err = s.GetSongList(ctx, query)
+					if !assert.NoError(t, err) {
+						return
+					}
+					forward = append(forward, songIDs(last.Songs)...)
+					if last.NextCursor == nil {
+						break
+					}
+					query.Cursor = last.NextCursor
+				}
+				assert.Equal(t, songIDs(all.Songs), forward)
+
+				backward := songIDs(last.Songs)
+				for page := last; page.PrevCursor != nil && len(backward) <= len(all.Songs); {
+					page, err = s.GetSongList(ctx, entities.SongListQuery{Sort: sort, Limit: 2, Cursor: page.PrevCursor})
+					if !assert.NoError(t, err) {
+						return
+					}
+					backward = append(songIDs(page.Songs), backward...)
+				}
+				assert.Equal(t, songIDs(all.Songs), backward)
+			})
+		}
+	}
+}
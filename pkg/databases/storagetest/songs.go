@@ -0,0 +1,249 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/pkg/databases/dberrors"
+	"testing"
+	"time"
+)
+
+var songTests = []test{
+	{name: "SaveSong and GetSong", run: testSaveSong},
+	{name: "SaveSong with missing references", run: testSaveSongMissingReferences},
+	{name: "UpdateSong", run: testUpdateSong},
+	{name: "ReplaceSong", run: testReplaceSong},
+	{name: "RemoveSong and RestoreSong", run: testRemoveSong},
+	{name: "Songs in the trash are not changed", run: testTrashedSongs},
+	{name: "PurgeTrash", run: testPurgeTrash},
+}
+
+func testSaveSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{
+		Song:        "Hysteria",
+		Group:       "  Muse ",
+		TrackNumber: 3,
+		ReleaseDate: entities.MustParseReleaseDate("2003-12"),
+		Text:        "It's bugging me",
+		Link:        "https://example.com/hysteria",
+	})
+
+	//get
+	song := getSong(t, s, id)
+	assert.Equal(t, id, song.ID)
+	assert.Equal(t, "Hysteria", song.Song)
+	assert.Equal(t, "Muse", song.Group)
+	assert.NotZero(t, song.GroupID)
+	assert.Equal(t, 3, song.TrackNumber)
+	assert.Equal(t, "2003-12", song.ReleaseDateString())
+	assert.Equal(t, "It's bugging me", song.Text)
+	assert.Equal(t, "https://example.com/hysteria", song.Link)
+	assert.Equal(t, int64(1), song.Version)
+
+	//the group is found by its name regardless of case and spaces
+	otherID := saveSong(t, s, entities.Song{Song: "Uprising", Group: "MUSE"})
+	assert.NotEqual(t, id, otherID)
+	assert.Equal(t, song.GroupID, getSong(t, s, otherID).GroupID)
+	assert.Equal(t, "Muse", getSong(t, s, otherID).Group)
+
+	//the song is given by value
+	song.Text = "changed"
+	assert.Equal(t, "It's bugging me", getSong(t, s, id).Text)
+
+	lyrics, err := s.GetSongLyrics(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "It's bugging me", lyrics)
+
+	_, err = s.GetSong(ctx, missingID)
+	assertNotFound(t, err)
+	_, err = s.GetSongLyrics(ctx, missingID)
+	assertNotFound(t, err)
+}
+
+func testSaveSongMissingReferences(t *testing.T, s Storage) {
+	ctx := context.Background()
+	_, err := s.SaveSong(ctx, entities.Song{Song: "Hysteria", GroupID: missingID})
+	assertInvalid(t, err)
+
+	albumID := uint64(missingID)
+	_, err = s.SaveSong(ctx, entities.Song{Song: "Hysteria", Group: "Muse", AlbumID: &albumID})
+	assertInvalid(t, err)
+
+	_, err = s.SaveSong(ctx, entities.Song{Song: "Hysteria", Group: " "})
+	assertInvalid(t, err)
+
+	//nothing was saved
+	_, err = s.GetGroupList(ctx, entities.Group{}, 0, 10)
+	assertNotFound(t, err)
+	_, err = s.GetSongList(ctx, entities.SongListQuery{})
+	assertNotFound(t, err)
+}
+
+func testUpdateSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", Text: "It's bugging me"})
+
+	//non-empty fields are updated
+	err := s.UpdateSong(ctx, entities.Song{ID: id, Link: "https://example.com/hysteria", Version: 1})
+	assert.NoError(t, err)
+	song := getSong(t, s, id)
+	assert.Equal(t, "Hysteria", song.Song)
+	assert.Equal(t, "It's bugging me", song.Text)
+	assert.Equal(t, "https://example.com/hysteria", song.Link)
+	assert.Equal(t, int64(2), song.Version)
+
+	//an update which changes nothing keeps the version
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Link: "https://example.com/hysteria"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), getSong(t, s, id).Version)
+
+	//a group name moves the song to the group
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Group: "Queen"})
+	assert.NoError(t, err)
+	song = getSong(t, s, id)
+	assert.Equal(t, "Queen", song.Group)
+	assert.Equal(t, int64(3), song.Version)
+
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Text: "Grating me", Version: 2})
+	assert.ErrorIs(t, err, dberrors.NewVersionMismatchErr())
+	assert.Equal(t, "It's bugging me", getSong(t, s, id).Text)
+
+	err = s.UpdateSong(ctx, entities.Song{ID: missingID, Text: "Grating me"})
+	assertNotFound(t, err)
+}
+
+func testReplaceSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{
+		Song:        "Hysteria",
+		Group:       "Muse",
+		TrackNumber: 3,
+		ReleaseDate: entities.MustParseReleaseDate("2003"),
+		Text:        "It's bugging me",
+		Link:        "https://example.com/hysteria",
+	})
+	groupID := getSong(t, s, id).GroupID
+
+	//empty fields are cleared
+	err := s.ReplaceSong(ctx, entities.Song{ID: id, Song: "Hysteria (Live)", GroupID: groupID, Text: "Grating me", Version: 1})
+	assert.NoError(t, err)
+	song := getSong(t, s, id)
+	assert.Equal(t, "Hysteria (Live)", song.Song)
+	assert.Equal(t, "Grating me", song.Text)
+	assert.Zero(t, song.TrackNumber)
+	assert.Nil(t, song.ReleaseDate)
+	assert.Empty(t, song.Link)
+	assert.Equal(t, int64(2), song.Version)
+
+	//a song without GroupID is moved to the group of its name
+	err = s.ReplaceSong(ctx, entities.Song{ID: id, Song: "Hysteria", Group: "Queen"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Queen", getSong(t, s, id).Group)
+
+	err = s.ReplaceSong(ctx, entities.Song{ID: id, Song: "Hysteria", GroupID: groupID, Version: 1})
+	assert.ErrorIs(t, err, dberrors.NewVersionMismatchErr())
+
+	err = s.ReplaceSong(ctx, entities.Song{ID: missingID, Song: "Hysteria", GroupID: groupID})
+	assertNotFound(t, err)
+}
+
+func testRemoveSong(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+	otherID := saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+
+	err := s.RemoveSong(ctx, id, 2)
+	assert.ErrorIs(t, err, dberrors.NewVersionMismatchErr())
+
+	//removed songs are hidden
+	err = s.RemoveSong(ctx, id, 1)
+	assert.NoError(t, err)
+	_, err = s.GetSong(ctx, id)
+	assertNotFound(t, err)
+	page, err := s.GetSongList(ctx, entities.SongListQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Uprising"}, songNames(page.Songs))
+	err = s.RemoveSong(ctx, id, 0)
+	assertNotFound(t, err)
+
+	//but they are in the trash, the latest removed go first
+	err = s.RemoveSong(ctx, otherID, 0)
+	assert.NoError(t, err)
+	trash, err := s.GetTrash(ctx, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, trash, 2) {
+		assert.Equal(t, otherID, trash[0].ID)
+		assert.Equal(t, "Muse", trash[0].Group)
+		assert.Equal(t, id, trash[1].ID)
+		assert.False(t, trash[1].DeletedAt.IsZero())
+		assert.False(t, trash[0].DeletedAt.Before(trash[1].DeletedAt))
+	}
+	trash, err = s.GetTrash(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+
+	//restore
+	err = s.RestoreSong(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), getSong(t, s, id).Version)
+	err = s.RestoreSong(ctx, id)
+	assertNotFound(t, err)
+	err = s.RestoreSong(ctx, missingID)
+	assertNotFound(t, err)
+	err = s.RestoreSong(ctx, otherID)
+	assert.NoError(t, err)
+	_, err = s.GetTrash(ctx, 0, 10)
+	assertNotFound(t, err)
+}
+
+func testTrashedSongs(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse", Text: "It's bugging me"})
+	groupID := getSong(t, s, id).GroupID
+	err := s.RemoveSong(ctx, id, 0)
+	assert.NoError(t, err)
+
+	_, err = s.GetSongLyrics(ctx, id)
+	assertNotFound(t, err)
+	err = s.UpdateSong(ctx, entities.Song{ID: id, Text: "Grating me"})
+	assertNotFound(t, err)
+	err = s.ReplaceSong(ctx, entities.Song{ID: id, Song: "Hysteria", GroupID: groupID})
+	assertNotFound(t, err)
+	err = s.EnqueueEnrichment(ctx, id)
+	assertNotFound(t, err)
+
+	//the song is restored as it was removed
+	err = s.RestoreSong(ctx, id)
+	assert.NoError(t, err)
+	song := getSong(t, s, id)
+	assert.Equal(t, "It's bugging me", song.Text)
+	assert.NotEqual(t, entities.EnrichmentPending, song.EnrichmentStatus)
+	assert.Equal(t, int64(3), song.Version)
+}
+
+func testPurgeTrash(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, entities.Song{Song: "Hysteria", Group: "Muse"})
+	saveSong(t, s, entities.Song{Song: "Uprising", Group: "Muse"})
+	err := s.RemoveSong(ctx, id, 0)
+	assert.NoError(t, err)
+
+	purged, err := s.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = s.PurgeTrash(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	_, err = s.GetTrash(ctx, 0, 10)
+	assertNotFound(t, err)
+	err = s.RestoreSong(ctx, id)
+	assertNotFound(t, err)
+
+	//songs which are not in the trash are kept
+	page, err := s.GetSongList(ctx, entities.SongListQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Uprising"}, songNames(page.Songs))
+}
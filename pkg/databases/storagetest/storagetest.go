@@ -0,0 +1,102 @@
+// Package storagetest is a conformance suite every storage backend must pass,
+// so handlers and services behave the same whichever backend is configured.
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"musiclib/internal/app/requiredinterfaces"
+	"musiclib/pkg/databases/dberrors"
+	"testing"
+)
+
+// Storage is everything a storage backend implements.
+type Storage interface {
+	requiredinterfaces.SongStorage
+	requiredinterfaces.EnrichmentJobStorage
+	requiredinterfaces.ExtraDataCacheStorage
+	requiredinterfaces.TrashStorage
+}
+
+// test is a case of the suite, it gets an empty storage.
+type test struct {
+	name string
+	run  func(t *testing.T, s Storage)
+}
+
+// Run runs the suite. newStorage must return an empty storage for every test, tests do not run in parallel.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	var tests []test
+	for _, group := range [][]test{songTests, songListTests, historyTests, groupTests, albumTests, enrichmentTests, searchTests, importExportTests, duplicateTests, unicodeTests, concurrencyTests} {
+		tests = append(tests, group...)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStorage(t))
+		})
+	}
+}
+
+// missingID is an ID no test creates.
+const missingID = 1 << 40
+
+// saveSong saves the song and returns its ID, the test stops if it fails.
+func saveSong(t *testing.T, s Storage, song entities.Song) uint64 {
+	t.Helper()
+	id, err := s.SaveSong(context.Background(), song)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return id
+}
+
+// getSong returns the song, the test stops if it is not found.
+func getSong(t *testing.T, s Storage, id uint64) entities.Song {
+	t.Helper()
+	song, err := s.GetSong(context.Background(), id)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return song
+}
+
+// saveGroup saves the group and returns its ID, the test stops if it fails.
+func saveGroup(t *testing.T, s Storage, group entities.Group) uint64 {
+	t.Helper()
+	id, err := s.SaveGroup(context.Background(), group)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return id
+}
+
+// assertNotFound checks that the error is the NotFound error of dberrors.
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	assert.ErrorIs(t, err, dberrors.NewNotFoundErr())
+}
+
+// assertInvalid checks that the storage refused content, like a reference to a missing group.
+func assertInvalid(t *testing.T, err error) {
+	t.Helper()
+	assert.Equal(t, dberrors.KindInvalid, dberrors.KindOf(err), "error: %v", err)
+}
+
+// songNames returns names of the songs in their order.
+func songNames(songs []entities.Song) []string {
+	names := make([]string, 0, len(songs))
+	for _, song := range songs {
+		names = append(names, song.Song)
+	}
+	return names
+}
+
+// songIDs returns IDs of the songs in their order.
+func songIDs(songs []entities.Song) []uint64 {
+	ids := make([]uint64, 0, len(songs))
+	for _, song := range songs {
+		ids = append(ids, song.ID)
+	}
+	return ids
+}
@@ -0,0 +1,92 @@
+package storagetest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"musiclib/internal/app/entities"
+	"testing"
+)
+
+var unicodeTests = []test{
+	{name: "Unicode names", run: testUnicodeNames},
+	{name: "Unicode lyrics", run: testUnicodeLyrics},
+}
+
+// unicodeSongs have names in other scripts and with diacritics, whose case is not changed by ASCII rules.
+var unicodeSongs = []entities.Song{
+	{Song: "Группа крови", Group: "Кино", Text: "Группа крови на рукаве\n\nМой порядковый номер на рукаве"},
+	{Song: "Звезда по имени Солнце", Group: "КИНО"},
+	{Song: "Schrei nach Liebe", Group: "Die Ärzte"},
+	{Song: "Crème Brûlée", Group: "Ñu"},
+}
+
+func testUnicodeNames(t *testing.T, s Storage) {
+	ctx := context.Background()
+	var ids []uint64
+	for _, song := range unicodeSongs {
+		ids = append(ids, saveSong(t, s, song))
+	}
+
+	//groups are found by their names in any case
+	assert.Equal(t, getSong(t, s, ids[0]).GroupID, getSong(t, s, ids[1]).GroupID)
+	assert.Equal(t, "Кино", getSong(t, s, ids[1]).Group)
+
+	tests := []struct {
+		name     string
+		filter   entities.SongFilter
+		expected []string
+	}{
+		{name: "Song contains", filter: entities.SongFilter{Song: "КРОВИ"}, expected: []string{"Группа крови"}},
+		{name: "Song prefix", filter: entities.SongFilter{Song: "crème b", SongMatch: entities.MatchPrefix}, expected: []string{"Crème Brûlée"}},
+		{name: "Song exact", filter: entities.SongFilter{Song: "CRÈME BRÛLÉE", SongMatch: entities.MatchExact}, expected: []string{"Crème Brûlée"}},
+		{name: "Diacritics are not ignored", filter: entities.SongFilter{Song: "creme"}},
+		{name: "Group exact", filter: entities.SongFilter{Group: "кино", GroupMatch: entities.MatchExact}, expected: []string{"Группа крови", "Звезда по имени Солнце"}},
+		{name: "Group contains", filter: entities.SongFilter{Group: "ärzte"}, expected: []string{"Schrei nach Liebe"}},
+		{name: "Groups", filter: entities.SongFilter{Groups: []string{"ñu", "die ärzte"}}, expected: []string{"Schrei nach Liebe", "Crème Brûlée"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.GetSongList(ctx, entities.SongListQuery{Filter: tt.filter})
+
+			if tt.expected == nil {
+				assertNotFound(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, songNames(page.Songs))
+		})
+	}
+
+	groups, err := s.GetGroupList(ctx, entities.Group{Name: "ÄRZTE"}, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 1) {
+		assert.Equal(t, "Die Ärzte", groups[0].Name)
+	}
+	albumID, err := s.SaveAlbum(ctx, entities.Album{Title: "Ночь", Group: "кино"})
+	assert.NoError(t, err)
+	albums, err := s.GetAlbumList(ctx, entities.Album{Title: "НОЧЬ", Group: "КИН"}, 0, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, albums, 1) {
+		assert.Equal(t, albumID, albums[0].ID)
+		assert.Equal(t, "Кино", albums[0].Group)
+	}
+}
+
+func testUnicodeLyrics(t *testing.T, s Storage) {
+	ctx := context.Background()
+	id := saveSong(t, s, unicodeSongs[0])
+	emojiID := saveSong(t, s, entities.Song{Song: "🎸", Group: "Muse", Text: "Ünïcödé 🎵 «текст» 漢字"})
+
+	//texts are kept byte by byte
+	lyrics, err := s.GetSongLyrics(ctx, emojiID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ünïcödé 🎵 «текст» 漢字", lyrics)
+	assert.Equal(t, "🎸", getSong(t, s, emojiID).Song)
+
+	results, err := s.SearchSongs(ctx, entities.SearchQuery{Text: "номер", Language: "russian"})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, id, results[0].Song.ID)
+		assert.Equal(t, "Мой порядковый <mark>номер</mark> на рукаве", results[0].Headline)
+	}
+}